@@ -1,11 +1,18 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"log"
+	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"tournament-app/internal/db"
 	"tournament-app/internal/router"
+	"tournament-app/service"
 
 	_ "tournament-app/docs" // this creates error on build and its necessary for Swagger to work
 
@@ -47,6 +54,15 @@ func init() {
 	}
 
 	db.InitRedis(0)
+
+	// Rebuild the Redis leaderboards from Postgres, e.g. after Redis lost its data
+	if os.Getenv("RECONCILE_LEADERBOARDS_ON_START") == "true" {
+		report, err := service.ReconcileLeaderboards(false)
+		if err != nil {
+			log.Fatalf("Failed to reconcile the leaderboards: %v", err)
+		}
+		log.Printf("Reconciled %d leaderboards: %d mismatches, %d rebuilt", report.Checked, len(report.Mismatches), report.Rebuilt)
+	}
 }
 
 func main() {
@@ -57,12 +73,40 @@ func main() {
 
 	router.UserRoutes(r)
 	router.TournamentRoutes(r)
+	router.MatchRoutes(r)
+	router.RankingRoutes(r)
+	router.MatchmakingRoutes(r)
+	router.TeamRoutes(r)
+	router.ClanRoutes(r)
+	router.SeasonRoutes(r)
+	router.TemplateRoutes(r)
 
 	// Swagger documentation route
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
-	// run on all interfaces
-	if err := r.Run("0.0.0.0:8080"); err != nil {
-		log.Fatalf("Failed to run server: %v", err)
+	// Match the players waiting in the matchmaking queue in the background
+	service.StartMatchmaking()
+
+	// Open, start and finalize the scheduled tournaments when their time comes
+	service.StartTournamentScheduler()
+
+	// run on all interfaces until the process is asked to stop
+	server := &http.Server{Addr: "0.0.0.0:8080", Handler: r}
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("Failed to run server: %v", err)
+		}
+	}()
+
+	stop, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+	<-stop.Done()
+
+	ctx, cancelShutdown := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancelShutdown()
+	if err := server.Shutdown(ctx); err != nil {
+		log.Printf("Failed to shut down server: %v", err)
 	}
+	service.StopMatchmaking()
+	service.StopTournamentScheduler()
 }
@@ -24,22 +24,24 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/clear-database": {
-            "post": {
-                "description": "Clear all data from the database",
+        "/clans": {
+            "get": {
+                "description": "Get all clans with their members",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "health"
+                    "clans"
                 ],
-                "summary": "Clear the database",
+                "summary": "Get all clans",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Clan"
+                            }
                         }
                     },
                     "500": {
@@ -50,21 +52,57 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/health": {
-            "get": {
-                "description": "Check the health status of the service",
+            },
+            "post": {
+                "description": "Create a clan led by a user who is not in a clan yet",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "health"
+                    "clans"
+                ],
+                "summary": "Create a clan",
+                "parameters": [
+                    {
+                        "description": "Clan",
+                        "name": "clan",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                " leader_id": {
+                                    "type": "integer"
+                                },
+                                " tag": {
+                                    "type": "string"
+                                },
+                                "name": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
                 ],
-                "summary": "Get health status",
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.Clan"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -80,68 +118,68 @@ const docTemplate = `{
                 }
             }
         },
-        "/leaderboard": {
-            "get": {
-                "description": "Get the leaderboard",
+        "/clans/invitations/{invitationId}/accept": {
+            "post": {
+                "description": "The invited user joins the clan as a member",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "leaderboard"
+                    "clans"
                 ],
-                "summary": "Get leaderboard",
+                "summary": "Accept a clan invitation",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Start",
-                        "name": "start",
-                        "in": "query"
+                        "description": "Invitation ID",
+                        "name": "invitationId",
+                        "in": "path",
+                        "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "Stop",
-                        "name": "stop",
-                        "in": "query"
+                        "description": "Invited user",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "user_id": {
+                                    "type": "integer"
+                                }
+                            }
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/model.User"
-                            }
+                            "$ref": "#/definitions/model.Clan"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
-                    }
-                }
-            }
-        },
-        "/leaderboard/active": {
-            "get": {
-                "description": "Get the active leaderboard",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "leaderboard"
-                ],
-                "summary": "Get active leaderboard",
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                    },
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/model.User"
-                            }
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
                     "500": {
@@ -154,33 +192,69 @@ const docTemplate = `{
                 }
             }
         },
-        "/leaderboard/tournament/{id}": {
-            "get": {
-                "description": "Get the leaderboard for a specific tournament",
+        "/clans/invitations/{invitationId}/decline": {
+            "post": {
+                "description": "The invited user turns the invitation down",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "leaderboard"
+                    "clans"
                 ],
-                "summary": "Get leaderboard by tournament ID",
+                "summary": "Decline a clan invitation",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Tournament ID",
-                        "name": "id",
+                        "description": "Invitation ID",
+                        "name": "invitationId",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Invited user",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "user_id": {
+                                    "type": "integer"
+                                }
+                            }
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/model.User"
-                            }
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
                     "500": {
@@ -193,23 +267,28 @@ const docTemplate = `{
                 }
             }
         },
-        "/leaderboard/tournament/{id}/active": {
+        "/clans/rankings": {
             "get": {
-                "description": "Get the active leaderboard for a specific tournament",
+                "description": "Get the clans ranked by the tournament points of their members, best first",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "leaderboard"
+                    "clans"
                 ],
-                "summary": "Get active leaderboard by tournament ID",
+                "summary": "Get the clan ranking",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Tournament ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Start",
+                        "name": "start",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Stop",
+                        "name": "stop",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -218,10 +297,17 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/model.User"
+                                "$ref": "#/definitions/model.ClanRanking"
                             }
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -232,20 +318,20 @@ const docTemplate = `{
                 }
             }
         },
-        "/leaderboard/tournament/{id}/finished": {
+        "/clans/{id}": {
             "get": {
-                "description": "Get the finished leaderboard for a specific tournament",
+                "description": "Get a clan with its members and their roles",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "leaderboard"
+                    "clans"
                 ],
-                "summary": "Get finished leaderboard by tournament ID",
+                "summary": "Get a clan by ID",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Tournament ID",
+                        "description": "Clan ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -255,10 +341,14 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/model.User"
-                            }
+                            "$ref": "#/definitions/model.Clan"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
                     "500": {
@@ -269,35 +359,69 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/leaderboard/user/{id}": {
-            "get": {
-                "description": "Get the leaderboard for a specific user",
+            },
+            "delete": {
+                "description": "Delete a clan with an empty treasury, only its leader can",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "leaderboard"
+                    "clans"
                 ],
-                "summary": "Get leaderboard by user ID",
+                "summary": "Delete a clan",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "User ID",
+                        "description": "Clan ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Leader",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "actor_id": {
+                                    "type": "integer"
+                                }
+                            }
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/model.User"
-                            }
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
                     "500": {
@@ -310,63 +434,71 @@ const docTemplate = `{
                 }
             }
         },
-        "/leaderboard/user/{id}/active": {
-            "get": {
-                "description": "Get the active leaderboard for a specific user",
+        "/clans/{id}/invitations": {
+            "post": {
+                "description": "The leader or an officer invites a user who is not in a clan",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "leaderboard"
+                    "clans"
                 ],
-                "summary": "Get active leaderboard by user ID",
+                "summary": "Invite a user to a clan",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "User ID",
+                        "description": "Clan ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Invitation",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                " user_id": {
+                                    "type": "integer"
+                                },
+                                "inviter_id": {
+                                    "type": "integer"
+                                }
+                            }
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/model.User"
-                            }
+                            "$ref": "#/definitions/model.ClanInvitation"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
-                    }
-                }
-            }
-        },
-        "/tournaments": {
-            "get": {
-                "description": "Get a list of all tournaments",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "tournaments"
-                ],
-                "summary": "Get all tournaments",
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                    },
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/model.Tournament"
-                            }
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
                     "500": {
@@ -377,9 +509,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/clans/{id}/leave": {
             "post": {
-                "description": "Create a new tournament with the input payload",
+                "description": "A member other than the leader leaves their clan",
                 "consumes": [
                     "application/json"
                 ],
@@ -387,33 +521,38 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "tournaments"
+                    "clans"
                 ],
-                "summary": "Create a new tournament",
+                "summary": "Leave a clan",
                 "parameters": [
                     {
-                        "description": "Tournament",
-                        "name": "tournament",
+                        "type": "integer",
+                        "description": "Clan ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Member",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
                             "type": "object",
                             "properties": {
-                                " prize": {
+                                "user_id": {
                                     "type": "integer"
-                                },
-                                "name": {
-                                    "type": "string"
                                 }
                             }
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.Tournament"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
                     "400": {
@@ -423,6 +562,13 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -433,9 +579,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/tournaments/join": {
-            "post": {
-                "description": "Join a tournament with the input payload",
+        "/clans/{id}/members/{userId}": {
+            "put": {
+                "description": "The leader makes a member an officer or a member, making them the leader hands over the leadership",
                 "consumes": [
                     "application/json"
                 ],
@@ -443,22 +589,36 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "tournaments"
+                    "clans"
                 ],
-                "summary": "Join a tournament",
+                "summary": "Change a clan member's role",
                 "parameters": [
                     {
-                        "description": "Join Request",
-                        "name": "joinRequest",
+                        "type": "integer",
+                        "description": "Clan ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Role",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
                             "type": "object",
                             "properties": {
-                                " user_id": {
-                                    "type": "integer"
+                                " role": {
+                                    "type": "string"
                                 },
-                                "tournament_id": {
+                                "actor_id": {
                                     "type": "integer"
                                 }
                             }
@@ -469,8 +629,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/model.Clan"
                         }
                     },
                     "400": {
@@ -480,35 +639,12 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
-                    }
-                }
-            }
-        },
-        "/tournaments/ongoing": {
-            "get": {
-                "description": "Get a list of ongoing tournaments",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "tournaments"
-                ],
-                "summary": "Get ongoing tournaments",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/model.Tournament"
-                            }
-                        }
                     },
                     "500": {
                         "description": "Internal Server Error",
@@ -518,32 +654,69 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/tournaments/{id}": {
-            "get": {
-                "description": "Get a tournament by its ID",
+            },
+            "delete": {
+                "description": "The leader removes anyone and an officer removes members from the clan",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "tournaments"
+                    "clans"
                 ],
-                "summary": "Get a tournament by ID",
+                "summary": "Remove a clan member",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Tournament ID",
+                        "description": "Clan ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Leader or officer",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "actor_id": {
+                                    "type": "integer"
+                                }
+                            }
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.Tournament"
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
                     "500": {
@@ -554,9 +727,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
-                "description": "Update a tournament by ID with the input payload",
+            }
+        },
+        "/clans/{id}/treasury/deposit": {
+            "post": {
+                "description": "A member moves money from their wallet into the clan treasury",
                 "consumes": [
                     "application/json"
                 ],
@@ -564,24 +739,32 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "tournaments"
+                    "clans"
                 ],
-                "summary": "Update a tournament",
+                "summary": "Deposit into a clan treasury",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Tournament ID",
+                        "description": "Clan ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Tournament",
-                        "name": "tournament",
+                        "description": "Deposit",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.Tournament"
+                            "type": "object",
+                            "properties": {
+                                " amount": {
+                                    "type": "integer"
+                                },
+                                "user_id": {
+                                    "type": "integer"
+                                }
+                            }
                         }
                     }
                 ],
@@ -589,7 +772,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.Tournament"
+                            "$ref": "#/definitions/model.Clan"
                         }
                     },
                     "400": {
@@ -607,31 +790,55 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Delete a tournament by ID",
+            }
+        },
+        "/clans/{id}/treasury/payout": {
+            "post": {
+                "description": "The leader pays money from the clan treasury to a member",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "tournaments"
+                    "clans"
                 ],
-                "summary": "Delete a tournament",
+                "summary": "Pay out of a clan treasury",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Tournament ID",
+                        "description": "Clan ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Payout",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                " amount": {
+                                    "type": "integer"
+                                },
+                                " user_id": {
+                                    "type": "integer"
+                                },
+                                "actor_id": {
+                                    "type": "integer"
+                                }
+                            }
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/model.Clan"
                         }
                     },
                     "400": {
@@ -641,6 +848,20 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -651,25 +872,16 @@ const docTemplate = `{
                 }
             }
         },
-        "/tournaments/{id}/end": {
+        "/clear-database": {
             "post": {
-                "description": "End a tournament by ID",
+                "description": "Clear all data from the database",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "tournaments"
-                ],
-                "summary": "End a tournament",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Tournament ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "health"
                 ],
+                "summary": "Clear the database",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -688,24 +900,22 @@ const docTemplate = `{
                 }
             }
         },
-        "/users": {
+        "/health": {
             "get": {
-                "description": "Get a list of all users",
+                "description": "Check the health status of the service",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "health"
                 ],
-                "summary": "Get all users",
+                "summary": "Get health status",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/model.User"
-                            }
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
                     "500": {
@@ -716,46 +926,51 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
-                "description": "Create a new user with the input payload",
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/leaderboard": {
+            "get": {
+                "description": "Get the leaderboard of a time window with the rank, name, level and avatar of every user, the daily and weekly boards start empty every UTC day and ISO week and rank what each score changed by since",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "leaderboard"
                 ],
-                "summary": "Create a new user",
+                "summary": "Get leaderboard",
                 "parameters": [
                     {
-                        "description": "User",
-                        "name": "user",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "type": "object",
-                            "properties": {
-                                " level": {
-                                    "type": "integer"
-                                },
-                                " money": {
-                                    "type": "integer"
-                                },
-                                "name": {
-                                    "type": "string"
-                                }
-                            }
-                        }
+                        "type": "integer",
+                        "description": "Start",
+                        "name": "start",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Stop",
+                        "name": "stop",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "all_time",
+                            "daily",
+                            "weekly"
+                        ],
+                        "type": "string",
+                        "description": "Time window",
+                        "name": "window",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.User"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.LeaderboardEntry"
+                            }
                         }
                     },
                     "400": {
@@ -775,30 +990,56 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/{id}": {
+        "/leaderboard/active": {
             "get": {
-                "description": "Get a user by their ID",
+                "description": "Get the active leaderboard of a time window",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "leaderboard"
                 ],
-                "summary": "Get a user by ID",
+                "summary": "Get active leaderboard",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "User ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Start",
+                        "name": "start",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Stop",
+                        "name": "stop",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "all_time",
+                            "daily",
+                            "weekly"
+                        ],
+                        "type": "string",
+                        "description": "Time window",
+                        "name": "window",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.User"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Leaderboard"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
                     "500": {
@@ -809,53 +1050,31 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
-                "description": "Update a user by ID with the input payload",
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/leaderboard/reconcile": {
+            "post": {
+                "description": "Compare the global leaderboard, the rating ranking and the leaderboards of running tournaments in Redis with Postgres and rebuild the boards that differ. With dry_run the mismatches are only reported.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "leaderboard"
                 ],
-                "summary": "Update a user",
+                "summary": "Reconcile the leaderboards",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "User ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "User",
-                        "name": "user",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "type": "object",
-                            "properties": {
-                                " level": {
-                                    "type": "integer"
-                                },
-                                " money": {
-                                    "type": "integer"
-                                },
-                                "name": {
-                                    "type": "string"
-                                }
-                            }
-                        }
+                        "type": "boolean",
+                        "description": "Only report the mismatches",
+                        "name": "dry_run",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.User"
+                            "$ref": "#/definitions/model.LeaderboardReconciliation"
                         }
                     },
                     "400": {
@@ -873,39 +1092,126 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Delete a user by ID",
+            }
+        },
+        "/leaderboard/tournament/{id}": {
+            "get": {
+                "description": "Get the live Redis leaderboard for a specific tournament with the rank, name, level and avatar of every user",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "leaderboard"
                 ],
-                "summary": "Delete a user",
+                "summary": "Get leaderboard by tournament ID",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "User ID",
+                        "description": "Tournament ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Start",
+                        "name": "start",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Stop",
+                        "name": "stop",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.LeaderboardEntry"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
+                    }
+                }
+            }
+        },
+        "/leaderboard/tournament/{id}/active": {
+            "get": {
+                "description": "Get the live leaderboard of a running tournament, empty once the tournament was finalized",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "leaderboard"
+                ],
+                "summary": "Get active leaderboard by tournament ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Tournament ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Leaderboard"
+                            }
+                        }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
+                    }
+                }
+            }
+        },
+        "/leaderboard/tournament/{id}/finished": {
+            "get": {
+                "description": "Get the finished leaderboard for a specific tournament",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "leaderboard"
+                ],
+                "summary": "Get finished leaderboard by tournament ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Tournament ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.User"
+                            }
+                        }
                     },
                     "500": {
                         "description": "Internal Server Error",
@@ -917,16 +1223,16 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/{id}/levelup": {
-            "post": {
-                "description": "Level up a user by ID",
+        "/leaderboard/user/{id}": {
+            "get": {
+                "description": "Get the leaderboard for a specific user",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "leaderboard"
                 ],
-                "summary": "Level up a user",
+                "summary": "Get leaderboard by user ID",
                 "parameters": [
                     {
                         "type": "integer",
@@ -940,8 +1246,10 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.User"
+                            }
                         }
                     },
                     "500": {
@@ -953,29 +1261,3616 @@ const docTemplate = `{
                     }
                 }
             }
-        }
+        },
+        "/leaderboard/user/{id}/active": {
+            "get": {
+                "description": "Get the user's entries on the leaderboards of the tournaments still running",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "leaderboard"
+                ],
+                "summary": "Get active leaderboard by user ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Leaderboard"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/leaderboard/user/{id}/around": {
+            "get": {
+                "description": "Get a user's entry with the entries up to radius ranks above and below it, on the same leaderboards as the rank lookup",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "leaderboard"
+                ],
+                "summary": "Get the leaderboard around a user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Entries above and below the user, at most 50",
+                        "name": "radius",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Tournament ID",
+                        "name": "tournament_id",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "all_time",
+                            "daily",
+                            "weekly"
+                        ],
+                        "type": "string",
+                        "description": "Time window of the global leaderboard",
+                        "name": "window",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.LeaderboardEntry"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/leaderboard/user/{id}/rank": {
+            "get": {
+                "description": "Get a user's rank, score and name on a tournament's live leaderboard, or on the global leaderboard of a time window when no tournament is given",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "leaderboard"
+                ],
+                "summary": "Get the rank of a user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Tournament ID",
+                        "name": "tournament_id",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "all_time",
+                            "daily",
+                            "weekly"
+                        ],
+                        "type": "string",
+                        "description": "Time window of the global leaderboard",
+                        "name": "window",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.LeaderboardEntry"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/matchmaking/matches/{matchId}/result": {
+            "post": {
+                "description": "Report the scores of a match made by the matchmaking queue, both players are rated from the result",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "matchmaking"
+                ],
+                "summary": "Report a matchmaking match result",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Match ID",
+                        "name": "matchId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Result",
+                        "name": "result",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                " player2_score": {
+                                    "type": "integer"
+                                },
+                                "player1_score": {
+                                    "type": "integer"
+                                }
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Match"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/matchmaking/queue": {
+            "post": {
+                "description": "Queue a user to be matched with an opponent of similar rating, the accepted rating difference grows while they wait",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "matchmaking"
+                ],
+                "summary": "Join the matchmaking queue",
+                "parameters": [
+                    {
+                        "description": "User",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "user_id": {
+                                    "type": "integer"
+                                }
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Take a user off the matchmaking queue",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "matchmaking"
+                ],
+                "summary": "Leave the matchmaking queue",
+                "parameters": [
+                    {
+                        "description": "User",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "user_id": {
+                                    "type": "integer"
+                                }
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/rankings": {
+            "get": {
+                "description": "Get the users ranked by their skill rating, best first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rankings"
+                ],
+                "summary": "Get the rating ranking",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Start",
+                        "name": "start",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Stop",
+                        "name": "stop",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Ranking"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/seasons": {
+            "get": {
+                "description": "Get all seasons, the latest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "seasons"
+                ],
+                "summary": "Get all seasons",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Season"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a season that does not overlap another active season. When it ends the next monthly season starts unless one was planned.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "seasons"
+                ],
+                "summary": "Create a season",
+                "parameters": [
+                    {
+                        "description": "Season",
+                        "name": "season",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                " ends_at": {
+                                    "type": "string"
+                                },
+                                " rating_soft_reset": {
+                                    "type": "number"
+                                },
+                                " starts_at": {
+                                    "type": "string"
+                                },
+                                "name": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.Season"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/seasons/current": {
+            "get": {
+                "description": "Get the season running now, the tournament scheduler rolls over the seasons that ended",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "seasons"
+                ],
+                "summary": "Get the current season",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Season"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/seasons/rollover": {
+            "post": {
+                "description": "Archive the seasons that ended: their final standings are saved, the rating soft reset is applied and the next monthly season is started",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "seasons"
+                ],
+                "summary": "Roll over the seasons",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Season"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/seasons/{id}": {
+            "get": {
+                "description": "Get a season with its dates and status",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "seasons"
+                ],
+                "summary": "Get a season by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Season ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Season"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/seasons/{id}/leaderboard": {
+            "get": {
+                "description": "Get the users ranked by the tournament points they made during the season, live while it runs and the archived final standings after it ended",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "seasons"
+                ],
+                "summary": "Get the leaderboard of a season",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Season ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Start",
+                        "name": "start",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Stop",
+                        "name": "stop",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.SeasonLeaderboard"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/teams": {
+            "get": {
+                "description": "Get all teams with their members",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "teams"
+                ],
+                "summary": "Get all teams",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Team"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a team led by its captain, who becomes its first member",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "teams"
+                ],
+                "summary": "Create a team",
+                "parameters": [
+                    {
+                        "description": "Team",
+                        "name": "team",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                " captain_id": {
+                                    "type": "integer"
+                                },
+                                " roster_size": {
+                                    "type": "integer"
+                                },
+                                "name": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.Team"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/teams/{id}": {
+            "get": {
+                "description": "Get a team with its members",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "teams"
+                ],
+                "summary": "Get a team by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Team ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Team"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a team that is not entered in an open or ongoing tournament",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "teams"
+                ],
+                "summary": "Delete a team",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Team ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/teams/{id}/members": {
+            "post": {
+                "description": "Put a user on a team with room on its roster, the roster of a team in an open or ongoing tournament is fixed",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "teams"
+                ],
+                "summary": "Add a team member",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Team ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Member",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "user_id": {
+                                    "type": "integer"
+                                }
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Team"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/teams/{id}/members/{userId}": {
+            "delete": {
+                "description": "Take a user other than the captain off a team that is not in an open or ongoing tournament",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "teams"
+                ],
+                "summary": "Remove a team member",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Team ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Team"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/tournament-templates": {
+            "get": {
+                "description": "Get all recurring tournament templates",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tournament-templates"
+                ],
+                "summary": "Get all tournament templates",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.TournamentTemplate"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a recurring event. The scheduler creates a tournament for every time the cron recurrence fires, create_ahead_minutes in advance. Its registration opens registration_minutes before the start and it is finalized duration_minutes after it.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tournament-templates"
+                ],
+                "summary": "Create a tournament template",
+                "parameters": [
+                    {
+                        "description": "Tournament template",
+                        "name": "template",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                " create_ahead_minutes": {
+                                    "type": "integer"
+                                },
+                                " duration_minutes": {
+                                    "type": "integer"
+                                },
+                                " entry_fee": {
+                                    "type": "integer"
+                                },
+                                " format": {
+                                    "type": "string"
+                                },
+                                " max_players": {
+                                    "type": "integer"
+                                },
+                                " min_players": {
+                                    "type": "integer"
+                                },
+                                " prize": {
+                                    "type": "integer"
+                                },
+                                " recurrence": {
+                                    "type": "string"
+                                },
+                                " registration_minutes": {
+                                    "type": "integer"
+                                },
+                                "name_pattern": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.TournamentTemplate"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/tournament-templates/{id}": {
+            "get": {
+                "description": "Get a recurring tournament template by its ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tournament-templates"
+                ],
+                "summary": "Get a tournament template by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Template ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.TournamentTemplate"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update a recurring tournament template, the settings left out are kept. Tournaments already created from it do not change.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tournament-templates"
+                ],
+                "summary": "Update a tournament template",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Template ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Template settings to change",
+                        "name": "template",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.TournamentTemplateUpdate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.TournamentTemplate"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a recurring tournament template, the tournaments created from it stay",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tournament-templates"
+                ],
+                "summary": "Delete a tournament template",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Template ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/tournament-templates/{id}/tournaments": {
+            "get": {
+                "description": "Get the tournaments created from a recurring template, the latest start first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tournament-templates"
+                ],
+                "summary": "Get the tournaments of a template",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Template ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Tournament"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/tournaments": {
+            "get": {
+                "description": "Get a list of all tournaments",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tournaments"
+                ],
+                "summary": "Get all tournaments",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Tournament"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a new tournament with the input payload. With a schedule its registration opens, it starts or is cancelled without min_players and it is finalized at the given times.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tournaments"
+                ],
+                "summary": "Create a new tournament",
+                "parameters": [
+                    {
+                        "description": "Tournament",
+                        "name": "tournament",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                " ends_at": {
+                                    "type": "string"
+                                },
+                                " entry_fee": {
+                                    "type": "integer"
+                                },
+                                " max_players": {
+                                    "type": "integer"
+                                },
+                                " min_players": {
+                                    "type": "integer"
+                                },
+                                " prize": {
+                                    "type": "integer"
+                                },
+                                " prize_strategy": {
+                                    "$ref": "#/definitions/model.PrizeStrategyConfig"
+                                },
+                                " registration_closes_at": {
+                                    "type": "string"
+                                },
+                                " registration_opens_at": {
+                                    "type": "string"
+                                },
+                                " starts_at": {
+                                    "type": "string"
+                                },
+                                "name": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.Tournament"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/tournaments/join": {
+            "post": {
+                "description": "Join a tournament with the input payload",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tournaments"
+                ],
+                "summary": "Join a tournament",
+                "parameters": [
+                    {
+                        "description": "Join Request",
+                        "name": "joinRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                " user_id": {
+                                    "type": "integer"
+                                },
+                                "tournament_id": {
+                                    "type": "integer"
+                                }
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/tournaments/ongoing": {
+            "get": {
+                "description": "Get a list of ongoing tournaments",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tournaments"
+                ],
+                "summary": "Get ongoing tournaments",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Tournament"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/tournaments/{id}": {
+            "get": {
+                "description": "Get a tournament by its ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tournaments"
+                ],
+                "summary": "Get a tournament by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Tournament ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Tournament"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update a tournament by ID with the input payload",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tournaments"
+                ],
+                "summary": "Update a tournament",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Tournament ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Tournament",
+                        "name": "tournament",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.Tournament"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Tournament"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a tournament by ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tournaments"
+                ],
+                "summary": "Delete a tournament",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Tournament ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/tournaments/{id}/bracket": {
+            "get": {
+                "description": "Get the elimination bracket of a tournament as trees rooted at the finals of the winners and losers brackets, followed by the grand final matches",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "matches"
+                ],
+                "summary": "Get the bracket of a tournament",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Tournament ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/service.Bracket"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/tournaments/{id}/cancel": {
+            "post": {
+                "description": "Cancel a tournament that has not finished yet, entry fees are refunded under the tournament's refund policy",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tournaments"
+                ],
+                "summary": "Cancel a tournament",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Tournament ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/tournaments/{id}/end": {
+            "post": {
+                "description": "Finalize an ongoing tournament by ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tournaments"
+                ],
+                "summary": "End a tournament",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Tournament ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/tournaments/{id}/groups": {
+            "get": {
+                "description": "Get the standings table of every group of a group stage tournament",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "matches"
+                ],
+                "summary": "Get the group standings of a tournament",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Tournament ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/service.GroupStandings"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/tournaments/{id}/leave": {
+            "post": {
+                "description": "Withdraw a user from a tournament while its registration is open, the entry fee is refunded under the tournament's refund policy",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tournaments"
+                ],
+                "summary": "Leave a tournament",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Tournament ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Leave Request",
+                        "name": "leaveRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "user_id": {
+                                    "type": "integer"
+                                }
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/tournaments/{id}/matches": {
+            "get": {
+                "description": "Get the matches of a tournament ordered by round",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "matches"
+                ],
+                "summary": "Get matches of a tournament",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Tournament ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Match"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a pending match between two players of an ongoing tournament",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "matches"
+                ],
+                "summary": "Create a match",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Tournament ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Match",
+                        "name": "match",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                " player2_id": {
+                                    "type": "integer"
+                                },
+                                " round": {
+                                    "type": "integer"
+                                },
+                                "player1_id": {
+                                    "type": "integer"
+                                }
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.Match"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/tournaments/{id}/matches/{matchId}/result": {
+            "post": {
+                "description": "Report the scores of a match, the higher score wins and equal scores are a draw",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "matches"
+                ],
+                "summary": "Report a match result",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Tournament ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Match ID",
+                        "name": "matchId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Result",
+                        "name": "result",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                " player2_score": {
+                                    "type": "integer"
+                                },
+                                "player1_score": {
+                                    "type": "integer"
+                                }
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Match"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/tournaments/{id}/open": {
+            "post": {
+                "description": "Open the registration of a planned tournament",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tournaments"
+                ],
+                "summary": "Open tournament registration",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Tournament ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/tournaments/{id}/payouts": {
+            "get": {
+                "description": "Preview the prize of each rank under the tournament's prize strategy",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tournaments"
+                ],
+                "summary": "Preview tournament payouts",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Tournament ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of ranked players, defaults to the joined users",
+                        "name": "players",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/service.Payout"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/tournaments/{id}/rounds/next": {
+            "post": {
+                "description": "Pair the next round of a swiss tournament once every match of the current round has a result",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "matches"
+                ],
+                "summary": "Pair the next round",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Tournament ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Match"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/tournaments/{id}/standings": {
+            "get": {
+                "description": "Get the standings table of a tournament with wins, draws, losses and points, ranked by points, head-to-head results and score difference, or by points, Buchholz and Sonneborn-Berger in a swiss tournament",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "matches"
+                ],
+                "summary": "Get the standings of a tournament",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Tournament ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Standing"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/tournaments/{id}/start": {
+            "post": {
+                "description": "Close the registration and start a tournament",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tournaments"
+                ],
+                "summary": "Start a tournament",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Tournament ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/tournaments/{id}/teams/join": {
+            "post": {
+                "description": "Enter a team with a full roster into a team tournament, the entry fee is split across the members or paid by the captain",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tournaments"
+                ],
+                "summary": "Enter a team into a tournament",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Tournament ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Team",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "team_id": {
+                                    "type": "integer"
+                                }
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/tournaments/{id}/teams/leave": {
+            "post": {
+                "description": "Withdraw a team from a team tournament while its registration is open, every member's share of the entry fee is refunded under the tournament's refund policy",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tournaments"
+                ],
+                "summary": "Withdraw a team from a tournament",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Tournament ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Team",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "team_id": {
+                                    "type": "integer"
+                                }
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/users": {
+            "get": {
+                "description": "Get a list of all users",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get all users",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.User"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a new user with the input payload",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Create a new user",
+                "parameters": [
+                    {
+                        "description": "User",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                " level": {
+                                    "type": "integer"
+                                },
+                                " money": {
+                                    "type": "integer"
+                                },
+                                "name": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/users/reconcile": {
+            "get": {
+                "description": "List the users whose money disagrees with their ledger sum",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Reconcile wallets",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.WalletMismatch"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}": {
+            "get": {
+                "description": "Get a user by their ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get a user by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.User"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update a user by ID with the input payload",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Update a user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "User",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                " level": {
+                                    "type": "integer"
+                                },
+                                " money": {
+                                    "type": "integer"
+                                },
+                                "name": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a user by ID, a clan member leaves their clan first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Delete a user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/clan-invitations": {
+            "get": {
+                "description": "Get the clan invitations a user has not answered yet",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "clans"
+                ],
+                "summary": "Get a user's clan invitations",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.ClanInvitation"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/levelup": {
+            "post": {
+                "description": "Level up a user by ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Level up a user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/matchmaking/matches": {
+            "get": {
+                "description": "List the matches the matchmaking queue made for a user, the newest first, to find the match ID to report the result of",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "matchmaking"
+                ],
+                "summary": "Get a user's matchmaking matches",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Match"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/ratings": {
+            "get": {
+                "description": "Get a user's skill rating after each of their rated matches, oldest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rankings"
+                ],
+                "summary": "Get the rating history of a user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.RatingHistory"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/transactions": {
+            "get": {
+                "description": "Get the wallet ledger of a user, oldest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get user transactions",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Transaction"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        }
     },
     "definitions": {
+        "model.BracketSide": {
+            "type": "string",
+            "enum": [
+                "winners",
+                "losers",
+                "grand_final"
+            ],
+            "x-enum-varnames": [
+                "WinnersBracket",
+                "LosersBracket",
+                "GrandFinalBracket"
+            ]
+        },
+        "model.Clan": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "members": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ClanMember"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "tag": {
+                    "type": "string"
+                },
+                "treasury": {
+                    "type": "integer",
+                    "minimum": 0
+                }
+            }
+        },
+        "model.ClanInvitation": {
+            "type": "object",
+            "properties": {
+                "clan_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "invited_by": {
+                    "type": "integer"
+                },
+                "status": {
+                    "$ref": "#/definitions/model.InvitationStatus"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.ClanMember": {
+            "type": "object",
+            "properties": {
+                "clan_id": {
+                    "type": "integer"
+                },
+                "joined_at": {
+                    "type": "string"
+                },
+                "role": {
+                    "$ref": "#/definitions/model.ClanRole"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.ClanRanking": {
+            "type": "object",
+            "properties": {
+                "clan_id": {
+                    "type": "integer"
+                },
+                "rank": {
+                    "type": "integer"
+                },
+                "score": {
+                    "type": "number"
+                }
+            }
+        },
+        "model.ClanRole": {
+            "type": "string",
+            "enum": [
+                "leader",
+                "officer",
+                "member"
+            ],
+            "x-enum-comments": {
+                "LeaderRole": "runs the clan, its members and its treasury, there is one per clan",
+                "OfficerRole": "invites users and removes members"
+            },
+            "x-enum-varnames": [
+                "LeaderRole",
+                "OfficerRole",
+                "MemberRole"
+            ]
+        },
+        "model.InvitationStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "accepted",
+                "declined"
+            ],
+            "x-enum-varnames": [
+                "InvitationPending",
+                "InvitationAccepted",
+                "InvitationDeclined"
+            ]
+        },
+        "model.Leaderboard": {
+            "type": "object",
+            "required": [
+                "status",
+                "tournament_id",
+                "user_id"
+            ],
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "score": {
+                    "type": "number",
+                    "minimum": 0
+                },
+                "season_id": {
+                    "description": "the season the tournament's points counted for",
+                    "type": "integer"
+                },
+                "status": {
+                    "$ref": "#/definitions/model.LeaderboardStatus"
+                },
+                "team_id": {
+                    "description": "team tournaments: the ranked team, UserID is its captain",
+                    "type": "integer"
+                },
+                "tournament_id": {
+                    "type": "integer"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.LeaderboardBoard": {
+            "type": "string",
+            "enum": [
+                "global",
+                "rating",
+                "tournament"
+            ],
+            "x-enum-comments": {
+                "GlobalBoard": "all users by the score of their level and money",
+                "RatingBoard": "all users by their skill rating",
+                "TournamentBoard": "the entrants of a running tournament by their points"
+            },
+            "x-enum-varnames": [
+                "GlobalBoard",
+                "RatingBoard",
+                "TournamentBoard"
+            ]
+        },
+        "model.LeaderboardEntry": {
+            "type": "object",
+            "properties": {
+                "avatar": {
+                    "description": "the user's profile picture, team entries have none",
+                    "type": "string"
+                },
+                "level": {
+                    "description": "the user's level, team entries have none",
+                    "type": "integer"
+                },
+                "name": {
+                    "description": "the user's name, or the team's name on a team tournament's board",
+                    "type": "string"
+                },
+                "rank": {
+                    "type": "integer"
+                },
+                "score": {
+                    "type": "number"
+                },
+                "team_id": {
+                    "description": "team tournaments: the ranked team, UserID is its captain",
+                    "type": "integer"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.LeaderboardMismatch": {
+            "type": "object",
+            "properties": {
+                "actual": {
+                    "type": "number"
+                },
+                "board": {
+                    "$ref": "#/definitions/model.LeaderboardBoard"
+                },
+                "expected": {
+                    "type": "number"
+                },
+                "tournament_id": {
+                    "type": "integer"
+                },
+                "user_id": {
+                    "description": "team tournaments: the team ID",
+                    "type": "integer"
+                }
+            }
+        },
+        "model.LeaderboardReconciliation": {
+            "type": "object",
+            "properties": {
+                "checked": {
+                    "description": "boards compared",
+                    "type": "integer"
+                },
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "mismatches": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.LeaderboardMismatch"
+                    }
+                },
+                "rebuilt": {
+                    "description": "boards rewritten from Postgres, always 0 in a dry run",
+                    "type": "integer"
+                }
+            }
+        },
+        "model.LeaderboardStatus": {
+            "type": "string",
+            "enum": [
+                "active",
+                "passive"
+            ],
+            "x-enum-varnames": [
+                "Active",
+                "Passive"
+            ]
+        },
+        "model.Match": {
+            "type": "object",
+            "properties": {
+                "bracket": {
+                    "$ref": "#/definitions/model.BracketSide"
+                },
+                "bye_slot": {
+                    "type": "integer"
+                },
+                "code": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "group": {
+                    "description": "group stage: the group the match is played in, 0 for the knockout",
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "loser_to": {
+                    "type": "string"
+                },
+                "loser_to_slot": {
+                    "type": "integer"
+                },
+                "player1_id": {
+                    "type": "integer"
+                },
+                "player1_score": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "player2_id": {
+                    "type": "integer"
+                },
+                "player2_score": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "position": {
+                    "type": "integer"
+                },
+                "round": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "status": {
+                    "$ref": "#/definitions/model.MatchStatus"
+                },
+                "tournament_id": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "winner_id": {
+                    "type": "integer"
+                },
+                "winner_to": {
+                    "type": "string"
+                },
+                "winner_to_slot": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.MatchStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "completed",
+                "skipped"
+            ],
+            "x-enum-comments": {
+                "MatchSkipped": "a bracket match that will never have players, e.g. an unneeded reset"
+            },
+            "x-enum-varnames": [
+                "MatchPending",
+                "MatchCompleted",
+                "MatchSkipped"
+            ]
+        },
+        "model.PrizeStrategyConfig": {
+            "type": "object",
+            "properties": {
+                "amounts": {
+                    "description": "fixed: money per rank",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "percentages": {
+                    "description": "percentage: share of the pool per rank",
+                    "type": "array",
+                    "items": {
+                        "type": "number"
+                    }
+                },
+                "top_n": {
+                    "description": "top_n_split: number of ranks sharing the pool",
+                    "type": "integer"
+                },
+                "type": {
+                    "$ref": "#/definitions/model.PrizeStrategyType"
+                }
+            }
+        },
+        "model.PrizeStrategyType": {
+            "type": "string",
+            "enum": [
+                "percentage",
+                "fixed",
+                "winner_takes_all",
+                "top_n_split"
+            ],
+            "x-enum-varnames": [
+                "PercentagePrize",
+                "FixedPrize",
+                "WinnerTakesAllPrize",
+                "TopNSplitPrize"
+            ]
+        },
+        "model.Ranking": {
+            "type": "object",
+            "properties": {
+                "rank": {
+                    "type": "integer"
+                },
+                "rating": {
+                    "type": "number"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.RatingHistory": {
+            "type": "object",
+            "properties": {
+                "change": {
+                    "type": "number"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "match_id": {
+                    "type": "integer"
+                },
+                "rating": {
+                    "type": "number"
+                },
+                "rating_deviation": {
+                    "type": "number"
+                },
+                "tournament_id": {
+                    "type": "integer"
+                },
+                "user_id": {
+                    "type": "integer"
+                },
+                "volatility": {
+                    "type": "number"
+                }
+            }
+        },
+        "model.RefundPolicy": {
+            "type": "string",
+            "enum": [
+                "before_start",
+                "full",
+                "none"
+            ],
+            "x-enum-comments": {
+                "RefundBeforeStart": "full refund until the tournament starts, none after"
+            },
+            "x-enum-varnames": [
+                "RefundBeforeStart",
+                "FullRefund",
+                "NoRefund"
+            ]
+        },
+        "model.Season": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "ends_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "rating_soft_reset": {
+                    "description": "RatingSoftReset is the share of the distance to the starting rating every rating loses when\nthe season ends, 0 keeps the ratings and 1 resets them",
+                    "type": "number",
+                    "maximum": 1,
+                    "minimum": 0
+                },
+                "starts_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/model.SeasonStatus"
+                }
+            }
+        },
+        "model.SeasonLeaderboard": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "rank": {
+                    "type": "integer"
+                },
+                "score": {
+                    "type": "number"
+                },
+                "season_id": {
+                    "type": "integer"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.SeasonStatus": {
+            "type": "string",
+            "enum": [
+                "active",
+                "archived"
+            ],
+            "x-enum-comments": {
+                "SeasonActive": "running or upcoming, its leaderboard lives in Redis",
+                "SeasonArchived": "over, its final standings are in Postgres"
+            },
+            "x-enum-varnames": [
+                "SeasonActive",
+                "SeasonArchived"
+            ]
+        },
+        "model.SeedingMethod": {
+            "type": "string",
+            "enum": [
+                "level",
+                "score"
+            ],
+            "x-enum-varnames": [
+                "SeedByLevel",
+                "SeedByScore"
+            ]
+        },
+        "model.Standing": {
+            "type": "object",
+            "properties": {
+                "buchholz": {
+                    "description": "Swiss tiebreakers: the points of all opponents, and of the beaten ones plus half of the drawn ones",
+                    "type": "integer"
+                },
+                "draws": {
+                    "type": "integer"
+                },
+                "group": {
+                    "description": "group stage: the group the rank is within",
+                    "type": "integer"
+                },
+                "losses": {
+                    "type": "integer"
+                },
+                "played": {
+                    "type": "integer"
+                },
+                "points": {
+                    "type": "integer"
+                },
+                "rank": {
+                    "type": "integer"
+                },
+                "score_against": {
+                    "type": "integer"
+                },
+                "score_for": {
+                    "type": "integer"
+                },
+                "sonneborn_berger": {
+                    "type": "number"
+                },
+                "user_id": {
+                    "type": "integer"
+                },
+                "wins": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.Team": {
+            "type": "object",
+            "required": [
+                "captain_id",
+                "name"
+            ],
+            "properties": {
+                "captain_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "members": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.User"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "roster_size": {
+                    "description": "most members the team can have, the captain included",
+                    "type": "integer",
+                    "minimum": 1
+                }
+            }
+        },
+        "model.TeamFeePolicy": {
+            "type": "string",
+            "enum": [
+                "split",
+                "captain"
+            ],
+            "x-enum-comments": {
+                "CaptainTeamFee": "the captain pays the whole fee",
+                "SplitTeamFee": "every member pays an equal share, the captain also pays what does not divide evenly"
+            },
+            "x-enum-varnames": [
+                "SplitTeamFee",
+                "CaptainTeamFee"
+            ]
+        },
         "model.Tournament": {
             "type": "object",
             "required": [
                 "name",
+                "prize",
                 "status"
             ],
             "properties": {
+                "advance_per_group": {
+                    "description": "group stage: top players of each group going into the knockout",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "ends_at": {
+                    "type": "string"
+                },
+                "entry_fee": {
+                    "description": "left out for the default fee, 0 for a free tournament",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "format": {
+                    "$ref": "#/definitions/model.TournamentFormat"
+                },
+                "grand_final_reset": {
+                    "description": "double elimination: replay the final if the losers bracket champion wins it",
+                    "type": "boolean"
+                },
+                "group_size": {
+                    "description": "group stage: players per group",
+                    "type": "integer",
+                    "minimum": 0
+                },
                 "id": {
                     "type": "integer"
                 },
+                "max_players": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "min_players": {
+                    "type": "integer",
+                    "minimum": 0
+                },
                 "name": {
                     "type": "string"
                 },
                 "prize": {
+                    "type": "integer"
+                },
+                "prize_strategy": {
+                    "$ref": "#/definitions/model.PrizeStrategyConfig"
+                },
+                "refund_policy": {
+                    "$ref": "#/definitions/model.RefundPolicy"
+                },
+                "registration_closes_at": {
+                    "type": "string"
+                },
+                "registration_opens_at": {
+                    "description": "The schedule is optional, the scheduler moves a tournament along the steps whose time is set:\nregistration opens, joining stops, the tournament starts or is cancelled without min_players, it is finalized.",
+                    "type": "string"
+                },
+                "rounds": {
+                    "description": "swiss: rounds to play, 0 plays enough rounds to leave a single unbeaten player",
                     "type": "integer",
                     "minimum": 0
                 },
+                "season_id": {
+                    "description": "the season running when the tournament was created",
+                    "type": "integer"
+                },
+                "seeding": {
+                    "$ref": "#/definitions/model.SeedingMethod"
+                },
+                "starts_at": {
+                    "type": "string"
+                },
                 "status": {
                     "$ref": "#/definitions/model.TournamentStatus"
                 },
+                "team_entry": {
+                    "description": "entered by teams instead of single players, max and min players count teams",
+                    "type": "boolean"
+                },
+                "team_fee": {
+                    "description": "team tournaments: who pays the entry fee of a team",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.TeamFeePolicy"
+                        }
+                    ]
+                },
+                "teams": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Team"
+                    }
+                },
+                "template_id": {
+                    "description": "the recurring template the tournament was created from",
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
                 "users": {
                     "type": "array",
                     "items": {
@@ -984,42 +4879,364 @@ const docTemplate = `{
                 }
             }
         },
+        "model.TournamentFormat": {
+            "type": "string",
+            "enum": [
+                "single_elimination",
+                "double_elimination",
+                "round_robin",
+                "swiss",
+                "group_stage"
+            ],
+            "x-enum-varnames": [
+                "SingleElimination",
+                "DoubleElimination",
+                "RoundRobin",
+                "Swiss",
+                "GroupStage"
+            ]
+        },
         "model.TournamentStatus": {
             "type": "string",
             "enum": [
                 "planned",
+                "registration_open",
                 "ongoing",
-                "finished"
+                "finished",
+                "cancelled"
             ],
             "x-enum-varnames": [
                 "Planned",
+                "RegistrationOpen",
                 "Ongoing",
-                "Finished"
+                "Finished",
+                "Cancelled"
             ]
         },
-        "model.User": {
+        "model.TournamentTemplate": {
             "type": "object",
             "required": [
-                "name"
+                "name_pattern",
+                "recurrence"
             ],
             "properties": {
+                "advance_per_group": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "create_ahead_minutes": {
+                    "description": "tournaments starting within this time are created",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "duration_minutes": {
+                    "description": "the tournament is finalized this long after the start",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "entry_fee": {
+                    "description": "left out for the default fee, 0 for free tournaments",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "format": {
+                    "$ref": "#/definitions/model.TournamentFormat"
+                },
+                "grand_final_reset": {
+                    "type": "boolean"
+                },
+                "group_size": {
+                    "type": "integer",
+                    "minimum": 0
+                },
                 "id": {
                     "type": "integer"
                 },
-                "level": {
+                "last_starts_at": {
+                    "description": "LastStartsAt is the start time of the latest tournament created, the next one starts after it",
+                    "type": "string"
+                },
+                "max_players": {
                     "type": "integer",
                     "minimum": 0
                 },
-                "money": {
+                "min_players": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "name_pattern": {
+                    "description": "NamePattern names the tournaments, {date} and {time} are replaced by the start date and time in UTC",
+                    "type": "string"
+                },
+                "paused": {
+                    "description": "no new tournaments are created while paused",
+                    "type": "boolean"
+                },
+                "prize": {
+                    "type": "integer"
+                },
+                "prize_strategy": {
+                    "$ref": "#/definitions/model.PrizeStrategyConfig"
+                },
+                "recurrence": {
+                    "description": "Recurrence is a cron expression of the start times, see ParseRecurrence",
+                    "type": "string"
+                },
+                "refund_policy": {
+                    "$ref": "#/definitions/model.RefundPolicy"
+                },
+                "registration_minutes": {
+                    "description": "registration opens this long before the start and closes at it",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "rounds": {
                     "type": "integer",
                     "minimum": 0
                 },
+                "seeding": {
+                    "$ref": "#/definitions/model.SeedingMethod"
+                },
+                "team_entry": {
+                    "type": "boolean"
+                },
+                "team_fee": {
+                    "$ref": "#/definitions/model.TeamFeePolicy"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.TournamentTemplateUpdate": {
+            "type": "object",
+            "properties": {
+                "advance_per_group": {
+                    "type": "integer"
+                },
+                "create_ahead_minutes": {
+                    "type": "integer"
+                },
+                "duration_minutes": {
+                    "type": "integer"
+                },
+                "entry_fee": {
+                    "type": "integer"
+                },
+                "format": {
+                    "$ref": "#/definitions/model.TournamentFormat"
+                },
+                "grand_final_reset": {
+                    "type": "boolean"
+                },
+                "group_size": {
+                    "type": "integer"
+                },
+                "max_players": {
+                    "type": "integer"
+                },
+                "min_players": {
+                    "type": "integer"
+                },
+                "name_pattern": {
+                    "type": "string"
+                },
+                "paused": {
+                    "type": "boolean"
+                },
+                "prize": {
+                    "type": "integer"
+                },
+                "prize_strategy": {
+                    "$ref": "#/definitions/model.PrizeStrategyConfig"
+                },
+                "recurrence": {
+                    "type": "string"
+                },
+                "refund_policy": {
+                    "$ref": "#/definitions/model.RefundPolicy"
+                },
+                "registration_minutes": {
+                    "type": "integer"
+                },
+                "rounds": {
+                    "type": "integer"
+                },
+                "seeding": {
+                    "$ref": "#/definitions/model.SeedingMethod"
+                },
+                "team_entry": {
+                    "type": "boolean"
+                },
+                "team_fee": {
+                    "$ref": "#/definitions/model.TeamFeePolicy"
+                }
+            }
+        },
+        "model.Transaction": {
+            "type": "object",
+            "required": [
+                "reason",
+                "user_id"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "integer"
+                },
+                "clan_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "$ref": "#/definitions/model.TransactionReason"
+                },
+                "tournament_id": {
+                    "type": "integer"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.TransactionReason": {
+            "type": "string",
+            "enum": [
+                "entry_fee",
+                "prize",
+                "level_up",
+                "refund",
+                "admin_adjust",
+                "clan_deposit",
+                "clan_payout"
+            ],
+            "x-enum-comments": {
+                "ClanDepositTransaction": "money put into a clan treasury",
+                "ClanPayoutTransaction": "money paid out of a clan treasury"
+            },
+            "x-enum-varnames": [
+                "EntryFeeTransaction",
+                "PrizeTransaction",
+                "LevelUpTransaction",
+                "RefundTransaction",
+                "AdminAdjustTransaction",
+                "ClanDepositTransaction",
+                "ClanPayoutTransaction"
+            ]
+        },
+        "model.User": {
+            "type": "object",
+            "required": [
+                "level",
+                "money",
+                "name"
+            ],
+            "properties": {
+                "avatar": {
+                    "description": "Avatar is the URL of the user's profile picture",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "level": {
+                    "type": "integer"
+                },
+                "money": {
+                    "type": "integer"
+                },
                 "name": {
                     "type": "string"
                 },
+                "rating": {
+                    "type": "number"
+                },
+                "rating_deviation": {
+                    "type": "number"
+                },
                 "score": {
                     "type": "number",
                     "minimum": 0
+                },
+                "volatility": {
+                    "type": "number"
+                }
+            }
+        },
+        "model.WalletMismatch": {
+            "type": "object",
+            "properties": {
+                "ledger_balance": {
+                    "type": "integer"
+                },
+                "money": {
+                    "type": "integer"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "service.Bracket": {
+            "type": "object",
+            "properties": {
+                "grand_final": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Match"
+                    }
+                },
+                "losers": {
+                    "$ref": "#/definitions/service.BracketNode"
+                },
+                "winners": {
+                    "$ref": "#/definitions/service.BracketNode"
+                }
+            }
+        },
+        "service.BracketNode": {
+            "type": "object",
+            "properties": {
+                "children": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.BracketNode"
+                    }
+                },
+                "match": {
+                    "$ref": "#/definitions/model.Match"
+                }
+            }
+        },
+        "service.GroupStandings": {
+            "type": "object",
+            "properties": {
+                "group": {
+                    "type": "integer"
+                },
+                "standings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Standing"
+                    }
+                }
+            }
+        },
+        "service.Payout": {
+            "type": "object",
+            "properties": {
+                "prize": {
+                    "type": "integer"
+                },
+                "rank": {
+                    "type": "integer"
                 }
             }
         }
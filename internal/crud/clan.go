@@ -0,0 +1,406 @@
+package crud
+
+import (
+	"tournament-app/internal/db"
+	"tournament-app/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CreateClan creates the clan with the user as its leader in a single transaction,
+// a user already in a clan fails on the clan_members primary key
+func CreateClan(clan *model.Clan, leaderID uint) error {
+	tx := db.DB.Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	var leader model.User
+	if err := tx.First(&leader, leaderID).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Omit(clause.Associations).Create(clan).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	member := model.ClanMember{UserID: leader.ID, ClanID: clan.ID, Role: model.LeaderRole}
+	if err := tx.Create(&member).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	clan.Members = []model.ClanMember{member}
+	return nil
+}
+
+func GetClanByID(id uint) (*model.Clan, error) {
+	var clan model.Clan
+	if err := db.DB.Preload("Members").First(&clan, id).Error; err != nil {
+		return nil, err
+	}
+	return &clan, nil
+}
+
+func GetClans() ([]model.Clan, error) {
+	var clans []model.Clan
+	if err := db.DB.Preload("Members").Order("id").Find(&clans).Error; err != nil {
+		return nil, err
+	}
+	return clans, nil
+}
+
+// GetClanMemberships returns the clan memberships of the given users, users without a clan are left out
+func GetClanMemberships(userIDs []uint) ([]model.ClanMember, error) {
+	var members []model.ClanMember
+	if len(userIDs) == 0 {
+		return members, nil
+	}
+	if err := db.DB.Where("user_id IN ?", userIDs).Find(&members).Error; err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+// GetPendingClanInvitations returns the invitations the user has not answered yet, oldest first
+func GetPendingClanInvitations(userID uint) ([]model.ClanInvitation, error) {
+	var invitations []model.ClanInvitation
+	if err := db.DB.Where("user_id = ? AND status = ?", userID, model.InvitationPending).Order("id").Find(&invitations).Error; err != nil {
+		return nil, err
+	}
+	return invitations, nil
+}
+
+// InviteToClan creates the invitation in a single transaction. The clan row is locked first,
+// then apply checks the clan, the invited user and their pending invitations to the clan.
+func InviteToClan(invitation *model.ClanInvitation, apply func(clan *model.Clan, user *model.User, pending []model.ClanInvitation) error) error {
+	tx := db.DB.Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	clan, err := lockClan(tx, invitation.ClanID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	var user model.User
+	if err := tx.First(&user, invitation.UserID).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	var pending []model.ClanInvitation
+	if err := tx.Where("clan_id = ? AND user_id = ? AND status = ?", clan.ID, user.ID, model.InvitationPending).Find(&pending).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := apply(clan, &user, pending); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Create(invitation).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return nil
+}
+
+// AnswerClanInvitation locks the invitation and its clan, lets apply answer it and saves the answer
+// in a single transaction. An accepted invitation makes the user a member of the clan,
+// a user already in a clan fails on the clan_members primary key.
+func AnswerClanInvitation(invitationID uint, apply func(invitation *model.ClanInvitation, clan *model.Clan) error) (*model.ClanInvitation, *model.Clan, error) {
+	tx := db.DB.Begin()
+	if tx.Error != nil {
+		return nil, nil, tx.Error
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	var invitation model.ClanInvitation
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&invitation, invitationID).Error; err != nil {
+		tx.Rollback()
+		return nil, nil, err
+	}
+	clan, err := lockClan(tx, invitation.ClanID)
+	if err != nil {
+		tx.Rollback()
+		return nil, nil, err
+	}
+
+	if err := apply(&invitation, clan); err != nil {
+		tx.Rollback()
+		return nil, nil, err
+	}
+	if err := tx.Save(&invitation).Error; err != nil {
+		tx.Rollback()
+		return nil, nil, err
+	}
+	if invitation.Status == model.InvitationAccepted {
+		member := model.ClanMember{UserID: invitation.UserID, ClanID: clan.ID, Role: model.MemberRole}
+		if err := tx.Create(&member).Error; err != nil {
+			tx.Rollback()
+			return nil, nil, err
+		}
+		clan.Members = append(clan.Members, member)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return nil, nil, err
+	}
+
+	return &invitation, clan, nil
+}
+
+// RemoveClanMember takes the user out of the clan in a single transaction, apply checks the locked clan first
+func RemoveClanMember(clanID, userID uint, apply func(clan *model.Clan) error) (*model.Clan, error) {
+	tx := db.DB.Begin()
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	clan, err := lockClan(tx, clanID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := apply(clan); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := tx.Where("clan_id = ? AND user_id = ?", clan.ID, userID).Delete(&model.ClanMember{}).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	members := clan.Members[:0]
+	for _, member := range clan.Members {
+		if member.UserID != userID {
+			members = append(members, member)
+		}
+	}
+	clan.Members = members
+	return clan, nil
+}
+
+// UpdateClanRoles locks the clan, lets apply change the roles of its members and saves the members
+// apply returns, all in a single transaction
+func UpdateClanRoles(clanID uint, apply func(clan *model.Clan) ([]model.ClanMember, error)) (*model.Clan, error) {
+	tx := db.DB.Begin()
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	clan, err := lockClan(tx, clanID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	changed, err := apply(clan)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	for _, member := range changed {
+		if err := tx.Model(&model.ClanMember{}).Where("user_id = ? AND clan_id = ?", member.UserID, clan.ID).Update("role", member.Role).Error; err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	return clan, nil
+}
+
+// ApplyClanTreasury moves money between a user's wallet and the clan treasury in a single transaction.
+// The clan and the user are locked in that order, apply changes both and the user's money difference
+// is written to their ledger with the given reason.
+func ApplyClanTreasury(clanID, userID uint, reason model.TransactionReason, apply func(clan *model.Clan, user *model.User) error) (*model.Clan, *model.User, error) {
+	tx := db.DB.Begin()
+	if tx.Error != nil {
+		return nil, nil, tx.Error
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	clan, err := lockClan(tx, clanID)
+	if err != nil {
+		tx.Rollback()
+		return nil, nil, err
+	}
+	var user model.User
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&user, userID).Error; err != nil {
+		tx.Rollback()
+		return nil, nil, err
+	}
+
+	before := user.Money
+	if err := apply(clan, &user); err != nil {
+		tx.Rollback()
+		return nil, nil, err
+	}
+
+	if err := tx.Model(clan).Update("treasury", clan.Treasury).Error; err != nil {
+		tx.Rollback()
+		return nil, nil, err
+	}
+	if err := tx.Save(&user).Error; err != nil {
+		tx.Rollback()
+		return nil, nil, err
+	}
+	if err := recordTransaction(tx, &model.Transaction{
+		UserID: user.ID,
+		Amount: user.Money - before,
+		Reason: reason,
+		ClanID: &clan.ID,
+	}); err != nil {
+		tx.Rollback()
+		return nil, nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return nil, nil, err
+	}
+
+	return clan, &user, nil
+}
+
+// DeleteClan deletes the clan with its memberships and invitations in a single transaction,
+// apply checks the locked clan first
+func DeleteClan(clanID uint, apply func(clan *model.Clan) error) (*model.Clan, error) {
+	tx := db.DB.Begin()
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	clan, err := lockClan(tx, clanID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := apply(clan); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := tx.Where("clan_id = ?", clan.ID).Delete(&model.ClanMember{}).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := tx.Where("clan_id = ?", clan.ID).Delete(&model.ClanInvitation{}).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := tx.Omit(clause.Associations).Delete(clan).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	return clan, nil
+}
+
+// lockClan locks the clan row and loads its members inside an open database transaction
+func lockClan(tx *gorm.DB, clanID uint) (*model.Clan, error) {
+	var clan model.Clan
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&clan, clanID).Error; err != nil {
+		return nil, err
+	}
+	if err := tx.Where("clan_id = ?", clan.ID).Order("joined_at, user_id").Find(&clan.Members).Error; err != nil {
+		return nil, err
+	}
+	return &clan, nil
+}
+
+// Redis-related functions will be retrieved from internal/db/clan.go
+
+// AddClanToLeaderboard puts a new clan on the clan leaderboard in Redis
+func AddClanToLeaderboard(clanID uint) error {
+	return db.AddClanToLeaderboard(clanID)
+}
+
+// RemoveClanFromLeaderboard takes a deleted clan off the clan leaderboard in Redis
+func RemoveClanFromLeaderboard(clanID uint) error {
+	return db.RemoveClanFromLeaderboard(clanID)
+}
+
+// AddClanMemberResults adds a new member's tournament points to their clan in Redis
+func AddClanMemberResults(clanID, userID uint) error {
+	return db.AddClanMemberResults(clanID, userID)
+}
+
+// RemoveClanMemberResults takes a leaving member's tournament points off their clan in Redis
+func RemoveClanMemberResults(clanID, userID uint) error {
+	return db.RemoveClanMemberResults(clanID, userID)
+}
+
+// AddTournamentResults adds the points of a finished tournament to its players, their clans
+// and the season leaderboard in Redis
+func AddTournamentResults(points map[uint]float64, clans map[uint]uint, seasonID uint) error {
+	return db.AddTournamentResults(points, clans, seasonID)
+}
+
+// GetClanLeaderboard retrieves the clan leaderboard from Redis
+func GetClanLeaderboard(start, stop int64) ([]model.ClanRanking, error) {
+	return db.GetClanLeaderboard(start, stop)
+}
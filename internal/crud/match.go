@@ -0,0 +1,160 @@
+package crud
+
+import (
+	"tournament-app/internal/db"
+	"tournament-app/model"
+
+	"gorm.io/gorm/clause"
+)
+
+func CreateMatch(match *model.Match) error {
+	tx := db.DB.Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Create(match).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return nil
+}
+
+func GetMatchByID(id uint) (*model.Match, error) {
+	var match model.Match
+	if err := db.DB.First(&match, id).Error; err != nil {
+		return nil, err
+	}
+	return &match, nil
+}
+
+func GetMatchesByTournamentID(tournamentID uint) ([]model.Match, error) {
+	var matches []model.Match
+	if err := db.DB.Where("tournament_id = ?", tournamentID).Order("round, id").Find(&matches).Error; err != nil {
+		return nil, err
+	}
+	return matches, nil
+}
+
+// ReportMatchResult locks the tournament and then all of its matches, lets apply record the result and saves
+// the matches it returns in a single transaction, so players moving on through a bracket never race and no result
+// slips in while the tournament is being finalized, which locks the tournament first too.
+// Returned matches without an ID are created, e.g. a playoff that starts with the result.
+func ReportMatchResult(tournamentID uint, apply func(tournament *model.Tournament, matches []model.Match) ([]model.Match, error)) ([]model.Match, error) {
+	tx := db.DB.Begin()
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	tournament, err := lockTournament(tx, tournamentID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	var matches []model.Match
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("tournament_id = ?", tournamentID).Order("id").Find(&matches).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	changed, err := apply(tournament, matches)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	for i := range changed {
+		if err := tx.Save(&changed[i]).Error; err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	return changed, nil
+}
+
+// CreateRound locks the tournament and its matches, lets pair build the next round from the
+// roster and the matches played so far, and creates the new matches in a single transaction,
+// so two requests can never open the same round twice.
+func CreateRound(tournamentID uint, pair func(tournament *model.Tournament, matches []model.Match) ([]model.Match, error)) ([]model.Match, error) {
+	tx := db.DB.Begin()
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	var tournament model.Tournament
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&tournament, tournamentID).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := tx.Model(&tournament).Association("Users").Find(&tournament.Users); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	teams, err := findTournamentTeams(tx, tournament.ID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	tournament.Teams = teams
+
+	var matches []model.Match
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("tournament_id = ?", tournamentID).Order("round, id").Find(&matches).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	round, err := pair(&tournament, matches)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if len(round) > 0 {
+		if err := tx.Create(&round).Error; err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	return round, nil
+}
+
+// IncrementTournamentLeaderboard adds points to a user's score on a tournament's leaderboard in Redis
+func IncrementTournamentLeaderboard(tournamentID uint, userID string, points float64) error {
+	return db.IncrementTournamentLeaderboard(tournamentID, userID, points)
+}
@@ -0,0 +1,78 @@
+package crud
+
+import (
+	"time"
+	"tournament-app/internal/db"
+	"tournament-app/model"
+
+	"gorm.io/gorm/clause"
+)
+
+// EnqueueMatchmaking puts a user on the matchmaking queue in Redis
+func EnqueueMatchmaking(entry model.QueueEntry) (bool, error) {
+	return db.EnqueueMatchmaking(entry)
+}
+
+// DequeueMatchmaking takes a user off the matchmaking queue in Redis
+func DequeueMatchmaking(userID uint) (bool, error) {
+	return db.DequeueMatchmaking(userID)
+}
+
+// GetMatchmakingQueue reads the matchmaking queue from Redis
+func GetMatchmakingQueue(now time.Time) ([]model.QueueEntry, error) {
+	return db.GetMatchmakingQueue(now)
+}
+
+// RemoveMatchedPair takes two matched users off the matchmaking queue in Redis
+func RemoveMatchedPair(userID1, userID2 uint) (bool, error) {
+	return db.RemoveMatchedPair(userID1, userID2)
+}
+
+// GetMatchmakingMatchesByUserID returns the matches made by the matchmaking queue that the user plays in,
+// the newest first
+func GetMatchmakingMatchesByUserID(userID uint) ([]model.Match, error) {
+	var matches []model.Match
+	if err := db.DB.Where("tournament_id = ? AND (player1_id = ? OR player2_id = ?)", 0, userID, userID).
+		Order("id DESC").Find(&matches).Error; err != nil {
+		return nil, err
+	}
+	return matches, nil
+}
+
+// ReportMatchmakingResult locks a match made by the matchmaking queue, lets apply record the result
+// and saves it in a single transaction
+func ReportMatchmakingResult(matchID uint, apply func(match *model.Match) error) (*model.Match, error) {
+	tx := db.DB.Begin()
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	var match model.Match
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("tournament_id = ?", 0).First(&match, matchID).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := apply(&match); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Save(&match).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	return &match, nil
+}
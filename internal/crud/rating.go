@@ -0,0 +1,93 @@
+package crud
+
+import (
+	"tournament-app/internal/db"
+	"tournament-app/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ApplyMatchRating locks both players of a match, lets rate change their ratings and saves them
+// with a rating history row each, all in one database transaction. The players are locked in ID
+// order so two matches of the same players never deadlock.
+func ApplyMatchRating(match *model.Match, rate func(player1, player2 *model.User) error) (*model.User, *model.User, error) {
+	tx := db.DB.Begin()
+	if tx.Error != nil {
+		return nil, nil, tx.Error
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	var players []model.User
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id IN ?", []uint{*match.Player1ID, *match.Player2ID}).Order("id").Find(&players).Error; err != nil {
+		tx.Rollback()
+		return nil, nil, err
+	}
+	if len(players) != 2 {
+		tx.Rollback()
+		return nil, nil, gorm.ErrRecordNotFound
+	}
+	player1, player2 := &players[0], &players[1]
+	if player1.ID != *match.Player1ID {
+		player1, player2 = player2, player1
+	}
+
+	before := map[uint]float64{player1.ID: player1.Rating, player2.ID: player2.Rating}
+	if err := rate(player1, player2); err != nil {
+		tx.Rollback()
+		return nil, nil, err
+	}
+
+	for _, player := range []*model.User{player1, player2} {
+		if err := tx.Model(player).Select("Rating", "RatingDeviation", "Volatility").Updates(player).Error; err != nil {
+			tx.Rollback()
+			return nil, nil, err
+		}
+		if err := tx.Create(&model.RatingHistory{
+			UserID:       player.ID,
+			MatchID:      match.ID,
+			TournamentID: match.TournamentID,
+			Change:       player.Rating - before[player.ID],
+			SkillRating:  player.SkillRating,
+		}).Error; err != nil {
+			tx.Rollback()
+			return nil, nil, err
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return nil, nil, err
+	}
+
+	return player1, player2, nil
+}
+
+// GetRatingHistory returns a user's ratings after each rated match, oldest first
+func GetRatingHistory(userID uint) ([]model.RatingHistory, error) {
+	var history []model.RatingHistory
+	if err := db.DB.Where("user_id = ?", userID).Order("id").Find(&history).Error; err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
+// UpdateRatingRanking sets a user's rating on the global rating ranking in Redis
+func UpdateRatingRanking(userID uint, rating float64) error {
+	return db.UpdateRatingRanking(userID, rating)
+}
+
+// RemoveFromRatingRanking drops a user from the global rating ranking in Redis
+func RemoveFromRatingRanking(userID uint) error {
+	return db.RemoveFromRatingRanking(userID)
+}
+
+// GetRatingRanking reads the global rating ranking from Redis, best first
+func GetRatingRanking(start, stop int64) ([]model.Ranking, error) {
+	return db.GetRatingRanking(start, stop)
+}
@@ -0,0 +1,277 @@
+package crud
+
+import (
+	"sort"
+	"time"
+	"tournament-app/internal/db"
+	"tournament-app/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CreateSeason creates the season in a single transaction. The seasons table is locked against
+// other writers so check sees every active season overlapping the new one.
+func CreateSeason(season *model.Season, check func(overlapping []model.Season) error) error {
+	tx := db.DB.Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Exec("LOCK TABLE seasons IN SHARE ROW EXCLUSIVE MODE").Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	var overlapping []model.Season
+	if err := tx.Where("status = ? AND starts_at < ? AND ends_at > ?", model.SeasonActive, season.EndsAt, season.StartsAt).
+		Find(&overlapping).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := check(overlapping); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Create(season).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return nil
+}
+
+func GetSeasonByID(id uint) (*model.Season, error) {
+	var season model.Season
+	if err := db.DB.First(&season, id).Error; err != nil {
+		return nil, err
+	}
+	return &season, nil
+}
+
+// GetSeasons returns all seasons, the latest first
+func GetSeasons() ([]model.Season, error) {
+	var seasons []model.Season
+	if err := db.DB.Order("starts_at DESC").Find(&seasons).Error; err != nil {
+		return nil, err
+	}
+	return seasons, nil
+}
+
+// GetSeasonAt returns the active season running at the given time, gorm.ErrRecordNotFound when there is none
+func GetSeasonAt(at time.Time) (*model.Season, error) {
+	var season model.Season
+	if err := db.DB.Where("status = ? AND starts_at <= ? AND ends_at > ?", model.SeasonActive, at, at).
+		Order("starts_at").First(&season).Error; err != nil {
+		return nil, err
+	}
+	return &season, nil
+}
+
+// GetEndedSeasons returns the seasons that are over at the given time but not archived yet, oldest first
+func GetEndedSeasons(at time.Time) ([]model.Season, error) {
+	var seasons []model.Season
+	if err := db.DB.Where("status = ? AND ends_at <= ?", model.SeasonActive, at).Order("ends_at").Find(&seasons).Error; err != nil {
+		return nil, err
+	}
+	return seasons, nil
+}
+
+// ArchiveSeason archives a season in a single transaction. The season row is locked first, apply gets it
+// with the active seasons starting after it and returns the final standings to store and the next season
+// to create, if any. The season's rating soft reset is applied to every user, the users are returned with
+// their new ratings.
+func ArchiveSeason(seasonID uint, apply func(season *model.Season, upcoming []model.Season) ([]model.SeasonLeaderboard, *model.Season, error)) (*model.Season, []model.User, error) {
+	tx := db.DB.Begin()
+	if tx.Error != nil {
+		return nil, nil, tx.Error
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	var season model.Season
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&season, seasonID).Error; err != nil {
+		tx.Rollback()
+		return nil, nil, err
+	}
+	var upcoming []model.Season
+	if err := tx.Where("status = ? AND starts_at >= ? AND id <> ?", model.SeasonActive, season.EndsAt, season.ID).
+		Order("starts_at").Find(&upcoming).Error; err != nil {
+		tx.Rollback()
+		return nil, nil, err
+	}
+
+	standings, next, err := apply(&season, upcoming)
+	if err != nil {
+		tx.Rollback()
+		return nil, nil, err
+	}
+
+	for _, standing := range standings {
+		standing.SeasonID = season.ID
+		if err := tx.Create(&standing).Error; err != nil {
+			tx.Rollback()
+			return nil, nil, err
+		}
+	}
+	season.Status = model.SeasonArchived
+	if err := tx.Save(&season).Error; err != nil {
+		tx.Rollback()
+		return nil, nil, err
+	}
+	if next != nil {
+		if err := tx.Create(next).Error; err != nil {
+			tx.Rollback()
+			return nil, nil, err
+		}
+	}
+
+	var users []model.User
+	if season.RatingSoftReset > 0 {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Order("id").Find(&users).Error; err != nil {
+			tx.Rollback()
+			return nil, nil, err
+		}
+		for i := range users {
+			users[i].SkillRating = users[i].SkillRating.SoftReset(season.RatingSoftReset)
+			if err := tx.Model(&users[i]).Updates(map[string]interface{}{
+				"rating":           users[i].Rating,
+				"rating_deviation": users[i].RatingDeviation,
+			}).Error; err != nil {
+				tx.Rollback()
+				return nil, nil, err
+			}
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return nil, nil, err
+	}
+
+	return &season, users, nil
+}
+
+// AddSeasonTournamentResults adds the points of a finished tournament like AddTournamentResults with the season's
+// row locked, so the season is not archived halfway. A season that is already archived gets the points added to
+// its final standings in Postgres, which are ranked again, its leaderboard in Redis is gone.
+func AddSeasonTournamentResults(points map[uint]float64, clans map[uint]uint, seasonID uint) error {
+	tx := db.DB.Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	var season model.Season
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&season, seasonID).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if season.Status == model.SeasonArchived {
+		if err := addArchivedSeasonPoints(tx, season.ID, points); err != nil {
+			tx.Rollback()
+			return err
+		}
+		seasonID = 0
+	}
+	if err := db.AddTournamentResults(points, clans, seasonID); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return nil
+}
+
+// addArchivedSeasonPoints adds points to the final standings of an archived season and ranks them again,
+// users level on points keep their order and users new to the standings follow them by user ID
+func addArchivedSeasonPoints(tx *gorm.DB, seasonID uint, points map[uint]float64) error {
+	var standings []model.SeasonLeaderboard
+	if err := tx.Where("season_id = ?", seasonID).Order("rank").Find(&standings).Error; err != nil {
+		return err
+	}
+	rows := make(map[uint]int, len(standings))
+	for i, standing := range standings {
+		rows[standing.UserID] = i
+	}
+
+	var added []uint
+	for userID, score := range points {
+		if i, ok := rows[userID]; ok {
+			standings[i].Score += score
+		} else {
+			added = append(added, userID)
+		}
+	}
+	sort.Slice(added, func(i, j int) bool { return added[i] < added[j] })
+	for _, userID := range added {
+		standings = append(standings, model.SeasonLeaderboard{SeasonID: seasonID, UserID: userID, Score: points[userID]})
+	}
+
+	sort.SliceStable(standings, func(i, j int) bool {
+		return standings[i].Score > standings[j].Score
+	})
+	for i := range standings {
+		standings[i].Rank = i + 1
+		if err := tx.Save(&standings[i]).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetArchivedSeasonLeaderboard returns the archived final standings of a season between the given ranks,
+// a negative stop reads to the end like a Redis range
+func GetArchivedSeasonLeaderboard(seasonID uint, start, stop int64) ([]model.SeasonLeaderboard, error) {
+	standings := []model.SeasonLeaderboard{}
+	if stop >= 0 && stop < start {
+		return standings, nil
+	}
+	query := db.DB.Where("season_id = ?", seasonID).Order("rank").Offset(int(start))
+	if stop >= 0 {
+		query = query.Limit(int(stop - start + 1))
+	}
+	if err := query.Find(&standings).Error; err != nil {
+		return nil, err
+	}
+	return standings, nil
+}
+
+// Redis-related functions will be retrieved from internal/db/season.go
+
+// GetSeasonLeaderboard retrieves a running season's leaderboard from Redis
+func GetSeasonLeaderboard(seasonID uint, start, stop int64) ([]model.SeasonLeaderboard, error) {
+	return db.GetSeasonLeaderboard(seasonID, start, stop)
+}
+
+// RemoveSeasonLeaderboard removes an archived season's leaderboard from Redis
+func RemoveSeasonLeaderboard(seasonID uint) error {
+	return db.RemoveSeasonLeaderboard(seasonID)
+}
+
+// SetRatingRankings sets the ratings of many users on the rating ranking in Redis
+func SetRatingRankings(ratings map[uint]float64) error {
+	return db.SetRatingRankings(ratings)
+}
@@ -0,0 +1,220 @@
+package crud
+
+import (
+	"tournament-app/internal/db"
+	"tournament-app/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CreateTeam creates the team with its captain as the first member in a single transaction
+func CreateTeam(team *model.Team) error {
+	tx := db.DB.Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	var captain model.User
+	if err := tx.First(&captain, team.CaptainID).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Omit(clause.Associations).Create(team).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Create(&model.TeamMember{TeamID: team.ID, UserID: captain.ID}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	team.Members = []model.User{captain}
+	return nil
+}
+
+func GetTeamByID(id uint) (*model.Team, error) {
+	var team model.Team
+	if err := db.DB.Preload("Members").First(&team, id).Error; err != nil {
+		return nil, err
+	}
+	return &team, nil
+}
+
+func GetTeams() ([]model.Team, error) {
+	var teams []model.Team
+	if err := db.DB.Preload("Members").Order("id").Find(&teams).Error; err != nil {
+		return nil, err
+	}
+	return teams, nil
+}
+
+// AddTeamMember adds the user to the team in a single transaction. The team row is locked first,
+// then apply checks the team, its members and the open or ongoing tournaments it is entered in.
+func AddTeamMember(teamID, userID uint, apply func(team *model.Team, user *model.User, entered []model.Tournament) error) (*model.Team, error) {
+	tx := db.DB.Begin()
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	team, entered, err := lockTeam(tx, teamID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	var user model.User
+	if err := tx.First(&user, userID).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := apply(team, &user, entered); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := tx.Create(&model.TeamMember{TeamID: team.ID, UserID: user.ID}).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	team.Members = append(team.Members, user)
+	return team, nil
+}
+
+// RemoveTeamMember removes the user from the team in a single transaction, apply checks the locked
+// team and the open or ongoing tournaments it is entered in first
+func RemoveTeamMember(teamID, userID uint, apply func(team *model.Team, entered []model.Tournament) error) (*model.Team, error) {
+	tx := db.DB.Begin()
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	team, entered, err := lockTeam(tx, teamID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := apply(team, entered); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := tx.Where("team_id = ? AND user_id = ?", team.ID, userID).Delete(&model.TeamMember{}).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	members := team.Members[:0]
+	for _, member := range team.Members {
+		if member.ID != userID {
+			members = append(members, member)
+		}
+	}
+	team.Members = members
+	return team, nil
+}
+
+// DeleteTeam deletes the team and its memberships in a single transaction, apply checks the locked
+// team and the open or ongoing tournaments it is entered in first
+func DeleteTeam(teamID uint, apply func(team *model.Team, entered []model.Tournament) error) error {
+	tx := db.DB.Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	team, entered, err := lockTeam(tx, teamID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := apply(team, entered); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Where("team_id = ?", team.ID).Delete(&model.TeamMember{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Where("team_id = ?", team.ID).Delete(&model.TournamentTeam{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Delete(team).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return nil
+}
+
+// lockTeam locks the team row and loads its members and the open or ongoing tournaments it is entered in
+// inside an open database transaction
+func lockTeam(tx *gorm.DB, teamID uint) (*model.Team, []model.Tournament, error) {
+	var team model.Team
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&team, teamID).Error; err != nil {
+		return nil, nil, err
+	}
+	if err := tx.Model(&team).Association("Members").Find(&team.Members); err != nil {
+		return nil, nil, err
+	}
+
+	var entered []model.Tournament
+	if err := tx.Select("tournaments.*").Joins("JOIN tournament_teams ON tournament_teams.tournament_id = tournaments.id").
+		Where("tournament_teams.team_id = ? AND tournaments.status IN ?", team.ID, []model.TournamentStatus{model.RegistrationOpen, model.Ongoing}).
+		Find(&entered).Error; err != nil {
+		return nil, nil, err
+	}
+	return &team, entered, nil
+}
+
+// GetTeamEntryMembers returns the IDs of the users who entered the tournament with the team
+func GetTeamEntryMembers(tournamentID, teamID uint) ([]uint, error) {
+	var userIDs []uint
+	if err := db.DB.Model(&model.TournamentUser{}).
+		Where("tournament_id = ? AND team_id = ?", tournamentID, teamID).
+		Order("user_id").Pluck("user_id", &userIDs).Error; err != nil {
+		return nil, err
+	}
+	return userIDs, nil
+}
@@ -0,0 +1,135 @@
+package crud
+
+import (
+	"tournament-app/internal/db"
+	"tournament-app/model"
+
+	"gorm.io/gorm/clause"
+)
+
+func CreateTournamentTemplate(template *model.TournamentTemplate) error {
+	return db.DB.Create(template).Error
+}
+
+func GetTournamentTemplateByID(id uint) (*model.TournamentTemplate, error) {
+	var template model.TournamentTemplate
+	if err := db.DB.First(&template, id).Error; err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
+func GetTournamentTemplates() ([]model.TournamentTemplate, error) {
+	var templates []model.TournamentTemplate
+	if err := db.DB.Order("id").Find(&templates).Error; err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+// GetRunningTournamentTemplates returns the templates that are not paused
+func GetRunningTournamentTemplates() ([]model.TournamentTemplate, error) {
+	var templates []model.TournamentTemplate
+	if err := db.DB.Where("paused = ?", false).Order("id").Find(&templates).Error; err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+// UpdateTournamentTemplate changes the template with apply in a single transaction. The template row is locked
+// first, so an update never writes back a last_starts_at the scheduler moved in the meantime.
+func UpdateTournamentTemplate(templateID uint, apply func(template *model.TournamentTemplate) error) (*model.TournamentTemplate, error) {
+	tx := db.DB.Begin()
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	var template model.TournamentTemplate
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&template, templateID).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := apply(&template); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := tx.Save(&template).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	return &template, nil
+}
+
+// DeleteTournamentTemplate deletes the template, the tournaments created from it keep its ID
+func DeleteTournamentTemplate(id uint) error {
+	return db.DB.Delete(&model.TournamentTemplate{}, id).Error
+}
+
+// GetTournamentsByTemplateID returns the tournaments created from a template, the latest start first
+func GetTournamentsByTemplateID(templateID uint) ([]model.Tournament, error) {
+	var tournaments []model.Tournament
+	if err := db.DB.Where("template_id = ?", templateID).Order("starts_at DESC").Find(&tournaments).Error; err != nil {
+		return nil, err
+	}
+	return tournaments, nil
+}
+
+// CreateTemplateTournaments creates the next tournaments of a template in a single transaction.
+// The template row is locked first so concurrent schedulers create every tournament once, then plan
+// returns the tournaments due from the locked template and moves its last_starts_at past them.
+func CreateTemplateTournaments(templateID uint, plan func(template *model.TournamentTemplate) ([]model.Tournament, error)) ([]model.Tournament, error) {
+	tx := db.DB.Begin()
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	var template model.TournamentTemplate
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&template, templateID).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	tournaments, err := plan(&template)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if len(tournaments) == 0 {
+		tx.Rollback()
+		return nil, nil
+	}
+	for i := range tournaments {
+		if err := tx.Create(&tournaments[i]).Error; err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+	if err := tx.Model(&template).Update("last_starts_at", template.LastStartsAt).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	return tournaments, nil
+}
@@ -1,8 +1,13 @@
 package crud
 
 import (
+	"sort"
+	"time"
 	"tournament-app/internal/db"
 	"tournament-app/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 func CreateTournament(tournament *model.Tournament) error {
@@ -37,9 +42,47 @@ func GetOngoingTournaments() ([]model.Tournament, error) {
 	return tournaments, nil
 }
 
+// GetLiveTournaments returns the tournaments keeping a live leaderboard in Redis, those open for registration
+// and the ongoing ones, with their players and teams
+func GetLiveTournaments() ([]model.Tournament, error) {
+	var tournaments []model.Tournament
+	if err := db.DB.Preload("Users").Preload("Teams.Members").
+		Where("status IN ?", []model.TournamentStatus{model.RegistrationOpen, model.Ongoing}).
+		Order("id").Find(&tournaments).Error; err != nil {
+		return nil, err
+	}
+	return tournaments, nil
+}
+
+// GetTournamentsToOpen returns the planned tournaments whose registration_opens_at has come
+func GetTournamentsToOpen(at time.Time) ([]model.Tournament, error) {
+	return getTournamentsDue([]model.TournamentStatus{model.Planned}, "registration_opens_at", at)
+}
+
+// GetTournamentsToStart returns the planned tournaments and the tournaments open for registration whose starts_at has come
+func GetTournamentsToStart(at time.Time) ([]model.Tournament, error) {
+	return getTournamentsDue([]model.TournamentStatus{model.Planned, model.RegistrationOpen}, "starts_at", at)
+}
+
+// GetTournamentsToEnd returns the ongoing tournaments whose ends_at has come
+func GetTournamentsToEnd(at time.Time) ([]model.Tournament, error) {
+	return getTournamentsDue([]model.TournamentStatus{model.Ongoing}, "ends_at", at)
+}
+
+// getTournamentsDue returns the tournaments in one of the statuses whose scheduled time in column is at or before at,
+// the earliest first
+func getTournamentsDue(statuses []model.TournamentStatus, column string, at time.Time) ([]model.Tournament, error) {
+	var tournaments []model.Tournament
+	if err := db.DB.Where("status IN ? AND "+column+" <= ?", statuses, at).
+		Order(column).Order("id").Find(&tournaments).Error; err != nil {
+		return nil, err
+	}
+	return tournaments, nil
+}
+
 func GetTournamentByID(id uint) (*model.Tournament, error) {
 	var tournament model.Tournament
-	if err := db.DB.Preload("Users").First(&tournament, id).Error; err != nil {
+	if err := db.DB.Preload("Users").Preload("Teams.Members").First(&tournament, id).Error; err != nil {
 		return nil, err
 	}
 	return &tournament, nil
@@ -69,6 +112,499 @@ func UpdateTournament(tournament *model.Tournament) error {
 	return nil
 }
 
+// JoinTournament adds the user to the tournament in a single transaction.
+// The tournament and user rows are locked first so concurrent joins run one after another,
+// then apply checks the locked rows and changes them (entry fee) before both are saved
+// and the entry fee is written to the user's ledger. start then sees the roster with the user
+// and may start the tournament, the opening matches it returns are created in the same transaction.
+func JoinTournament(tournamentID, userID uint, apply func(tournament *model.Tournament, user *model.User) error, start func(tournament *model.Tournament) ([]model.Match, error)) (*model.Tournament, *model.User, error) {
+	tx := db.DB.Begin()
+	if tx.Error != nil {
+		return nil, nil, tx.Error
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	var tournament model.Tournament
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&tournament, tournamentID).Error; err != nil {
+		tx.Rollback()
+		return nil, nil, err
+	}
+	if err := tx.Model(&tournament).Association("Users").Find(&tournament.Users); err != nil {
+		tx.Rollback()
+		return nil, nil, err
+	}
+
+	var user model.User
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&user, userID).Error; err != nil {
+		tx.Rollback()
+		return nil, nil, err
+	}
+
+	before := user.Money
+	if err := apply(&tournament, &user); err != nil {
+		tx.Rollback()
+		return nil, nil, err
+	}
+
+	if err := tx.Save(&user).Error; err != nil {
+		tx.Rollback()
+		return nil, nil, err
+	}
+	if err := recordTransaction(tx, &model.Transaction{
+		UserID:       user.ID,
+		Amount:       user.Money - before,
+		Reason:       model.EntryFeeTransaction,
+		TournamentID: &tournament.ID,
+	}); err != nil {
+		tx.Rollback()
+		return nil, nil, err
+	}
+	if err := tx.Create(&model.TournamentUser{TournamentID: tournament.ID, UserID: user.ID}).Error; err != nil {
+		tx.Rollback()
+		return nil, nil, err
+	}
+	tournament.Users = append(tournament.Users, user)
+	if err := startTournament(tx, &tournament, start); err != nil {
+		tx.Rollback()
+		return nil, nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return nil, nil, err
+	}
+
+	return &tournament, &user, nil
+}
+
+// LeaveTournament removes the user from the tournament in a single transaction.
+// The tournament and user rows are locked, then refund checks them and changes the user's money
+// given the entry fee they paid, the difference is written to the user's ledger as a refund.
+func LeaveTournament(tournamentID, userID uint, refund func(tournament *model.Tournament, user *model.User, paid int) error) (*model.Tournament, *model.User, error) {
+	tx := db.DB.Begin()
+	if tx.Error != nil {
+		return nil, nil, tx.Error
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	var tournament model.Tournament
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&tournament, tournamentID).Error; err != nil {
+		tx.Rollback()
+		return nil, nil, err
+	}
+	if err := tx.Model(&tournament).Association("Users").Find(&tournament.Users); err != nil {
+		tx.Rollback()
+		return nil, nil, err
+	}
+
+	var user model.User
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&user, userID).Error; err != nil {
+		tx.Rollback()
+		return nil, nil, err
+	}
+
+	if err := refundEntryFee(tx, &tournament, &user, refund); err != nil {
+		tx.Rollback()
+		return nil, nil, err
+	}
+	if err := tx.Where("tournament_id = ? AND user_id = ?", tournament.ID, user.ID).Delete(&model.TournamentUser{}).Error; err != nil {
+		tx.Rollback()
+		return nil, nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return nil, nil, err
+	}
+
+	return &tournament, &user, nil
+}
+
+// JoinTournamentAsTeam enters the team into the tournament in a single transaction.
+// The tournament, the team and its members are locked in that order, members by ID, then apply checks
+// the locked rows and changes them (entry fee). Every member is added to the tournament's roster
+// with their team and their share of the entry fee is written to their ledger. start then sees the
+// teams with the new one and may start the tournament, its opening matches are created in the same transaction.
+func JoinTournamentAsTeam(tournamentID, teamID uint, apply func(tournament *model.Tournament, team *model.Team) error, start func(tournament *model.Tournament) ([]model.Match, error)) (*model.Tournament, *model.Team, error) {
+	tx := db.DB.Begin()
+	if tx.Error != nil {
+		return nil, nil, tx.Error
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	var tournament model.Tournament
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&tournament, tournamentID).Error; err != nil {
+		tx.Rollback()
+		return nil, nil, err
+	}
+	teams, err := findTournamentTeams(tx, tournament.ID)
+	if err != nil {
+		tx.Rollback()
+		return nil, nil, err
+	}
+	tournament.Teams = teams
+
+	var team model.Team
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&team, teamID).Error; err != nil {
+		tx.Rollback()
+		return nil, nil, err
+	}
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("users.*").
+		Joins("JOIN team_members ON team_members.user_id = users.id").
+		Where("team_members.team_id = ?", team.ID).Order("users.id").Find(&team.Members).Error; err != nil {
+		tx.Rollback()
+		return nil, nil, err
+	}
+
+	before := make(map[uint]int, len(team.Members))
+	for _, member := range team.Members {
+		before[member.ID] = member.Money
+	}
+	if err := apply(&tournament, &team); err != nil {
+		tx.Rollback()
+		return nil, nil, err
+	}
+
+	for i := range team.Members {
+		member := &team.Members[i]
+		if err := tx.Save(member).Error; err != nil {
+			tx.Rollback()
+			return nil, nil, err
+		}
+		if err := recordTransaction(tx, &model.Transaction{
+			UserID:       member.ID,
+			Amount:       member.Money - before[member.ID],
+			Reason:       model.EntryFeeTransaction,
+			TournamentID: &tournament.ID,
+		}); err != nil {
+			tx.Rollback()
+			return nil, nil, err
+		}
+		if err := tx.Create(&model.TournamentUser{TournamentID: tournament.ID, UserID: member.ID, TeamID: &team.ID}).Error; err != nil {
+			tx.Rollback()
+			return nil, nil, err
+		}
+	}
+	if err := tx.Create(&model.TournamentTeam{TournamentID: tournament.ID, TeamID: team.ID}).Error; err != nil {
+		tx.Rollback()
+		return nil, nil, err
+	}
+	tournament.Teams = append(tournament.Teams, team)
+	if err := startTournament(tx, &tournament, start); err != nil {
+		tx.Rollback()
+		return nil, nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return nil, nil, err
+	}
+
+	return &tournament, &team, nil
+}
+
+// LeaveTournamentAsTeam withdraws the team from the tournament in a single transaction.
+// The tournament is locked and checked by check, then the members who entered with the team are locked
+// and refund changes each member's money given the share of the entry fee they paid,
+// the differences are written to their ledgers as refunds.
+func LeaveTournamentAsTeam(tournamentID, teamID uint, check func(tournament *model.Tournament) error, refund func(tournament *model.Tournament, user *model.User, paid int) error) (*model.Tournament, []model.User, error) {
+	tx := db.DB.Begin()
+	if tx.Error != nil {
+		return nil, nil, tx.Error
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	var tournament model.Tournament
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&tournament, tournamentID).Error; err != nil {
+		tx.Rollback()
+		return nil, nil, err
+	}
+	teams, err := findTournamentTeams(tx, tournament.ID)
+	if err != nil {
+		tx.Rollback()
+		return nil, nil, err
+	}
+	tournament.Teams = teams
+	if err := check(&tournament); err != nil {
+		tx.Rollback()
+		return nil, nil, err
+	}
+
+	var members []model.User
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("users.*").
+		Joins("JOIN tournament_users ON tournament_users.user_id = users.id").
+		Where("tournament_users.tournament_id = ? AND tournament_users.team_id = ?", tournament.ID, teamID).
+		Order("users.id").Find(&members).Error; err != nil {
+		tx.Rollback()
+		return nil, nil, err
+	}
+
+	for i := range members {
+		if err := refundEntryFee(tx, &tournament, &members[i], refund); err != nil {
+			tx.Rollback()
+			return nil, nil, err
+		}
+	}
+	if err := tx.Where("tournament_id = ? AND team_id = ?", tournament.ID, teamID).Delete(&model.TournamentUser{}).Error; err != nil {
+		tx.Rollback()
+		return nil, nil, err
+	}
+	if err := tx.Where("tournament_id = ? AND team_id = ?", tournament.ID, teamID).Delete(&model.TournamentTeam{}).Error; err != nil {
+		tx.Rollback()
+		return nil, nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return nil, nil, err
+	}
+
+	return &tournament, members, nil
+}
+
+// findTournamentTeams loads the teams entered into the tournament with their members inside an open database transaction
+func findTournamentTeams(tx *gorm.DB, tournamentID uint) ([]model.Team, error) {
+	var teams []model.Team
+	err := tx.Preload("Members").Select("teams.*").
+		Joins("JOIN tournament_teams ON tournament_teams.team_id = teams.id").
+		Where("tournament_teams.tournament_id = ?", tournamentID).Order("teams.id").Find(&teams).Error
+	return teams, err
+}
+
+// lockTournament locks the tournament row and loads its roster and teams inside an open database transaction
+func lockTournament(tx *gorm.DB, tournamentID uint) (*model.Tournament, error) {
+	var tournament model.Tournament
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&tournament, tournamentID).Error; err != nil {
+		return nil, err
+	}
+	if err := tx.Model(&tournament).Association("Users").Find(&tournament.Users); err != nil {
+		return nil, err
+	}
+	teams, err := findTournamentTeams(tx, tournament.ID)
+	if err != nil {
+		return nil, err
+	}
+	tournament.Teams = teams
+	return &tournament, nil
+}
+
+// StartTournament starts the tournament in a single transaction. The tournament row is locked first,
+// then start checks it, changes its status and returns its opening matches, which are created
+// together with the status change.
+func StartTournament(tournamentID uint, start func(tournament *model.Tournament) ([]model.Match, error)) (*model.Tournament, error) {
+	tx := db.DB.Begin()
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	tournament, err := lockTournament(tx, tournamentID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := startTournament(tx, tournament, start); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	return tournament, nil
+}
+
+// startTournament lets start change the locked tournament, then creates the matches it returns and saves
+// the tournament inside an open database transaction
+func startTournament(tx *gorm.DB, tournament *model.Tournament, start func(tournament *model.Tournament) ([]model.Match, error)) error {
+	matches, err := start(tournament)
+	if err != nil {
+		return err
+	}
+	if len(matches) > 0 {
+		if err := tx.Create(&matches).Error; err != nil {
+			return err
+		}
+	}
+	return tx.Omit(clause.Associations).Save(tournament).Error
+}
+
+// CancelTournament changes the tournament with apply and refunds every joined user in a single transaction.
+// The roster is kept, refund receives each locked user together with the entry fee they paid.
+func CancelTournament(tournamentID uint, apply func(tournament *model.Tournament) error, refund func(tournament *model.Tournament, user *model.User, paid int) error) (*model.Tournament, error) {
+	tx := db.DB.Begin()
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	var tournament model.Tournament
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&tournament, tournamentID).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := apply(&tournament); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := tx.Omit(clause.Associations).Save(&tournament).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	var userIDs []uint
+	if err := tx.Model(&model.TournamentUser{}).Where("tournament_id = ?", tournament.ID).Order("user_id").Pluck("user_id", &userIDs).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	for _, userID := range userIDs {
+		var user model.User
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&user, userID).Error; err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+		if err := refundEntryFee(tx, &tournament, &user, refund); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+		tournament.Users = append(tournament.Users, user)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	return &tournament, nil
+}
+
+// refundEntryFee looks up what the user paid to enter the tournament, lets refund change the
+// user's money and records the difference as a refund inside an open database transaction
+func refundEntryFee(tx *gorm.DB, tournament *model.Tournament, user *model.User, refund func(tournament *model.Tournament, user *model.User, paid int) error) error {
+	var balance int
+	if err := tx.Model(&model.Transaction{}).
+		Where("user_id = ? AND tournament_id = ? AND reason IN ?", user.ID, tournament.ID, []model.TransactionReason{model.EntryFeeTransaction, model.RefundTransaction}).
+		Select("COALESCE(SUM(amount), 0)").Scan(&balance).Error; err != nil {
+		return err
+	}
+
+	before := user.Money
+	if err := refund(tournament, user, -balance); err != nil {
+		return err
+	}
+	if err := tx.Save(user).Error; err != nil {
+		return err
+	}
+	return recordTransaction(tx, &model.Transaction{
+		UserID:       user.ID,
+		Amount:       user.Money - before,
+		Reason:       model.RefundTransaction,
+		TournamentID: &tournament.ID,
+	})
+}
+
+// FinalizeTournament finishes the tournament in a single transaction. The tournament row is locked first so a
+// tournament is finalized only once, then apply checks the locked tournament, changes its status and returns
+// its final leaderboard together with the prize of every user. The prizes are paid and written to the ledger,
+// the leaderboard is saved and the tournament is saved, so a failure on the way pays nothing.
+func FinalizeTournament(tournamentID uint, apply func(tournament *model.Tournament) ([]model.Leaderboard, map[uint]int, error)) (*model.Tournament, []model.Leaderboard, error) {
+	tx := db.DB.Begin()
+	if tx.Error != nil {
+		return nil, nil, tx.Error
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	tournament, err := lockTournament(tx, tournamentID)
+	if err != nil {
+		tx.Rollback()
+		return nil, nil, err
+	}
+
+	leaderboard, prizes, err := apply(tournament)
+	if err != nil {
+		tx.Rollback()
+		return nil, nil, err
+	}
+
+	// The winners are locked in ID order so concurrent transactions cannot deadlock on them
+	userIDs := make([]uint, 0, len(prizes))
+	for userID := range prizes {
+		userIDs = append(userIDs, userID)
+	}
+	sort.Slice(userIDs, func(i, j int) bool { return userIDs[i] < userIDs[j] })
+	for _, userID := range userIDs {
+		var user model.User
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&user, userID).Error; err != nil {
+			tx.Rollback()
+			return nil, nil, err
+		}
+		user.Money += prizes[userID]
+		if err := tx.Save(&user).Error; err != nil {
+			tx.Rollback()
+			return nil, nil, err
+		}
+		if err := recordTransaction(tx, &model.Transaction{
+			UserID:       user.ID,
+			Amount:       prizes[userID],
+			Reason:       model.PrizeTransaction,
+			TournamentID: &tournament.ID,
+		}); err != nil {
+			tx.Rollback()
+			return nil, nil, err
+		}
+	}
+
+	for i := range leaderboard {
+		leaderboard[i].TournamentID = tournament.ID
+		if err := tx.Create(&leaderboard[i]).Error; err != nil {
+			tx.Rollback()
+			return nil, nil, err
+		}
+	}
+	if err := tx.Omit(clause.Associations).Save(tournament).Error; err != nil {
+		tx.Rollback()
+		return nil, nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return nil, nil, err
+	}
+
+	return tournament, leaderboard, nil
+}
+
 func DeleteTournament(id uint) error {
 	tx := db.DB.Begin()
 	if tx.Error != nil {
@@ -80,6 +616,15 @@ func DeleteTournament(id uint) error {
 		}
 	}()
 
+	if err := tx.Where("tournament_id = ?", id).Delete(&model.TournamentUser{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Where("tournament_id = ?", id).Delete(&model.TournamentTeam{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
 	if err := tx.Delete(&model.Tournament{}, id).Error; err != nil {
 		tx.Rollback()
 		return err
@@ -101,14 +646,6 @@ func GetAllTournaments() ([]model.Tournament, error) {
 	return tournaments, nil
 }
 
-func GetTournamentByKey(key string) (*model.Tournament, error) {
-	var tournament model.Tournament
-	if err := db.DB.Where("key = ?", key).First(&tournament).Error; err != nil {
-		return nil, err
-	}
-	return &tournament, nil
-}
-
 func UpdateLeaderboardEntry(entry *model.Leaderboard) error {
 	tx := db.DB.Begin()
 	if tx.Error != nil {
@@ -182,17 +719,82 @@ func CreateLeaderboardEntry(entry *model.Leaderboard) error {
 	return db.CreateLeaderboardEntry(entry)
 }
 
-// GetLeaderboard retrieves the leaderboard from Redis
-func GetLeaderboard(start, stop int64) ([]model.Leaderboard, error) {
-	return db.GetLeaderboard(start, stop)
+// GetLeaderboard retrieves the global leaderboard of a time window from Redis
+func GetLeaderboard(window model.LeaderboardWindow, start, stop int64) ([]model.Leaderboard, error) {
+	return db.GetLeaderboard(window, start, stop)
+}
+
+// GetTournamentLeaderboard retrieves a tournament's leaderboard from Redis
+func GetTournamentLeaderboard(tournamentID uint, start, stop int64) ([]model.Leaderboard, error) {
+	return db.GetTournamentLeaderboard(tournamentID, start, stop)
+}
+
+// GetLeaderboardAround retrieves a member's neighbourhood on a tournament's or a global window's leaderboard from Redis
+func GetLeaderboardAround(tournamentID uint, window model.LeaderboardWindow, member uint, radius int64) ([]model.LeaderboardEntry, bool, error) {
+	return db.GetLeaderboardAround(tournamentID, window, member, radius)
+}
+
+// GetBoardScores retrieves every member of a live leaderboard with its score from Redis
+func GetBoardScores(board model.LeaderboardBoard, tournamentID uint) (map[uint]float64, error) {
+	return db.GetBoardScores(board, tournamentID)
 }
 
-// UpdateLeaderboard updates the leaderboard in Redis
+// ReplaceBoard rewrites a live leaderboard in Redis with the given scores
+func ReplaceBoard(board model.LeaderboardBoard, tournamentID uint, scores map[uint]float64) error {
+	return db.ReplaceBoard(board, tournamentID, scores)
+}
+
+// UpdateLeaderboard updates the global leaderboard in Redis
 func UpdateLeaderboard(userID string, score float64) error {
 	return db.UpdateLeaderboard(userID, score)
 }
 
-// RemoveLeaderboardFromRedis removes the leaderboard from Redis
+// RemoveFromLeaderboard removes a user from the global leaderboard in Redis
+func RemoveFromLeaderboard(userID string) error {
+	return db.RemoveFromLeaderboard(userID)
+}
+
+// UpdateTournamentLeaderboard updates a tournament's leaderboard in Redis
+func UpdateTournamentLeaderboard(tournamentID uint, userID string, score float64) error {
+	return db.UpdateTournamentLeaderboard(tournamentID, userID, score)
+}
+
+// RemoveFromTournamentLeaderboard removes a user from a tournament's leaderboard in Redis
+func RemoveFromTournamentLeaderboard(tournamentID uint, userID string) error {
+	return db.RemoveFromTournamentLeaderboard(tournamentID, userID)
+}
+
+// RemoveLeaderboardFromRedis removes a tournament's leaderboard from Redis
 func RemoveLeaderboardFromRedis(tournamentID uint) error {
 	return db.RemoveLeaderboardFromRedis(tournamentID)
 }
+
+// ArchiveTournamentLeaderboard marks a tournament's leaderboard in Redis passive and drops its live ranking
+func ArchiveTournamentLeaderboard(tournamentID uint) error {
+	return db.ArchiveTournamentLeaderboard(tournamentID)
+}
+
+// GetLeaderboardStatus retrieves the status of a tournament's leaderboard from Redis
+func GetLeaderboardStatus(tournamentID uint) (model.LeaderboardStatus, error) {
+	return db.GetLeaderboardStatus(tournamentID)
+}
+
+// GetLeaderboardStatuses retrieves the status of every tournament leaderboard from Redis
+func GetLeaderboardStatuses() (map[uint]model.LeaderboardStatus, error) {
+	return db.GetLeaderboardStatuses()
+}
+
+// GetTournamentScore retrieves a member's score on a tournament's leaderboard from Redis
+func GetTournamentScore(tournamentID, member uint) (float64, bool, error) {
+	return db.GetTournamentScore(tournamentID, member)
+}
+
+// SaveTournamentStandings stores a tournament's standings in Redis
+func SaveTournamentStandings(tournamentID uint, standings []model.Standing) error {
+	return db.SaveTournamentStandings(tournamentID, standings)
+}
+
+// GetTournamentStandings reads a tournament's standings from Redis
+func GetTournamentStandings(tournamentID uint) ([]model.Standing, error) {
+	return db.GetTournamentStandings(tournamentID)
+}
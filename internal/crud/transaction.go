@@ -0,0 +1,84 @@
+package crud
+
+import (
+	"tournament-app/internal/db"
+	"tournament-app/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ApplyTransaction locks the user, lets apply change it and records the money difference
+// as the given ledger transaction, all in one database transaction
+func ApplyTransaction(transaction *model.Transaction, apply func(user *model.User) error) (*model.User, error) {
+	tx := db.DB.Begin()
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	var user model.User
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&user, transaction.UserID).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	before := user.Money
+	if err := apply(&user); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Save(&user).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	transaction.Amount = user.Money - before
+	if err := recordTransaction(tx, transaction); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// recordTransaction writes a ledger row inside an open database transaction, zero amounts are skipped
+func recordTransaction(tx *gorm.DB, transaction *model.Transaction) error {
+	if transaction.Amount == 0 {
+		return nil
+	}
+	return tx.Create(transaction).Error
+}
+
+func GetTransactionsByUserID(userID uint) ([]model.Transaction, error) {
+	var transactions []model.Transaction
+	if err := db.DB.Where("user_id = ?", userID).Order("created_at, id").Find(&transactions).Error; err != nil {
+		return nil, err
+	}
+	return transactions, nil
+}
+
+// GetWalletMismatches lists the users whose Money is not equal to the sum of their ledger
+func GetWalletMismatches() ([]model.WalletMismatch, error) {
+	var mismatches []model.WalletMismatch
+	err := db.DB.Table("users").
+		Select("users.id AS user_id, users.money AS money, COALESCE(SUM(transactions.amount), 0) AS ledger_balance").
+		Joins("LEFT JOIN transactions ON transactions.user_id = users.id").
+		Group("users.id, users.money").
+		Having("users.money <> COALESCE(SUM(transactions.amount), 0)").
+		Order("users.id").
+		Scan(&mismatches).Error
+	if err != nil {
+		return nil, err
+	}
+	return mismatches, nil
+}
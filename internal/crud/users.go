@@ -38,6 +38,17 @@ func CreateUser(user *model.User) error {
 		return err
 	}
 
+	// The starting money opens the user's wallet ledger
+	if err := recordTransaction(tx, &model.Transaction{
+		UserID: user.ID,
+		Amount: user.Money,
+		Reason: model.AdminAdjustTransaction,
+	}); err != nil {
+		tx.Rollback()
+		log.Printf("Error recording opening balance: %v", err)
+		return err
+	}
+
 	if err := tx.Commit().Error; err != nil {
 		tx.Rollback()
 		log.Printf("Error committing transaction: %v", err)
@@ -90,6 +101,18 @@ func GetUsers() ([]model.User, error) {
 	return users, nil
 }
 
+// GetUsersByIDs returns the users with the given IDs in a single query, unknown IDs are left out
+func GetUsersByIDs(ids []uint) ([]model.User, error) {
+	var users []model.User
+	if len(ids) == 0 {
+		return users, nil
+	}
+	if err := db.DB.Where("id IN ?", ids).Find(&users).Error; err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
 func DeleteUser(id uint) error {
 	tx := db.DB.Begin()
 	if tx.Error != nil {
@@ -130,5 +153,33 @@ func ClearDatabase() error {
 		return err
 	}
 
+	if err := db.DB.Exec("TRUNCATE TABLE transactions RESTART IDENTITY CASCADE").Error; err != nil {
+		return err
+	}
+
+	if err := db.DB.Exec("TRUNCATE TABLE matches RESTART IDENTITY CASCADE").Error; err != nil {
+		return err
+	}
+
+	if err := db.DB.Exec("TRUNCATE TABLE rating_histories RESTART IDENTITY CASCADE").Error; err != nil {
+		return err
+	}
+
+	if err := db.DB.Exec("TRUNCATE TABLE teams RESTART IDENTITY CASCADE").Error; err != nil {
+		return err
+	}
+
+	if err := db.DB.Exec("TRUNCATE TABLE clans, clan_members, clan_invitations RESTART IDENTITY CASCADE").Error; err != nil {
+		return err
+	}
+
+	if err := db.DB.Exec("TRUNCATE TABLE seasons, season_leaderboards RESTART IDENTITY CASCADE").Error; err != nil {
+		return err
+	}
+
+	if err := db.DB.Exec("TRUNCATE TABLE tournament_templates RESTART IDENTITY CASCADE").Error; err != nil {
+		return err
+	}
+
 	return nil
 }
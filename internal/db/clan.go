@@ -0,0 +1,87 @@
+package db
+
+import (
+	"context"
+	"strconv"
+	"tournament-app/model"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// clanLeaderboardKey is the sorted set of clans by the tournament points of their members,
+// resultsKey the sorted set of every user's tournament points added up over all finished tournaments
+const (
+	clanLeaderboardKey = "leaderboard:clans"
+	resultsKey         = "leaderboard:results"
+)
+
+// moveResultsScript adds a user's tournament points to a clan, or takes them off it with a sign of -1
+var moveResultsScript = redis.NewScript(`
+local points = tonumber(redis.call("ZSCORE", KEYS[1], ARGV[1]) or "0")
+return redis.call("ZINCRBY", KEYS[2], points * tonumber(ARGV[2]), ARGV[3])
+`)
+
+// AddClanToLeaderboard puts a new clan on the clan leaderboard without points
+func AddClanToLeaderboard(clanID uint) error {
+	ctx := context.Background()
+	return rdb.ZAddNX(ctx, clanLeaderboardKey, &redis.Z{Score: 0, Member: strconv.FormatUint(uint64(clanID), 10)}).Err()
+}
+
+// RemoveClanFromLeaderboard takes a deleted clan off the clan leaderboard
+func RemoveClanFromLeaderboard(clanID uint) error {
+	ctx := context.Background()
+	return rdb.ZRem(ctx, clanLeaderboardKey, strconv.FormatUint(uint64(clanID), 10)).Err()
+}
+
+// AddClanMemberResults adds the tournament points the user collected so far to their new clan
+func AddClanMemberResults(clanID, userID uint) error {
+	return moveResults(clanID, userID, 1)
+}
+
+// RemoveClanMemberResults takes the tournament points of a leaving user off their clan
+func RemoveClanMemberResults(clanID, userID uint) error {
+	return moveResults(clanID, userID, -1)
+}
+
+func moveResults(clanID, userID uint, sign int) error {
+	ctx := context.Background()
+	return moveResultsScript.Run(ctx, rdb, []string{resultsKey, clanLeaderboardKey},
+		strconv.FormatUint(uint64(userID), 10), sign, strconv.FormatUint(uint64(clanID), 10)).Err()
+}
+
+// AddTournamentResults adds the points users made in a finished tournament to their results,
+// to the clans they belong to and to the leaderboard of the season, clans maps user IDs to clan IDs.
+// A season ID of 0 leaves the season leaderboards alone.
+func AddTournamentResults(points map[uint]float64, clans map[uint]uint, seasonID uint) error {
+	ctx := context.Background()
+	pipe := rdb.TxPipeline()
+	for userID, score := range points {
+		pipe.ZIncrBy(ctx, resultsKey, score, strconv.FormatUint(uint64(userID), 10))
+		if seasonID != 0 {
+			pipe.ZIncrBy(ctx, seasonLeaderboardKey(seasonID), score, strconv.FormatUint(uint64(userID), 10))
+		}
+		if clanID, ok := clans[userID]; ok {
+			pipe.ZIncrBy(ctx, clanLeaderboardKey, score, strconv.FormatUint(uint64(clanID), 10))
+		}
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// GetClanLeaderboard reads the clan leaderboard between the given ranks, best first
+func GetClanLeaderboard(start, stop int64) ([]model.ClanRanking, error) {
+	entries, err := getLeaderboardByKey(clanLeaderboardKey, 0, start, stop)
+	if err != nil {
+		return nil, err
+	}
+
+	rankings := make([]model.ClanRanking, 0, len(entries))
+	for i, entry := range entries {
+		rankings = append(rankings, model.ClanRanking{
+			Rank:   int(start) + i + 1,
+			ClanID: entry.UserID,
+			Score:  entry.Score,
+		})
+	}
+	return rankings, nil
+}
@@ -0,0 +1,97 @@
+package db
+
+import (
+	"context"
+	"strconv"
+	"time"
+	"tournament-app/model"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// matchmakingQueueKey is the sorted set of queued users by rating,
+// matchmakingJoinedKey the hash of the time each of them joined in unix nanoseconds
+const (
+	matchmakingQueueKey  = "matchmaking:queue"
+	matchmakingJoinedKey = "matchmaking:joined"
+)
+
+// removePairScript takes two users off the queue only if both are still queued
+var removePairScript = redis.NewScript(`
+if redis.call("ZSCORE", KEYS[1], ARGV[1]) and redis.call("ZSCORE", KEYS[1], ARGV[2]) then
+	redis.call("ZREM", KEYS[1], ARGV[1], ARGV[2])
+	redis.call("HDEL", KEYS[2], ARGV[1], ARGV[2])
+	return 1
+end
+return 0
+`)
+
+// EnqueueMatchmaking puts a user on the matchmaking queue, it reports false when the user was already queued
+func EnqueueMatchmaking(entry model.QueueEntry) (bool, error) {
+	ctx := context.Background()
+	member := strconv.FormatUint(uint64(entry.UserID), 10)
+
+	added, err := rdb.ZAddNX(ctx, matchmakingQueueKey, &redis.Z{Score: entry.Rating, Member: member}).Result()
+	if err != nil || added == 0 {
+		return false, err
+	}
+	if err := rdb.HSet(ctx, matchmakingJoinedKey, member, entry.JoinedAt.UnixNano()).Err(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// DequeueMatchmaking takes a user off the matchmaking queue, it reports false when the user was not queued
+func DequeueMatchmaking(userID uint) (bool, error) {
+	ctx := context.Background()
+	member := strconv.FormatUint(uint64(userID), 10)
+
+	pipe := rdb.TxPipeline()
+	removed := pipe.ZRem(ctx, matchmakingQueueKey, member)
+	pipe.HDel(ctx, matchmakingJoinedKey, member)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return false, err
+	}
+	return removed.Val() > 0, nil
+}
+
+// GetMatchmakingQueue reads every queued user, lowest rating first.
+// A user whose join time is missing counts as joined at now.
+func GetMatchmakingQueue(now time.Time) ([]model.QueueEntry, error) {
+	ctx := context.Background()
+	results, err := rdb.ZRangeWithScores(ctx, matchmakingQueueKey, 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+	joined, err := rdb.HGetAll(ctx, matchmakingJoinedKey).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	queue := make([]model.QueueEntry, 0, len(results))
+	for _, result := range results {
+		member, _ := result.Member.(string)
+		userID, err := strconv.ParseUint(member, 10, 64)
+		if err != nil {
+			continue
+		}
+		joinedAt := now
+		if nanos, err := strconv.ParseInt(joined[member], 10, 64); err == nil {
+			joinedAt = time.Unix(0, nanos)
+		}
+		queue = append(queue, model.QueueEntry{UserID: uint(userID), Rating: result.Score, JoinedAt: joinedAt})
+	}
+	return queue, nil
+}
+
+// RemoveMatchedPair takes two matched users off the queue together,
+// it reports false when one of them left the queue in the meantime
+func RemoveMatchedPair(userID1, userID2 uint) (bool, error) {
+	removed, err := removePairScript.Run(context.Background(), rdb,
+		[]string{matchmakingQueueKey, matchmakingJoinedKey},
+		strconv.FormatUint(uint64(userID1), 10), strconv.FormatUint(uint64(userID2), 10)).Int()
+	if err != nil {
+		return false, err
+	}
+	return removed == 1, nil
+}
@@ -13,7 +13,7 @@ var DB *gorm.DB
 // InitPostgres initializes the PostgreSQL database
 func InitPostgres(dsn string) error {
 	var err error
-	DB, err = gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	DB, err = gorm.Open(postgres.Open(dsn), &gorm.Config{TranslateError: true})
 	if err != nil {
 		log.Printf("Failed to connect to Postgres: %v", err)
 		return err
@@ -25,10 +25,41 @@ func InitPostgres(dsn string) error {
 	// if err != nil {
 	// 	return err
 
+	// tournament_users carries the join time and the unique (tournament, user) key
+	err = DB.SetupJoinTable(&model.Tournament{}, "Users", &model.TournamentUser{})
+	if err != nil {
+		log.Printf("Failed to set up tournament_users join table: %v", err)
+		return err
+	}
+
+	err = DB.SetupJoinTable(&model.Tournament{}, "Teams", &model.TournamentTeam{})
+	if err != nil {
+		log.Printf("Failed to set up tournament_teams join table: %v", err)
+		return err
+	}
+	err = DB.SetupJoinTable(&model.Team{}, "Members", &model.TeamMember{})
+	if err != nil {
+		log.Printf("Failed to set up team_members join table: %v", err)
+		return err
+	}
+
 	err = DB.AutoMigrate(
 		&model.User{},
 		&model.Tournament{},
+		&model.TournamentUser{},
 		&model.Leaderboard{},
+		&model.Transaction{},
+		&model.Match{},
+		&model.RatingHistory{},
+		&model.Team{},
+		&model.TeamMember{},
+		&model.TournamentTeam{},
+		&model.Clan{},
+		&model.ClanMember{},
+		&model.ClanInvitation{},
+		&model.Season{},
+		&model.SeasonLeaderboard{},
+		&model.TournamentTemplate{},
 	)
 
 	if err != nil {
@@ -36,5 +67,28 @@ func InitPostgres(dsn string) error {
 		return err
 	}
 
+	if err := backfillOpeningBalances(); err != nil {
+		log.Printf("Failed to backfill opening balances: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// backfillOpeningBalances opens the ledger of the wallets created before the ledger existed, so wallet
+// reconciliation does not flag them: a user with money but no ledger transaction gets an admin_adjust
+// transaction of their money. CreateUser opens every new wallet itself, so once a wallet has a transaction
+// it is left alone and running this on every start changes nothing twice.
+func backfillOpeningBalances() error {
+	result := DB.Exec(`INSERT INTO transactions (user_id, amount, reason, created_at)
+		SELECT users.id, users.money, ?, NOW() FROM users
+		WHERE users.money <> 0 AND NOT EXISTS (SELECT 1 FROM transactions WHERE transactions.user_id = users.id)`,
+		model.AdminAdjustTransaction)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		log.Printf("Backfilled the opening balance of %d wallets", result.RowsAffected)
+	}
 	return nil
 }
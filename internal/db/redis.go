@@ -2,9 +2,12 @@ package db
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"os"
+	"sort"
 	"strconv"
 	"sync"
 	"time"
@@ -46,12 +49,122 @@ func PingRedis() error {
 
 // Redis related functions
 
+// globalLeaderboardKey is the sorted set holding the ranking of all users
+const globalLeaderboardKey = "leaderboard:global"
+
+// leaderboardWindows are the time windows every global score update is written to next to the all-time board
+var leaderboardWindows = []model.LeaderboardWindow{model.DailyWindow, model.WeeklyWindow}
+
+// windowLeaderboardKey returns the key of the global leaderboard window running at the given time
+// and the time that window ends, the all-time board never ends
+func windowLeaderboardKey(window model.LeaderboardWindow, at time.Time) (string, time.Time) {
+	at = at.UTC()
+	switch window {
+	case model.DailyWindow:
+		start := time.Date(at.Year(), at.Month(), at.Day(), 0, 0, 0, 0, time.UTC)
+		return fmt.Sprintf("%s:daily:%s", globalLeaderboardKey, start.Format("2006-01-02")), start.AddDate(0, 0, 1)
+	case model.WeeklyWindow:
+		year, week := at.ISOWeek()
+		daysSinceMonday := (int(at.Weekday()) + 6) % 7
+		start := time.Date(at.Year(), at.Month(), at.Day()-daysSinceMonday, 0, 0, 0, 0, time.UTC)
+		return fmt.Sprintf("%s:weekly:%d-W%02d", globalLeaderboardKey, year, week), start.AddDate(0, 0, 7)
+	default:
+		return globalLeaderboardKey, time.Time{}
+	}
+}
+
+// leaderboardKey returns the key of a tournament's board, or of the global board of the window
+// when no tournament is given
+func leaderboardKey(tournamentID uint, window model.LeaderboardWindow) string {
+	if tournamentID != 0 {
+		return tournamentLeaderboardKey(tournamentID)
+	}
+	key, _ := windowLeaderboardKey(window, time.Now())
+	return key
+}
+
+// aroundScript reads a member's rank and the members up to a radius above and below it in one step,
+// it returns nil when the member is not on the board
+var aroundScript = redis.NewScript(`
+local rank = redis.call("ZREVRANK", KEYS[1], ARGV[1])
+if not rank then
+	return false
+end
+local start = math.max(rank - tonumber(ARGV[2]), 0)
+return {start, redis.call("ZREVRANGE", KEYS[1], start, rank + tonumber(ARGV[2]), "WITHSCORES")}
+`)
+
+// ratingRankingKey is the sorted set ranking all users by their skill rating
+const ratingRankingKey = "ranking:rating"
+
+// tournamentLeaderboardKey returns the sorted set key of a single tournament's ranking
+func tournamentLeaderboardKey(tournamentID uint) string {
+	return fmt.Sprintf("leaderboard:tournament:%d", tournamentID)
+}
+
+// tournamentStandingsKey returns the hash holding the standings rows of a tournament, keyed by user ID
+func tournamentStandingsKey(tournamentID uint) string {
+	return fmt.Sprintf("leaderboard:tournament:%d:standings", tournamentID)
+}
+
+// leaderboardStatusKey is the hash holding the status of every tournament leaderboard, keyed by tournament ID.
+// A tournament's board is active from its first entry until the tournament is finalized and passive after.
+const leaderboardStatusKey = "leaderboard:status"
+
+// markLeaderboardActive queues marking a tournament's board active, a passive board stays passive
+func markLeaderboardActive(ctx context.Context, pipe redis.Pipeliner, tournamentID uint) {
+	pipe.HSetNX(ctx, leaderboardStatusKey, strconv.FormatUint(uint64(tournamentID), 10), string(model.Active))
+}
+
+// GetLeaderboardStatus reads the status of a tournament's board, empty when the tournament has no board
+func GetLeaderboardStatus(tournamentID uint) (model.LeaderboardStatus, error) {
+	status, err := rdb.HGet(context.Background(), leaderboardStatusKey, strconv.FormatUint(uint64(tournamentID), 10)).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return model.LeaderboardStatus(status), nil
+}
+
+// GetLeaderboardStatuses reads the status of every tournament board
+func GetLeaderboardStatuses() (map[uint]model.LeaderboardStatus, error) {
+	fields, err := rdb.HGetAll(context.Background(), leaderboardStatusKey).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make(map[uint]model.LeaderboardStatus, len(fields))
+	for field, status := range fields {
+		tournamentID, err := strconv.ParseUint(field, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid tournament ID format")
+		}
+		statuses[uint(tournamentID)] = model.LeaderboardStatus(status)
+	}
+	return statuses, nil
+}
+
+// GetTournamentScore reads a member's score on a tournament's board, found is false when it is not on the board
+func GetTournamentScore(tournamentID, member uint) (float64, bool, error) {
+	score, err := rdb.ZScore(context.Background(), tournamentLeaderboardKey(tournamentID), strconv.FormatUint(uint64(member), 10)).Result()
+	if errors.Is(err, redis.Nil) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return score, true, nil
+}
+
 func CreateLeaderboardEntry(entry *model.Leaderboard) error {
 	ctx := context.Background()
 	pipe := rdb.TxPipeline()
 
 	score := float64(entry.Score)
-	pipe.ZAdd(ctx, "leaderboard", &redis.Z{Score: score, Member: entry.UserID})
+	pipe.ZAdd(ctx, tournamentLeaderboardKey(entry.TournamentID), &redis.Z{Score: score, Member: entry.UserID})
+	markLeaderboardActive(ctx, pipe, entry.TournamentID)
 
 	_, err := pipe.Exec(ctx)
 	if err != nil {
@@ -60,8 +173,41 @@ func CreateLeaderboardEntry(entry *model.Leaderboard) error {
 	return nil
 }
 
-func GetLeaderboard(start, stop int64) ([]model.Leaderboard, error) {
-	results, err := rdb.ZRevRangeWithScores(context.Background(), "leaderboard", start, stop).Result()
+// GetLeaderboard reads the global ranking of the given window, the daily and weekly windows
+// only hold the users whose score was updated since the window began, ranked by what it changed by
+func GetLeaderboard(window model.LeaderboardWindow, start, stop int64) ([]model.Leaderboard, error) {
+	key, _ := windowLeaderboardKey(window, time.Now())
+	leaderboard, err := getLeaderboardByKey(key, 0, start, stop)
+	if err != nil {
+		return nil, err
+	}
+	// The global boards are always live
+	return withStatus(leaderboard, model.Active), nil
+}
+
+// GetTournamentLeaderboard reads the ranking of a single tournament, the entries carry the status of its board
+func GetTournamentLeaderboard(tournamentID uint, start, stop int64) ([]model.Leaderboard, error) {
+	status, err := GetLeaderboardStatus(tournamentID)
+	if err != nil {
+		return nil, err
+	}
+	leaderboard, err := getLeaderboardByKey(tournamentLeaderboardKey(tournamentID), tournamentID, start, stop)
+	if err != nil {
+		return nil, err
+	}
+	return withStatus(leaderboard, status), nil
+}
+
+// withStatus sets the status of the board the entries were read from on every entry
+func withStatus(leaderboard []model.Leaderboard, status model.LeaderboardStatus) []model.Leaderboard {
+	for i := range leaderboard {
+		leaderboard[i].Status = status
+	}
+	return leaderboard
+}
+
+func getLeaderboardByKey(key string, tournamentID uint, start, stop int64) ([]model.Leaderboard, error) {
+	results, err := rdb.ZRevRangeWithScores(context.Background(), key, start, stop).Result()
 	if err != nil {
 		return nil, err
 	}
@@ -77,18 +223,127 @@ func GetLeaderboard(start, stop int64) ([]model.Leaderboard, error) {
 			return nil, fmt.Errorf("invalid user ID format")
 		}
 		leaderboard = append(leaderboard, model.Leaderboard{
-			UserID: uint(userID),
-			Score:  result.Score,
+			UserID:       uint(userID),
+			TournamentID: tournamentID,
+			Score:        result.Score,
 		})
 	}
 	return leaderboard, nil
 }
 
+// GetLeaderboardAround returns the member's entry on a tournament's board, or on the global board of the window
+// when no tournament is given, together with up to radius entries above and below it. The entries are ranked
+// but carry no names, found is false when the member is not on the board.
+func GetLeaderboardAround(tournamentID uint, window model.LeaderboardWindow, member uint, radius int64) ([]model.LeaderboardEntry, bool, error) {
+	result, err := aroundScript.Run(context.Background(), rdb, []string{leaderboardKey(tournamentID, window)},
+		strconv.FormatUint(uint64(member), 10), radius).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	reply, ok := result.([]interface{})
+	if !ok || len(reply) != 2 {
+		return nil, false, fmt.Errorf("unexpected leaderboard reply %v", result)
+	}
+	start, ok := reply[0].(int64)
+	if !ok {
+		return nil, false, fmt.Errorf("invalid leaderboard rank type")
+	}
+	values, ok := reply[1].([]interface{})
+	if !ok {
+		return nil, false, fmt.Errorf("invalid leaderboard range type")
+	}
+
+	entries := make([]model.LeaderboardEntry, 0, len(values)/2)
+	for i := 0; i+1 < len(values); i += 2 {
+		memberStr, _ := values[i].(string)
+		userID, err := strconv.ParseUint(memberStr, 10, 64)
+		if err != nil {
+			return nil, false, fmt.Errorf("invalid user ID format")
+		}
+		scoreStr, _ := values[i+1].(string)
+		score, err := strconv.ParseFloat(scoreStr, 64)
+		if err != nil {
+			return nil, false, fmt.Errorf("invalid score format")
+		}
+		entries = append(entries, model.LeaderboardEntry{
+			Rank:   int(start) + i/2 + 1,
+			UserID: uint(userID),
+			Score:  score,
+		})
+	}
+	return entries, true, nil
+}
+
+// updateLeaderboardScript sets a member's score on the all-time board in KEYS[1] and adds the change from its
+// previous score to the window boards in the other keys, each expiring at its end time in ARGV[3..].
+// A member new to the all-time board has no change yet.
+var updateLeaderboardScript = redis.NewScript(`
+local previous = redis.call("ZSCORE", KEYS[1], ARGV[1])
+local change = 0
+if previous then
+	change = tonumber(ARGV[2]) - tonumber(previous)
+end
+redis.call("ZADD", KEYS[1], ARGV[2], ARGV[1])
+for i = 2, #KEYS do
+	redis.call("ZINCRBY", KEYS[i], change, ARGV[1])
+	redis.call("EXPIREAT", KEYS[i], ARGV[i + 1])
+end
+return change
+`)
+
+// UpdateLeaderboard sets a user's score on the global ranking and adds what the score changed by to the boards
+// of the current windows in one step, so a window board ranks what users made during the window. A window board
+// expires when its window ends.
 func UpdateLeaderboard(userID string, score float64) error {
+	now := time.Now()
+	keys := []string{globalLeaderboardKey}
+	args := []interface{}{userID, score}
+	for _, window := range leaderboardWindows {
+		key, end := windowLeaderboardKey(window, now)
+		keys = append(keys, key)
+		args = append(args, end.Unix())
+	}
+
+	return updateLeaderboardScript.Run(context.Background(), rdb, keys, args...).Err()
+}
+
+// RemoveFromLeaderboard takes a user off the global ranking and the boards of the current windows
+func RemoveFromLeaderboard(userID string) error {
+	ctx := context.Background()
+	pipe := rdb.TxPipeline()
+
+	now := time.Now()
+	pipe.ZRem(ctx, globalLeaderboardKey, userID)
+	for _, window := range leaderboardWindows {
+		key, _ := windowLeaderboardKey(window, now)
+		pipe.ZRem(ctx, key, userID)
+	}
+
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// UpdateTournamentLeaderboard sets a user's score on a tournament's ranking
+func UpdateTournamentLeaderboard(tournamentID uint, userID string, score float64) error {
 	ctx := context.Background()
 	pipe := rdb.TxPipeline()
 
-	pipe.ZAdd(ctx, "leaderboard", &redis.Z{Score: score, Member: userID})
+	pipe.ZAdd(ctx, tournamentLeaderboardKey(tournamentID), &redis.Z{Score: score, Member: userID})
+	markLeaderboardActive(ctx, pipe, tournamentID)
+
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func updateLeaderboardByKey(key, userID string, score float64) error {
+	ctx := context.Background()
+	pipe := rdb.TxPipeline()
+
+	pipe.ZAdd(ctx, key, &redis.Z{Score: score, Member: userID})
 
 	_, err := pipe.Exec(ctx)
 	if err != nil {
@@ -97,11 +352,103 @@ func UpdateLeaderboard(userID string, score float64) error {
 	return nil
 }
 
+// UpdateRatingRanking sets a user's rating on the global rating ranking
+func UpdateRatingRanking(userID uint, rating float64) error {
+	return updateLeaderboardByKey(ratingRankingKey, strconv.FormatUint(uint64(userID), 10), rating)
+}
+
+// RemoveFromRatingRanking drops a user from the global rating ranking
+func RemoveFromRatingRanking(userID uint) error {
+	return rdb.ZRem(context.Background(), ratingRankingKey, strconv.FormatUint(uint64(userID), 10)).Err()
+}
+
+// GetRatingRanking reads the global rating ranking, best first
+func GetRatingRanking(start, stop int64) ([]model.Ranking, error) {
+	entries, err := getLeaderboardByKey(ratingRankingKey, 0, start, stop)
+	if err != nil {
+		return nil, err
+	}
+
+	ranking := make([]model.Ranking, 0, len(entries))
+	for i, entry := range entries {
+		ranking = append(ranking, model.Ranking{
+			Rank:   int(start) + i + 1,
+			UserID: entry.UserID,
+			Rating: entry.Score,
+		})
+	}
+	return ranking, nil
+}
+
+// IncrementTournamentLeaderboard adds points to a user's score on a tournament's ranking
+func IncrementTournamentLeaderboard(tournamentID uint, userID string, points float64) error {
+	ctx := context.Background()
+	pipe := rdb.TxPipeline()
+
+	pipe.ZIncrBy(ctx, tournamentLeaderboardKey(tournamentID), points, userID)
+	markLeaderboardActive(ctx, pipe, tournamentID)
+
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// RemoveFromTournamentLeaderboard drops a user from a tournament's ranking
+func RemoveFromTournamentLeaderboard(tournamentID uint, userID string) error {
+	return rdb.ZRem(context.Background(), tournamentLeaderboardKey(tournamentID), userID).Err()
+}
+
+// SaveTournamentStandings replaces a tournament's standings rows and sets every player's points on its ranking
+func SaveTournamentStandings(tournamentID uint, standings []model.Standing) error {
+	ctx := context.Background()
+	pipe := rdb.TxPipeline()
+
+	pipe.Del(ctx, tournamentStandingsKey(tournamentID))
+	for _, standing := range standings {
+		row, err := json.Marshal(standing)
+		if err != nil {
+			return err
+		}
+		member := strconv.FormatUint(uint64(standing.UserID), 10)
+		pipe.HSet(ctx, tournamentStandingsKey(tournamentID), member, row)
+		pipe.ZAdd(ctx, tournamentLeaderboardKey(tournamentID), &redis.Z{Score: float64(standing.Points), Member: member})
+	}
+	markLeaderboardActive(ctx, pipe, tournamentID)
+
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// GetTournamentStandings reads a tournament's standings rows ordered by group and rank
+func GetTournamentStandings(tournamentID uint) ([]model.Standing, error) {
+	rows, err := rdb.HGetAll(context.Background(), tournamentStandingsKey(tournamentID)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	standings := make([]model.Standing, 0, len(rows))
+	for _, row := range rows {
+		var standing model.Standing
+		if err := json.Unmarshal([]byte(row), &standing); err != nil {
+			return nil, fmt.Errorf("invalid standings row: %v", err)
+		}
+		standings = append(standings, standing)
+	}
+	sort.Slice(standings, func(i, j int) bool {
+		if standings[i].Group != standings[j].Group {
+			return standings[i].Group < standings[j].Group
+		}
+		return standings[i].Rank < standings[j].Rank
+	})
+	return standings, nil
+}
+
 func RemoveLeaderboardFromRedis(tournamentID uint) error {
 	ctx := context.Background()
 	pipe := rdb.TxPipeline()
 
-	pipe.Del(ctx, fmt.Sprintf("leaderboard:%d", tournamentID))
+	pipe.Del(ctx, tournamentLeaderboardKey(tournamentID))
+	pipe.Del(ctx, tournamentStandingsKey(tournamentID))
+	pipe.HDel(ctx, leaderboardStatusKey, strconv.FormatUint(uint64(tournamentID), 10))
 
 	_, err := pipe.Exec(ctx)
 	if err != nil {
@@ -109,3 +456,73 @@ func RemoveLeaderboardFromRedis(tournamentID uint) error {
 	}
 	return nil
 }
+
+// ArchiveTournamentLeaderboard marks a finalized tournament's board passive and drops its live ranking and
+// standings, the final standings are kept in Postgres
+func ArchiveTournamentLeaderboard(tournamentID uint) error {
+	ctx := context.Background()
+	pipe := rdb.TxPipeline()
+
+	pipe.Del(ctx, tournamentLeaderboardKey(tournamentID))
+	pipe.Del(ctx, tournamentStandingsKey(tournamentID))
+	pipe.HSet(ctx, leaderboardStatusKey, strconv.FormatUint(uint64(tournamentID), 10), string(model.Passive))
+
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// boardKey returns the key of a live leaderboard, the tournament ID only matters for tournament boards
+func boardKey(board model.LeaderboardBoard, tournamentID uint) (string, error) {
+	switch board {
+	case model.GlobalBoard:
+		return globalLeaderboardKey, nil
+	case model.RatingBoard:
+		return ratingRankingKey, nil
+	case model.TournamentBoard:
+		return tournamentLeaderboardKey(tournamentID), nil
+	default:
+		return "", fmt.Errorf("unknown leaderboard %q", board)
+	}
+}
+
+// GetBoardScores reads every member of a live leaderboard with its score
+func GetBoardScores(board model.LeaderboardBoard, tournamentID uint) (map[uint]float64, error) {
+	key, err := boardKey(board, tournamentID)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := getLeaderboardByKey(key, tournamentID, 0, -1)
+	if err != nil {
+		return nil, err
+	}
+
+	scores := make(map[uint]float64, len(entries))
+	for _, entry := range entries {
+		scores[entry.UserID] = entry.Score
+	}
+	return scores, nil
+}
+
+// ReplaceBoard swaps the members of a live leaderboard for the given scores in one transaction
+func ReplaceBoard(board model.LeaderboardBoard, tournamentID uint, scores map[uint]float64) error {
+	key, err := boardKey(board, tournamentID)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	pipe := rdb.TxPipeline()
+	pipe.Del(ctx, key)
+	if len(scores) > 0 {
+		members := make([]*redis.Z, 0, len(scores))
+		for member, score := range scores {
+			members = append(members, &redis.Z{Score: score, Member: strconv.FormatUint(uint64(member), 10)})
+		}
+		pipe.ZAdd(ctx, key, members...)
+	}
+	if board == model.TournamentBoard {
+		markLeaderboardActive(ctx, pipe, tournamentID)
+	}
+	_, err = pipe.Exec(ctx)
+	return err
+}
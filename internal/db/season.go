@@ -0,0 +1,52 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"tournament-app/model"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// seasonLeaderboardKey returns the sorted set of the tournament points users made during a season
+func seasonLeaderboardKey(seasonID uint) string {
+	return fmt.Sprintf("leaderboard:season:%d", seasonID)
+}
+
+// GetSeasonLeaderboard reads a running season's leaderboard between the given ranks, best first
+func GetSeasonLeaderboard(seasonID uint, start, stop int64) ([]model.SeasonLeaderboard, error) {
+	entries, err := getLeaderboardByKey(seasonLeaderboardKey(seasonID), 0, start, stop)
+	if err != nil {
+		return nil, err
+	}
+
+	standings := make([]model.SeasonLeaderboard, 0, len(entries))
+	for i, entry := range entries {
+		standings = append(standings, model.SeasonLeaderboard{
+			SeasonID: seasonID,
+			Rank:     int(start) + i + 1,
+			UserID:   entry.UserID,
+			Score:    entry.Score,
+		})
+	}
+	return standings, nil
+}
+
+// RemoveSeasonLeaderboard deletes the leaderboard of an archived season
+func RemoveSeasonLeaderboard(seasonID uint) error {
+	return rdb.Del(context.Background(), seasonLeaderboardKey(seasonID)).Err()
+}
+
+// SetRatingRankings sets the ratings of many users on the global rating ranking at once
+func SetRatingRankings(ratings map[uint]float64) error {
+	if len(ratings) == 0 {
+		return nil
+	}
+	ctx := context.Background()
+	members := make([]*redis.Z, 0, len(ratings))
+	for userID, rating := range ratings {
+		members = append(members, &redis.Z{Score: rating, Member: strconv.FormatUint(uint64(userID), 10)})
+	}
+	return rdb.ZAdd(ctx, ratingRankingKey, members...).Err()
+}
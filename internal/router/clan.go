@@ -0,0 +1,469 @@
+package router
+
+import (
+	"net/http"
+	"strconv"
+
+	"tournament-app/model"
+	"tournament-app/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ClanRoutes sets up the clan, membership and clan ranking routes
+func ClanRoutes(router *gin.Engine) {
+	router.POST("/clans", createClan)
+	router.GET("/clans", getClans)
+	router.GET("/clans/rankings", getClanRankings)
+	router.GET("/clans/:id", getClanByID)
+	router.DELETE("/clans/:id", deleteClan)
+	router.POST("/clans/:id/invitations", inviteToClan)
+	router.POST("/clans/invitations/:invitationId/accept", acceptClanInvitation)
+	router.POST("/clans/invitations/:invitationId/decline", declineClanInvitation)
+	router.GET("/users/:id/clan-invitations", getClanInvitations)
+	router.POST("/clans/:id/leave", leaveClan)
+	router.PUT("/clans/:id/members/:userId", setClanRole)
+	router.DELETE("/clans/:id/members/:userId", kickClanMember)
+	router.POST("/clans/:id/treasury/deposit", depositToClan)
+	router.POST("/clans/:id/treasury/payout", payOutFromClan)
+}
+
+// @Summary Create a clan
+// @Description Create a clan led by a user who is not in a clan yet
+// @Tags clans
+// @Accept  json
+// @Produce  json
+// @Param   clan  body  object{name=string, tag=string, leader_id=uint}  true  "Clan"
+// @Success 201 {object} model.Clan
+// @Failure 400 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /clans [post]
+func createClan(c *gin.Context) {
+	var request struct {
+		Name     string `json:"name" binding:"required"`
+		Tag      string `json:"tag"`
+		LeaderID uint   `json:"leader_id" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	clan := model.Clan{Name: request.Name, Tag: request.Tag}
+	if err := service.CreateClan(&clan, request.LeaderID); err != nil {
+		c.JSON(tournamentErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, clan)
+}
+
+// @Summary Get all clans
+// @Description Get all clans with their members
+// @Tags clans
+// @Produce  json
+// @Success 200 {array} model.Clan
+// @Failure 500 {object} map[string]interface{}
+// @Router /clans [get]
+func getClans(c *gin.Context) {
+	clans, err := service.GetClans()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, clans)
+}
+
+// @Summary Get the clan ranking
+// @Description Get the clans ranked by the tournament points of their members, best first
+// @Tags clans
+// @Produce  json
+// @Param   start  query  int  false  "Start"
+// @Param   stop   query  int  false  "Stop"
+// @Success 200 {array} model.ClanRanking
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /clans/rankings [get]
+func getClanRankings(c *gin.Context) {
+	start, _ := strconv.ParseInt(c.DefaultQuery("start", "0"), 10, 64)
+	stop, _ := strconv.ParseInt(c.DefaultQuery("stop", "10"), 10, 64)
+	if start < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "start cannot be negative"})
+		return
+	}
+	rankings, err := service.GetClanRankings(start, stop)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, rankings)
+}
+
+// @Summary Get a clan by ID
+// @Description Get a clan with its members and their roles
+// @Tags clans
+// @Produce  json
+// @Param   id  path  int  true  "Clan ID"
+// @Success 200 {object} model.Clan
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /clans/{id} [get]
+func getClanByID(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid clan ID"})
+		return
+	}
+	clan, err := service.GetClanByID(uint(id))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, clan)
+}
+
+// @Summary Delete a clan
+// @Description Delete a clan with an empty treasury, only its leader can
+// @Tags clans
+// @Accept  json
+// @Produce  json
+// @Param   id       path  int                    true  "Clan ID"
+// @Param   request  body  object{actor_id=uint}  true  "Leader"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /clans/{id} [delete]
+func deleteClan(c *gin.Context) {
+	clanID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid clan ID"})
+		return
+	}
+	var request struct {
+		ActorID uint `json:"actor_id" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := service.DeleteClan(uint(clanID), request.ActorID); err != nil {
+		c.JSON(tournamentErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Clan deleted successfully"})
+}
+
+// @Summary Invite a user to a clan
+// @Description The leader or an officer invites a user who is not in a clan
+// @Tags clans
+// @Accept  json
+// @Produce  json
+// @Param   id       path  int                                   true  "Clan ID"
+// @Param   request  body  object{inviter_id=uint, user_id=uint}  true  "Invitation"
+// @Success 201 {object} model.ClanInvitation
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /clans/{id}/invitations [post]
+func inviteToClan(c *gin.Context) {
+	clanID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid clan ID"})
+		return
+	}
+	var request struct {
+		InviterID uint `json:"inviter_id" binding:"required"`
+		UserID    uint `json:"user_id" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	invitation, err := service.InviteToClan(uint(clanID), request.InviterID, request.UserID)
+	if err != nil {
+		c.JSON(tournamentErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, invitation)
+}
+
+// @Summary Accept a clan invitation
+// @Description The invited user joins the clan as a member
+// @Tags clans
+// @Accept  json
+// @Produce  json
+// @Param   invitationId  path  int                   true  "Invitation ID"
+// @Param   request       body  object{user_id=uint}  true  "Invited user"
+// @Success 200 {object} model.Clan
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /clans/invitations/{invitationId}/accept [post]
+func acceptClanInvitation(c *gin.Context) {
+	invitationID, err := strconv.ParseUint(c.Param("invitationId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid invitation ID"})
+		return
+	}
+	var request struct {
+		UserID uint `json:"user_id" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	clan, err := service.AcceptClanInvitation(uint(invitationID), request.UserID)
+	if err != nil {
+		c.JSON(tournamentErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, clan)
+}
+
+// @Summary Decline a clan invitation
+// @Description The invited user turns the invitation down
+// @Tags clans
+// @Accept  json
+// @Produce  json
+// @Param   invitationId  path  int                   true  "Invitation ID"
+// @Param   request       body  object{user_id=uint}  true  "Invited user"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /clans/invitations/{invitationId}/decline [post]
+func declineClanInvitation(c *gin.Context) {
+	invitationID, err := strconv.ParseUint(c.Param("invitationId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid invitation ID"})
+		return
+	}
+	var request struct {
+		UserID uint `json:"user_id" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := service.DeclineClanInvitation(uint(invitationID), request.UserID); err != nil {
+		c.JSON(tournamentErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Clan invitation declined"})
+}
+
+// @Summary Get a user's clan invitations
+// @Description Get the clan invitations a user has not answered yet
+// @Tags clans
+// @Produce  json
+// @Param   id  path  int  true  "User ID"
+// @Success 200 {array} model.ClanInvitation
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /users/{id}/clan-invitations [get]
+func getClanInvitations(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+	invitations, err := service.GetClanInvitations(uint(userID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, invitations)
+}
+
+// @Summary Leave a clan
+// @Description A member other than the leader leaves their clan
+// @Tags clans
+// @Accept  json
+// @Produce  json
+// @Param   id       path  int                   true  "Clan ID"
+// @Param   request  body  object{user_id=uint}  true  "Member"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /clans/{id}/leave [post]
+func leaveClan(c *gin.Context) {
+	clanID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid clan ID"})
+		return
+	}
+	var request struct {
+		UserID uint `json:"user_id" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := service.LeaveClan(uint(clanID), request.UserID); err != nil {
+		c.JSON(tournamentErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "User left the clan"})
+}
+
+// @Summary Change a clan member's role
+// @Description The leader makes a member an officer or a member, making them the leader hands over the leadership
+// @Tags clans
+// @Accept  json
+// @Produce  json
+// @Param   id       path  int                                  true  "Clan ID"
+// @Param   userId   path  int                                  true  "User ID"
+// @Param   request  body  object{actor_id=uint, role=string}  true  "Role"
+// @Success 200 {object} model.Clan
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /clans/{id}/members/{userId} [put]
+func setClanRole(c *gin.Context) {
+	clanID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid clan ID"})
+		return
+	}
+	userID, err := strconv.ParseUint(c.Param("userId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+	var request struct {
+		ActorID uint           `json:"actor_id" binding:"required"`
+		Role    model.ClanRole `json:"role" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	clan, err := service.SetClanRole(uint(clanID), request.ActorID, uint(userID), request.Role)
+	if err != nil {
+		c.JSON(tournamentErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, clan)
+}
+
+// @Summary Remove a clan member
+// @Description The leader removes anyone and an officer removes members from the clan
+// @Tags clans
+// @Accept  json
+// @Produce  json
+// @Param   id       path  int                    true  "Clan ID"
+// @Param   userId   path  int                    true  "User ID"
+// @Param   request  body  object{actor_id=uint}  true  "Leader or officer"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /clans/{id}/members/{userId} [delete]
+func kickClanMember(c *gin.Context) {
+	clanID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid clan ID"})
+		return
+	}
+	userID, err := strconv.ParseUint(c.Param("userId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+	var request struct {
+		ActorID uint `json:"actor_id" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := service.KickClanMember(uint(clanID), request.ActorID, uint(userID)); err != nil {
+		c.JSON(tournamentErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "User removed from the clan"})
+}
+
+// @Summary Deposit into a clan treasury
+// @Description A member moves money from their wallet into the clan treasury
+// @Tags clans
+// @Accept  json
+// @Produce  json
+// @Param   id       path  int                               true  "Clan ID"
+// @Param   request  body  object{user_id=uint, amount=int}  true  "Deposit"
+// @Success 200 {object} model.Clan
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /clans/{id}/treasury/deposit [post]
+func depositToClan(c *gin.Context) {
+	clanID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid clan ID"})
+		return
+	}
+	var request struct {
+		UserID uint `json:"user_id" binding:"required"`
+		Amount int  `json:"amount" binding:"required,gt=0"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	clan, err := service.DepositToClan(uint(clanID), request.UserID, request.Amount)
+	if err != nil {
+		c.JSON(tournamentErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, clan)
+}
+
+// @Summary Pay out of a clan treasury
+// @Description The leader pays money from the clan treasury to a member
+// @Tags clans
+// @Accept  json
+// @Produce  json
+// @Param   id       path  int                                              true  "Clan ID"
+// @Param   request  body  object{actor_id=uint, user_id=uint, amount=int}  true  "Payout"
+// @Success 200 {object} model.Clan
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /clans/{id}/treasury/payout [post]
+func payOutFromClan(c *gin.Context) {
+	clanID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid clan ID"})
+		return
+	}
+	var request struct {
+		ActorID uint `json:"actor_id" binding:"required"`
+		UserID  uint `json:"user_id" binding:"required"`
+		Amount  int  `json:"amount" binding:"required,gt=0"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	clan, err := service.PayOutFromClan(uint(clanID), request.ActorID, request.UserID, request.Amount)
+	if err != nil {
+		c.JSON(tournamentErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, clan)
+}
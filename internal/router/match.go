@@ -0,0 +1,193 @@
+package router
+
+import (
+	"net/http"
+	"strconv"
+
+	"tournament-app/model"
+	"tournament-app/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MatchRoutes sets up the match routes
+func MatchRoutes(router *gin.Engine) {
+	router.POST("/tournaments/:id/matches", createMatch)
+	router.GET("/tournaments/:id/matches", getMatchesByTournamentID)
+	router.POST("/tournaments/:id/matches/:matchId/result", reportMatchResult)
+	router.GET("/tournaments/:id/bracket", getBracket)
+	router.GET("/tournaments/:id/standings", getStandings)
+	router.POST("/tournaments/:id/rounds/next", nextRound)
+	router.GET("/tournaments/:id/groups", getGroupStandings)
+}
+
+// @Summary Create a match
+// @Description Create a pending match between two players of an ongoing tournament
+// @Tags matches
+// @Accept  json
+// @Produce  json
+// @Param   id     path  int                                            true  "Tournament ID"
+// @Param   match  body  object{player1_id=uint, player2_id=uint, round=int}  true  "Match"
+// @Success 201 {object} model.Match
+// @Failure 400 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /tournaments/{id}/matches [post]
+func createMatch(c *gin.Context) {
+	tournamentID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tournament ID"})
+		return
+	}
+
+	var match model.Match
+	if err := c.ShouldBindJSON(&match); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	match.TournamentID = uint(tournamentID)
+	if err := service.CreateMatch(&match); err != nil {
+		c.JSON(tournamentErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, match)
+}
+
+// @Summary Get matches of a tournament
+// @Description Get the matches of a tournament ordered by round
+// @Tags matches
+// @Produce  json
+// @Param   id  path  int  true  "Tournament ID"
+// @Success 200 {array} model.Match
+// @Failure 500 {object} map[string]interface{}
+// @Router /tournaments/{id}/matches [get]
+func getMatchesByTournamentID(c *gin.Context) {
+	tournamentID, _ := strconv.ParseUint(c.Param("id"), 10, 64)
+	matches, err := service.GetMatchesByTournamentID(uint(tournamentID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, matches)
+}
+
+// @Summary Report a match result
+// @Description Report the scores of a match, the higher score wins and equal scores are a draw
+// @Tags matches
+// @Accept  json
+// @Produce  json
+// @Param   id       path  int                                          true  "Tournament ID"
+// @Param   matchId  path  int                                          true  "Match ID"
+// @Param   result   body  object{player1_score=int, player2_score=int}  true  "Result"
+// @Success 200 {object} model.Match
+// @Failure 400 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /tournaments/{id}/matches/{matchId}/result [post]
+func reportMatchResult(c *gin.Context) {
+	tournamentID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tournament ID"})
+		return
+	}
+	matchID, err := strconv.ParseUint(c.Param("matchId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid match ID"})
+		return
+	}
+
+	var request struct {
+		Player1Score int `json:"player1_score" binding:"gte=0"`
+		Player2Score int `json:"player2_score" binding:"gte=0"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	match, err := service.ReportMatchResult(uint(tournamentID), uint(matchID), request.Player1Score, request.Player2Score)
+	if err != nil {
+		c.JSON(tournamentErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, match)
+}
+
+// @Summary Get the bracket of a tournament
+// @Description Get the elimination bracket of a tournament as trees rooted at the finals of the winners and losers brackets, followed by the grand final matches
+// @Tags matches
+// @Produce  json
+// @Param   id  path  int  true  "Tournament ID"
+// @Success 200 {object} service.Bracket
+// @Failure 500 {object} map[string]interface{}
+// @Router /tournaments/{id}/bracket [get]
+func getBracket(c *gin.Context) {
+	tournamentID, _ := strconv.ParseUint(c.Param("id"), 10, 64)
+	bracket, err := service.GetBracket(uint(tournamentID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, bracket)
+}
+
+// @Summary Get the standings of a tournament
+// @Description Get the standings table of a tournament with wins, draws, losses and points, ranked by points, head-to-head results and score difference, or by points, Buchholz and Sonneborn-Berger in a swiss tournament
+// @Tags matches
+// @Produce  json
+// @Param   id  path  int  true  "Tournament ID"
+// @Success 200 {array} model.Standing
+// @Failure 500 {object} map[string]interface{}
+// @Router /tournaments/{id}/standings [get]
+func getStandings(c *gin.Context) {
+	tournamentID, _ := strconv.ParseUint(c.Param("id"), 10, 64)
+	standings, err := service.GetStandings(uint(tournamentID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, standings)
+}
+
+// @Summary Pair the next round
+// @Description Pair the next round of a swiss tournament once every match of the current round has a result
+// @Tags matches
+// @Produce  json
+// @Param   id  path  int  true  "Tournament ID"
+// @Success 201 {array} model.Match
+// @Failure 400 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /tournaments/{id}/rounds/next [post]
+func nextRound(c *gin.Context) {
+	tournamentID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tournament ID"})
+		return
+	}
+
+	matches, err := service.NextRound(uint(tournamentID))
+	if err != nil {
+		c.JSON(tournamentErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, matches)
+}
+
+// @Summary Get the group standings of a tournament
+// @Description Get the standings table of every group of a group stage tournament
+// @Tags matches
+// @Produce  json
+// @Param   id  path  int  true  "Tournament ID"
+// @Success 200 {array} service.GroupStandings
+// @Failure 500 {object} map[string]interface{}
+// @Router /tournaments/{id}/groups [get]
+func getGroupStandings(c *gin.Context) {
+	tournamentID, _ := strconv.ParseUint(c.Param("id"), 10, 64)
+	groups, err := service.GetGroupStandings(uint(tournamentID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, groups)
+}
@@ -0,0 +1,131 @@
+package router
+
+import (
+	"net/http"
+	"strconv"
+
+	"tournament-app/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MatchmakingRoutes sets up the matchmaking queue routes
+func MatchmakingRoutes(router *gin.Engine) {
+	router.POST("/matchmaking/queue", joinMatchmakingQueue)
+	router.DELETE("/matchmaking/queue", leaveMatchmakingQueue)
+	router.POST("/matchmaking/matches/:matchId/result", reportMatchmakingResult)
+	router.GET("/users/:id/matchmaking/matches", getMatchmakingMatches)
+}
+
+// @Summary Join the matchmaking queue
+// @Description Queue a user to be matched with an opponent of similar rating, the accepted rating difference grows while they wait
+// @Tags matchmaking
+// @Accept  json
+// @Produce  json
+// @Param   request  body  object{user_id=uint}  true  "User"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /matchmaking/queue [post]
+func joinMatchmakingQueue(c *gin.Context) {
+	var request struct {
+		UserID uint `json:"user_id" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := service.JoinMatchmakingQueue(request.UserID); err != nil {
+		c.JSON(tournamentErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "User joined the matchmaking queue"})
+}
+
+// @Summary Leave the matchmaking queue
+// @Description Take a user off the matchmaking queue
+// @Tags matchmaking
+// @Accept  json
+// @Produce  json
+// @Param   request  body  object{user_id=uint}  true  "User"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /matchmaking/queue [delete]
+func leaveMatchmakingQueue(c *gin.Context) {
+	var request struct {
+		UserID uint `json:"user_id" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := service.LeaveMatchmakingQueue(request.UserID); err != nil {
+		c.JSON(tournamentErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "User left the matchmaking queue"})
+}
+
+// @Summary Get a user's matchmaking matches
+// @Description List the matches the matchmaking queue made for a user, the newest first, to find the match ID to report the result of
+// @Tags matchmaking
+// @Produce  json
+// @Param   id  path  int  true  "User ID"
+// @Success 200 {array} model.Match
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /users/{id}/matchmaking/matches [get]
+func getMatchmakingMatches(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	matches, err := service.GetMatchmakingMatches(uint(userID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, matches)
+}
+
+// @Summary Report a matchmaking match result
+// @Description Report the scores of a match made by the matchmaking queue, both players are rated from the result
+// @Tags matchmaking
+// @Accept  json
+// @Produce  json
+// @Param   matchId  path  int                                          true  "Match ID"
+// @Param   result   body  object{player1_score=int, player2_score=int}  true  "Result"
+// @Success 200 {object} model.Match
+// @Failure 400 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /matchmaking/matches/{matchId}/result [post]
+func reportMatchmakingResult(c *gin.Context) {
+	matchID, err := strconv.ParseUint(c.Param("matchId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid match ID"})
+		return
+	}
+
+	var request struct {
+		Player1Score int `json:"player1_score" binding:"gte=0"`
+		Player2Score int `json:"player2_score" binding:"gte=0"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	match, err := service.ReportMatchmakingResult(uint(matchID), request.Player1Score, request.Player2Score)
+	if err != nil {
+		c.JSON(tournamentErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, match)
+}
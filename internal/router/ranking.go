@@ -0,0 +1,64 @@
+package router
+
+import (
+	"net/http"
+	"strconv"
+
+	"tournament-app/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RankingRoutes sets up the skill rating routes
+func RankingRoutes(router *gin.Engine) {
+	router.GET("/rankings", getRankings)
+	router.GET("/users/:id/ratings", getRatingHistory)
+}
+
+// @Summary Get the rating ranking
+// @Description Get the users ranked by their skill rating, best first
+// @Tags rankings
+// @Produce  json
+// @Param   start  query  int  false  "Start"
+// @Param   stop   query  int  false  "Stop"
+// @Success 200 {array} model.Ranking
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /rankings [get]
+func getRankings(c *gin.Context) {
+	start, _ := strconv.ParseInt(c.DefaultQuery("start", "0"), 10, 64)
+	stop, _ := strconv.ParseInt(c.DefaultQuery("stop", "10"), 10, 64)
+	if start < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "start cannot be negative"})
+		return
+	}
+	rankings, err := service.GetRankings(start, stop)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, rankings)
+}
+
+// @Summary Get the rating history of a user
+// @Description Get a user's skill rating after each of their rated matches, oldest first
+// @Tags rankings
+// @Produce  json
+// @Param   id  path  int  true  "User ID"
+// @Success 200 {array} model.RatingHistory
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /users/{id}/ratings [get]
+func getRatingHistory(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+	history, err := service.GetRatingHistory(uint(userID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, history)
+}
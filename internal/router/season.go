@@ -0,0 +1,168 @@
+package router
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"tournament-app/model"
+	"tournament-app/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SeasonRoutes sets up the season and season leaderboard routes
+func SeasonRoutes(router *gin.Engine) {
+	router.POST("/seasons", createSeason)
+	router.GET("/seasons", getSeasons)
+	router.GET("/seasons/current", getCurrentSeason)
+	router.POST("/seasons/rollover", rolloverSeasons)
+	router.GET("/seasons/:id", getSeasonByID)
+	router.GET("/seasons/:id/leaderboard", getSeasonLeaderboard)
+}
+
+// @Summary Create a season
+// @Description Create a season that does not overlap another active season. When it ends the next monthly season starts unless one was planned.
+// @Tags seasons
+// @Accept  json
+// @Produce  json
+// @Param   season  body  object{name=string, starts_at=string, ends_at=string, rating_soft_reset=number}  true  "Season"
+// @Success 201 {object} model.Season
+// @Failure 400 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /seasons [post]
+func createSeason(c *gin.Context) {
+	var request struct {
+		Name            string    `json:"name" binding:"required"`
+		StartsAt        time.Time `json:"starts_at" binding:"required"`
+		EndsAt          time.Time `json:"ends_at" binding:"required"`
+		RatingSoftReset float64   `json:"rating_soft_reset"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	season := model.Season{
+		Name:            request.Name,
+		StartsAt:        request.StartsAt,
+		EndsAt:          request.EndsAt,
+		RatingSoftReset: request.RatingSoftReset,
+	}
+	if err := service.CreateSeason(&season); err != nil {
+		c.JSON(tournamentErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, season)
+}
+
+// @Summary Get all seasons
+// @Description Get all seasons, the latest first
+// @Tags seasons
+// @Produce  json
+// @Success 200 {array} model.Season
+// @Failure 500 {object} map[string]interface{}
+// @Router /seasons [get]
+func getSeasons(c *gin.Context) {
+	seasons, err := service.GetSeasons()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, seasons)
+}
+
+// @Summary Get the current season
+// @Description Get the season running now, the tournament scheduler rolls over the seasons that ended
+// @Tags seasons
+// @Produce  json
+// @Success 200 {object} model.Season
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /seasons/current [get]
+func getCurrentSeason(c *gin.Context) {
+	season, err := service.CurrentSeason(time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if season == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No season is running"})
+		return
+	}
+	c.JSON(http.StatusOK, season)
+}
+
+// @Summary Roll over the seasons
+// @Description Archive the seasons that ended: their final standings are saved, the rating soft reset is applied and the next monthly season is started
+// @Tags seasons
+// @Produce  json
+// @Success 200 {array} model.Season
+// @Failure 500 {object} map[string]interface{}
+// @Router /seasons/rollover [post]
+func rolloverSeasons(c *gin.Context) {
+	archived, err := service.RolloverSeasons(time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if archived == nil {
+		archived = []model.Season{}
+	}
+	c.JSON(http.StatusOK, archived)
+}
+
+// @Summary Get a season by ID
+// @Description Get a season with its dates and status
+// @Tags seasons
+// @Produce  json
+// @Param   id  path  int  true  "Season ID"
+// @Success 200 {object} model.Season
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /seasons/{id} [get]
+func getSeasonByID(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid season ID"})
+		return
+	}
+	season, err := service.GetSeasonByID(uint(id))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, season)
+}
+
+// @Summary Get the leaderboard of a season
+// @Description Get the users ranked by the tournament points they made during the season, live while it runs and the archived final standings after it ended
+// @Tags seasons
+// @Produce  json
+// @Param   id     path   int  true   "Season ID"
+// @Param   start  query  int  false  "Start"
+// @Param   stop   query  int  false  "Stop"
+// @Success 200 {array} model.SeasonLeaderboard
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /seasons/{id}/leaderboard [get]
+func getSeasonLeaderboard(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid season ID"})
+		return
+	}
+	start, _ := strconv.ParseInt(c.DefaultQuery("start", "0"), 10, 64)
+	stop, _ := strconv.ParseInt(c.DefaultQuery("stop", "10"), 10, 64)
+	if start < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "start cannot be negative"})
+		return
+	}
+	standings, err := service.GetSeasonLeaderboard(uint(id), start, stop)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, standings)
+}
@@ -0,0 +1,239 @@
+package router
+
+import (
+	"net/http"
+	"strconv"
+
+	"tournament-app/model"
+	"tournament-app/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TeamRoutes sets up the team and team tournament routes
+func TeamRoutes(router *gin.Engine) {
+	router.POST("/teams", createTeam)
+	router.GET("/teams", getTeams)
+	router.GET("/teams/:id", getTeamByID)
+	router.DELETE("/teams/:id", deleteTeam)
+	router.POST("/teams/:id/members", addTeamMember)
+	router.DELETE("/teams/:id/members/:userId", removeTeamMember)
+	router.POST("/tournaments/:id/teams/join", joinTournamentAsTeam)
+	router.POST("/tournaments/:id/teams/leave", leaveTournamentAsTeam)
+}
+
+// @Summary Create a team
+// @Description Create a team led by its captain, who becomes its first member
+// @Tags teams
+// @Accept  json
+// @Produce  json
+// @Param   team  body  object{name=string, captain_id=uint, roster_size=int}  true  "Team"
+// @Success 201 {object} model.Team
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /teams [post]
+func createTeam(c *gin.Context) {
+	var team model.Team
+	if err := c.ShouldBindJSON(&team); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := service.CreateTeam(&team); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, team)
+}
+
+// @Summary Get all teams
+// @Description Get all teams with their members
+// @Tags teams
+// @Produce  json
+// @Success 200 {array} model.Team
+// @Failure 500 {object} map[string]interface{}
+// @Router /teams [get]
+func getTeams(c *gin.Context) {
+	teams, err := service.GetTeams()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, teams)
+}
+
+// @Summary Get a team by ID
+// @Description Get a team with its members
+// @Tags teams
+// @Produce  json
+// @Param   id  path  int  true  "Team ID"
+// @Success 200 {object} model.Team
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /teams/{id} [get]
+func getTeamByID(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+		return
+	}
+	team, err := service.GetTeamByID(uint(id))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, team)
+}
+
+// @Summary Delete a team
+// @Description Delete a team that is not entered in an open or ongoing tournament
+// @Tags teams
+// @Produce  json
+// @Param   id  path  int  true  "Team ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /teams/{id} [delete]
+func deleteTeam(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+		return
+	}
+	if err := service.DeleteTeam(uint(id)); err != nil {
+		c.JSON(tournamentErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Team deleted successfully"})
+}
+
+// @Summary Add a team member
+// @Description Put a user on a team with room on its roster, the roster of a team in an open or ongoing tournament is fixed
+// @Tags teams
+// @Accept  json
+// @Produce  json
+// @Param   id       path  int                   true  "Team ID"
+// @Param   request  body  object{user_id=uint}  true  "Member"
+// @Success 200 {object} model.Team
+// @Failure 400 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /teams/{id}/members [post]
+func addTeamMember(c *gin.Context) {
+	teamID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+		return
+	}
+	var request struct {
+		UserID uint `json:"user_id" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	team, err := service.AddTeamMember(uint(teamID), request.UserID)
+	if err != nil {
+		c.JSON(tournamentErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, team)
+}
+
+// @Summary Remove a team member
+// @Description Take a user other than the captain off a team that is not in an open or ongoing tournament
+// @Tags teams
+// @Produce  json
+// @Param   id      path  int  true  "Team ID"
+// @Param   userId  path  int  true  "User ID"
+// @Success 200 {object} model.Team
+// @Failure 400 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /teams/{id}/members/{userId} [delete]
+func removeTeamMember(c *gin.Context) {
+	teamID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+		return
+	}
+	userID, err := strconv.ParseUint(c.Param("userId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	team, err := service.RemoveTeamMember(uint(teamID), uint(userID))
+	if err != nil {
+		c.JSON(tournamentErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, team)
+}
+
+// @Summary Enter a team into a tournament
+// @Description Enter a team with a full roster into a team tournament, the entry fee is split across the members or paid by the captain
+// @Tags tournaments
+// @Accept  json
+// @Produce  json
+// @Param   id       path  int                   true  "Tournament ID"
+// @Param   request  body  object{team_id=uint}  true  "Team"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /tournaments/{id}/teams/join [post]
+func joinTournamentAsTeam(c *gin.Context) {
+	tournamentID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tournament ID"})
+		return
+	}
+	var request struct {
+		TeamID uint `json:"team_id" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := service.JoinTournamentAsTeam(uint(tournamentID), request.TeamID); err != nil {
+		c.JSON(tournamentErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Team joined tournament successfully"})
+}
+
+// @Summary Withdraw a team from a tournament
+// @Description Withdraw a team from a team tournament while its registration is open, every member's share of the entry fee is refunded under the tournament's refund policy
+// @Tags tournaments
+// @Accept  json
+// @Produce  json
+// @Param   id       path  int                   true  "Tournament ID"
+// @Param   request  body  object{team_id=uint}  true  "Team"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /tournaments/{id}/teams/leave [post]
+func leaveTournamentAsTeam(c *gin.Context) {
+	tournamentID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tournament ID"})
+		return
+	}
+	var request struct {
+		TeamID uint `json:"team_id" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := service.LeaveTournamentAsTeam(uint(tournamentID), request.TeamID); err != nil {
+		c.JSON(tournamentErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Team left tournament successfully"})
+}
@@ -0,0 +1,160 @@
+package router
+
+import (
+	"net/http"
+	"strconv"
+
+	"tournament-app/model"
+	"tournament-app/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TemplateRoutes sets up the recurring tournament template routes
+func TemplateRoutes(router *gin.Engine) {
+	router.POST("/tournament-templates", createTournamentTemplate)
+	router.GET("/tournament-templates", getTournamentTemplates)
+	router.GET("/tournament-templates/:id", getTournamentTemplateByID)
+	router.PUT("/tournament-templates/:id", updateTournamentTemplate)
+	router.DELETE("/tournament-templates/:id", deleteTournamentTemplate)
+	router.GET("/tournament-templates/:id/tournaments", getTemplateTournaments)
+}
+
+// @Summary Create a tournament template
+// @Description Create a recurring event. The scheduler creates a tournament for every time the cron recurrence fires, create_ahead_minutes in advance. Its registration opens registration_minutes before the start and it is finalized duration_minutes after it.
+// @Tags tournament-templates
+// @Accept  json
+// @Produce  json
+// @Param   template  body  object{name_pattern=string, prize=int, max_players=int, min_players=int, entry_fee=int, format=string, recurrence=string, registration_minutes=int, duration_minutes=int, create_ahead_minutes=int}  true  "Tournament template"
+// @Success 201 {object} model.TournamentTemplate
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /tournament-templates [post]
+func createTournamentTemplate(c *gin.Context) {
+	var template model.TournamentTemplate
+	if err := c.ShouldBindJSON(&template); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	template.ID = 0
+	if err := service.CreateTournamentTemplate(&template); err != nil {
+		c.JSON(tournamentErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, template)
+}
+
+// @Summary Get all tournament templates
+// @Description Get all recurring tournament templates
+// @Tags tournament-templates
+// @Produce  json
+// @Success 200 {array} model.TournamentTemplate
+// @Failure 500 {object} map[string]interface{}
+// @Router /tournament-templates [get]
+func getTournamentTemplates(c *gin.Context) {
+	templates, err := service.GetTournamentTemplates()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, templates)
+}
+
+// @Summary Get a tournament template by ID
+// @Description Get a recurring tournament template by its ID
+// @Tags tournament-templates
+// @Produce  json
+// @Param   id  path  int  true  "Template ID"
+// @Success 200 {object} model.TournamentTemplate
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /tournament-templates/{id} [get]
+func getTournamentTemplateByID(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid template ID"})
+		return
+	}
+	template, err := service.GetTournamentTemplateByID(uint(id))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, template)
+}
+
+// @Summary Update a tournament template
+// @Description Update a recurring tournament template, the settings left out are kept. Tournaments already created from it do not change.
+// @Tags tournament-templates
+// @Accept  json
+// @Produce  json
+// @Param   id        path  int                             true  "Template ID"
+// @Param   template  body  model.TournamentTemplateUpdate  true  "Template settings to change"
+// @Success 200 {object} model.TournamentTemplate
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /tournament-templates/{id} [put]
+func updateTournamentTemplate(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid template ID"})
+		return
+	}
+
+	var update model.TournamentTemplateUpdate
+	if err := c.ShouldBindJSON(&update); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	template, err := service.UpdateTournamentTemplate(uint(id), &update)
+	if err != nil {
+		c.JSON(tournamentErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, template)
+}
+
+// @Summary Delete a tournament template
+// @Description Delete a recurring tournament template, the tournaments created from it stay
+// @Tags tournament-templates
+// @Produce  json
+// @Param   id  path  int  true  "Template ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /tournament-templates/{id} [delete]
+func deleteTournamentTemplate(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid template ID"})
+		return
+	}
+	if err := service.DeleteTournamentTemplate(uint(id)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Tournament template deleted successfully"})
+}
+
+// @Summary Get the tournaments of a template
+// @Description Get the tournaments created from a recurring template, the latest start first
+// @Tags tournament-templates
+// @Produce  json
+// @Param   id  path  int  true  "Template ID"
+// @Success 200 {array} model.Tournament
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /tournament-templates/{id}/tournaments [get]
+func getTemplateTournaments(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid template ID"})
+		return
+	}
+	tournaments, err := service.GetTemplateTournaments(uint(id))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, tournaments)
+}
@@ -1,6 +1,8 @@
 package router
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
 
@@ -8,6 +10,7 @@ import (
 	"tournament-app/service"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 // TournamentRoutes sets up the tournament routes
@@ -18,25 +21,83 @@ func TournamentRoutes(router *gin.Engine) {
 	router.GET("/tournaments/:id", getTournamentByID)
 	router.GET("/tournaments/ongoing", getOngoingTournaments)
 	router.POST("/tournaments/join", joinTournament)
+	router.POST("/tournaments/:id/leave", leaveTournament)
+	router.POST("/tournaments/:id/open", openTournamentRegistration)
+	router.POST("/tournaments/:id/start", startTournament)
+	router.POST("/tournaments/:id/cancel", cancelTournament)
 	router.POST("/tournaments/:id/end", endTournament)
+	router.GET("/tournaments/:id/payouts", getTournamentPayouts)
 	router.GET("/tournaments", getAllTournaments)
 
 	router.GET("/leaderboard", getLeaderboard)
 	router.GET("/leaderboard/tournament/:id", getLeaderboardByTournamentID)
 	router.GET("/leaderboard/user/:id", getLeaderboardByUserID)
+	router.GET("/leaderboard/user/:id/rank", getLeaderboardRank)
+	router.GET("/leaderboard/user/:id/around", getLeaderboardAround)
 	router.GET("/leaderboard/tournament/:id/finished", getFinishedLeaderboardByTournamentID)
+	router.POST("/leaderboard/reconcile", reconcileLeaderboards)
 
 	router.GET("/leaderboard/active", getActiveLeaderboard)
 	router.GET("/leaderboard/user/:id/active", getActiveLeaderboardByUserID)
 	router.GET("/leaderboard/tournament/:id/active", getActiveLeaderboardByTournamentID)
 }
 
+// tournamentErrorStatus maps service errors to HTTP status codes
+func tournamentErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrInvalidTransition),
+		errors.Is(err, service.ErrTournamentFull),
+		errors.Is(err, service.ErrAlreadyJoined),
+		errors.Is(err, service.ErrNotEnoughPlayers),
+		errors.Is(err, service.ErrMatchCompleted),
+		errors.Is(err, service.ErrMatchNotReady),
+		errors.Is(err, service.ErrRoundNotFinished),
+		errors.Is(err, service.ErrNoRoundsLeft),
+		errors.Is(err, service.ErrNoPairing),
+		errors.Is(err, service.ErrAlreadyQueued),
+		errors.Is(err, service.ErrTeamFull),
+		errors.Is(err, service.ErrAlreadyMember),
+		errors.Is(err, service.ErrCaptainLeaving),
+		errors.Is(err, service.ErrTeamEntered),
+		errors.Is(err, service.ErrRosterIncomplete),
+		errors.Is(err, service.ErrAlreadyInClan),
+		errors.Is(err, service.ErrClanNameTaken),
+		errors.Is(err, service.ErrAlreadyInvited),
+		errors.Is(err, service.ErrInvitationAnswered),
+		errors.Is(err, service.ErrLeaderLeaving),
+		errors.Is(err, service.ErrTreasuryNotEmpty),
+		errors.Is(err, service.ErrInsufficientTreasury),
+		errors.Is(err, service.ErrSeasonOverlap):
+		return http.StatusConflict
+	case errors.Is(err, service.ErrRegistrationClosed),
+		errors.Is(err, service.ErrNotJoined),
+		errors.Is(err, service.ErrMatchNotInTournament),
+		errors.Is(err, service.ErrDrawNotAllowed),
+		errors.Is(err, service.ErrNotQueued),
+		errors.Is(err, service.ErrNotMember),
+		errors.Is(err, service.ErrTeamTournament),
+		errors.Is(err, service.ErrSoloTournament),
+		errors.Is(err, service.ErrNotInClan),
+		errors.Is(err, service.ErrInvalidWindow),
+		errors.Is(err, service.ErrInvalidTemplate),
+		errors.Is(err, service.ErrInvalidTournament):
+		return http.StatusBadRequest
+	case errors.Is(err, service.ErrClanPermission):
+		return http.StatusForbidden
+	case errors.Is(err, service.ErrNotRanked),
+		errors.Is(err, gorm.ErrRecordNotFound):
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
 // @Summary Create a new tournament
-// @Description Create a new tournament with the input payload
+// @Description Create a new tournament with the input payload. With a schedule its registration opens, it starts or is cancelled without min_players and it is finalized at the given times.
 // @Tags tournaments
 // @Accept  json
 // @Produce  json
-// @Param   tournament  body    object{name=string, prize=int}  true  "Tournament"
+// @Param   tournament  body    object{name=string, prize=int, max_players=int, min_players=int, entry_fee=int, prize_strategy=model.PrizeStrategyConfig, registration_opens_at=string, registration_closes_at=string, starts_at=string, ends_at=string}  true  "Tournament"
 // @Success 201 {object} model.Tournament
 // @Failure 400 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
@@ -48,7 +109,7 @@ func createTournament(c *gin.Context) {
 		return
 	}
 	if err := service.CreateTournament(&tournament); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(tournamentErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 	c.JSON(http.StatusCreated, tournament)
@@ -72,7 +133,7 @@ func deleteTournament(c *gin.Context) {
 	}
 
 	if err := service.DeleteTournament(uint(id)); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(tournamentErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "Tournament deleted successfully"})
@@ -87,6 +148,7 @@ func deleteTournament(c *gin.Context) {
 // @Param   tournament  body    model.Tournament  true  "Tournament"
 // @Success 200 {object} model.Tournament
 // @Failure 400 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Router /tournaments/{id} [put]
 func updateTournament(c *gin.Context) {
@@ -104,7 +166,7 @@ func updateTournament(c *gin.Context) {
 	}
 	tournament.ID = uint(id)
 	if err := service.UpdateTournament(&tournament); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(tournamentErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 	c.JSON(http.StatusOK, tournament)
@@ -152,6 +214,7 @@ func getOngoingTournaments(c *gin.Context) {
 // @Param   joinRequest  body    object{tournament_id=uint, user_id=uint}  true  "Join Request"
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Router /tournaments/join [post]
 func joinTournament(c *gin.Context) {
@@ -165,64 +228,179 @@ func joinTournament(c *gin.Context) {
 	}
 
 	if err := service.JoinTournament(request.TournamentID, request.UserID); err != nil {
-		if err.Error() == "cannot join a finished tournament" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		}
+		c.JSON(tournamentErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "User joined tournament successfully"})
 }
 
+// @Summary Leave a tournament
+// @Description Withdraw a user from a tournament while its registration is open, the entry fee is refunded under the tournament's refund policy
+// @Tags tournaments
+// @Accept  json
+// @Produce  json
+// @Param   id            path  int                  true  "Tournament ID"
+// @Param   leaveRequest  body  object{user_id=uint}  true  "Leave Request"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /tournaments/{id}/leave [post]
+func leaveTournament(c *gin.Context) {
+	tournamentID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tournament ID"})
+		return
+	}
+	var request struct {
+		UserID uint `json:"user_id" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := service.LeaveTournament(uint(tournamentID), request.UserID); err != nil {
+		c.JSON(tournamentErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "User left tournament successfully"})
+}
+
+// @Summary Open tournament registration
+// @Description Open the registration of a planned tournament
+// @Tags tournaments
+// @Produce  json
+// @Param   id  path  int  true  "Tournament ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /tournaments/{id}/open [post]
+func openTournamentRegistration(c *gin.Context) {
+	tournamentID, _ := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err := service.OpenRegistration(uint(tournamentID)); err != nil {
+		c.JSON(tournamentErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Tournament registration opened successfully"})
+}
+
+// @Summary Start a tournament
+// @Description Close the registration and start a tournament
+// @Tags tournaments
+// @Produce  json
+// @Param   id  path  int  true  "Tournament ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /tournaments/{id}/start [post]
+func startTournament(c *gin.Context) {
+	tournamentID, _ := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err := service.StartTournament(uint(tournamentID)); err != nil {
+		c.JSON(tournamentErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Tournament started successfully"})
+}
+
+// @Summary Cancel a tournament
+// @Description Cancel a tournament that has not finished yet, entry fees are refunded under the tournament's refund policy
+// @Tags tournaments
+// @Produce  json
+// @Param   id  path  int  true  "Tournament ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /tournaments/{id}/cancel [post]
+func cancelTournament(c *gin.Context) {
+	tournamentID, _ := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err := service.CancelTournament(uint(tournamentID)); err != nil {
+		c.JSON(tournamentErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Tournament cancelled successfully"})
+}
+
 // @Summary End a tournament
-// @Description End a tournament by ID
+// @Description Finalize an ongoing tournament by ID
 // @Tags tournaments
 // @Produce  json
 // @Param   id  path  int  true  "Tournament ID"
 // @Success 200 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Router /tournaments/{id}/end [post]
 func endTournament(c *gin.Context) {
 	tournamentID, _ := strconv.ParseUint(c.Param("id"), 10, 64)
 	if err := service.EndTournament(uint(tournamentID)); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(tournamentErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "Tournament ended successfully"})
 }
 
+// @Summary Preview tournament payouts
+// @Description Preview the prize of each rank under the tournament's prize strategy
+// @Tags tournaments
+// @Produce  json
+// @Param   id       path   int  true   "Tournament ID"
+// @Param   players  query  int  false  "Number of ranked players, defaults to the joined users"
+// @Success 200 {array} service.Payout
+// @Failure 500 {object} map[string]interface{}
+// @Router /tournaments/{id}/payouts [get]
+func getTournamentPayouts(c *gin.Context) {
+	tournamentID, _ := strconv.ParseUint(c.Param("id"), 10, 64)
+	players, _ := strconv.Atoi(c.DefaultQuery("players", "0"))
+	payouts, err := service.GetTournamentPayouts(uint(tournamentID), players)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, payouts)
+}
+
 // @Summary Get leaderboard
-// @Description Get the leaderboard
+// @Description Get the leaderboard of a time window with the rank, name, level and avatar of every user, the daily and weekly boards start empty every UTC day and ISO week and rank what each score changed by since
 // @Tags leaderboard
 // @Produce  json
-// @Param   start  query  int  false  "Start"
-// @Param   stop   query  int  false  "Stop"
-// @Success 200 {array} model.User
+// @Param   start   query  int     false  "Start"
+// @Param   stop    query  int     false  "Stop"
+// @Param   window  query  string  false  "Time window"  Enums(all_time, daily, weekly)
+// @Success 200 {array} model.LeaderboardEntry
+// @Failure 400 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Router /leaderboard [get]
 func getLeaderboard(c *gin.Context) {
 	start, _ := strconv.ParseInt(c.DefaultQuery("start", "0"), 10, 64)
 	stop, _ := strconv.ParseInt(c.DefaultQuery("stop", "10"), 10, 64)
-	leaderboard, err := service.GetActiveLeaderboard(start, stop)
+	if start < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "start cannot be negative"})
+		return
+	}
+	window := model.LeaderboardWindow(c.Query("window"))
+	leaderboard, err := service.GetLeaderboard(window, start, stop)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(tournamentErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 	c.JSON(http.StatusOK, leaderboard)
 }
 
 // @Summary Get leaderboard by tournament ID
-// @Description Get the leaderboard for a specific tournament
+// @Description Get the live Redis leaderboard for a specific tournament with the rank, name, level and avatar of every user
 // @Tags leaderboard
 // @Produce  json
-// @Param   id  path  int  true  "Tournament ID"
-// @Success 200 {array} model.User
+// @Param   id     path   int  true   "Tournament ID"
+// @Param   start  query  int  false  "Start"
+// @Param   stop   query  int  false  "Stop"
+// @Success 200 {array} model.LeaderboardEntry
 // @Failure 500 {object} map[string]interface{}
 // @Router /leaderboard/tournament/{id} [get]
 func getLeaderboardByTournamentID(c *gin.Context) {
 	tournamentID, _ := strconv.ParseUint(c.Param("id"), 10, 64)
-	leaderboard, err := service.GetActiveLeaderboardByTournamentID(uint(tournamentID))
+	start, _ := strconv.ParseInt(c.DefaultQuery("start", "0"), 10, 64)
+	stop, _ := strconv.ParseInt(c.DefaultQuery("stop", "10"), 10, 64)
+	leaderboard, err := service.GetTournamentLeaderboard(uint(tournamentID), start, stop)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -248,6 +426,78 @@ func getLeaderboardByUserID(c *gin.Context) {
 	c.JSON(http.StatusOK, leaderboard)
 }
 
+// @Summary Get the rank of a user
+// @Description Get a user's rank, score and name on a tournament's live leaderboard, or on the global leaderboard of a time window when no tournament is given
+// @Tags leaderboard
+// @Produce  json
+// @Param   id             path   int     true   "User ID"
+// @Param   tournament_id  query  int     false  "Tournament ID"
+// @Param   window         query  string  false  "Time window of the global leaderboard"  Enums(all_time, daily, weekly)
+// @Success 200 {object} model.LeaderboardEntry
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /leaderboard/user/{id}/rank [get]
+func getLeaderboardRank(c *gin.Context) {
+	userID, tournamentID, ok := leaderboardUserParams(c)
+	if !ok {
+		return
+	}
+	entry, err := service.GetLeaderboardRank(userID, tournamentID, model.LeaderboardWindow(c.Query("window")))
+	if err != nil {
+		c.JSON(tournamentErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, entry)
+}
+
+// @Summary Get the leaderboard around a user
+// @Description Get a user's entry with the entries up to radius ranks above and below it, on the same leaderboards as the rank lookup
+// @Tags leaderboard
+// @Produce  json
+// @Param   id             path   int     true   "User ID"
+// @Param   radius         query  int     false  "Entries above and below the user, at most 50"
+// @Param   tournament_id  query  int     false  "Tournament ID"
+// @Param   window         query  string  false  "Time window of the global leaderboard"  Enums(all_time, daily, weekly)
+// @Success 200 {array} model.LeaderboardEntry
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /leaderboard/user/{id}/around [get]
+func getLeaderboardAround(c *gin.Context) {
+	userID, tournamentID, ok := leaderboardUserParams(c)
+	if !ok {
+		return
+	}
+	radius, err := strconv.ParseInt(c.DefaultQuery("radius", "5"), 10, 64)
+	if err != nil || radius < 0 || radius > service.MaxAroundRadius {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("radius must be between 0 and %d", service.MaxAroundRadius)})
+		return
+	}
+	entries, err := service.GetLeaderboardAround(userID, tournamentID, model.LeaderboardWindow(c.Query("window")), radius)
+	if err != nil {
+		c.JSON(tournamentErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, entries)
+}
+
+// leaderboardUserParams reads the user ID and the optional tournament ID of a leaderboard lookup,
+// it answers the request itself when one of them is invalid
+func leaderboardUserParams(c *gin.Context) (uint, uint, bool) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return 0, 0, false
+	}
+	tournamentID, err := strconv.ParseUint(c.DefaultQuery("tournament_id", "0"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tournament ID"})
+		return 0, 0, false
+	}
+	return uint(userID), uint(tournamentID), true
+}
+
 // @Summary Get finished leaderboard by tournament ID
 // @Description Get the finished leaderboard for a specific tournament
 // @Tags leaderboard
@@ -283,29 +533,34 @@ func getAllTournaments(c *gin.Context) {
 }
 
 // @Summary Get active leaderboard
-// @Description Get the active leaderboard
+// @Description Get the active leaderboard of a time window
 // @Tags leaderboard
 // @Produce  json
-// @Success 200 {array} model.User
+// @Param   start   query  int     false  "Start"
+// @Param   stop    query  int     false  "Stop"
+// @Param   window  query  string  false  "Time window"  Enums(all_time, daily, weekly)
+// @Success 200 {array} model.Leaderboard
+// @Failure 400 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Router /leaderboard/active [get]
 func getActiveLeaderboard(c *gin.Context) {
 	start, _ := strconv.ParseInt(c.DefaultQuery("start", "0"), 10, 64)
 	stop, _ := strconv.ParseInt(c.DefaultQuery("stop", "10"), 10, 64)
-	leaderboard, err := service.GetActiveLeaderboard(start, stop)
+	window := model.LeaderboardWindow(c.Query("window"))
+	leaderboard, err := service.GetActiveLeaderboard(window, start, stop)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, map[string]interface{}{"error": err.Error()})
+		c.JSON(tournamentErrorStatus(err), map[string]interface{}{"error": err.Error()})
 		return
 	}
 	c.JSON(http.StatusOK, leaderboard)
 }
 
 // @Summary Get active leaderboard by user ID
-// @Description Get the active leaderboard for a specific user
+// @Description Get the user's entries on the leaderboards of the tournaments still running
 // @Tags leaderboard
 // @Produce  json
 // @Param   id  path  int  true  "User ID"
-// @Success 200 {array} model.User
+// @Success 200 {array} model.Leaderboard
 // @Failure 500 {object} map[string]interface{}
 // @Router /leaderboard/user/{id}/active [get]
 func getActiveLeaderboardByUserID(c *gin.Context) {
@@ -319,11 +574,11 @@ func getActiveLeaderboardByUserID(c *gin.Context) {
 }
 
 // @Summary Get active leaderboard by tournament ID
-// @Description Get the active leaderboard for a specific tournament
+// @Description Get the live leaderboard of a running tournament, empty once the tournament was finalized
 // @Tags leaderboard
 // @Produce  json
 // @Param   id  path  int  true  "Tournament ID"
-// @Success 200 {array} model.User
+// @Success 200 {array} model.Leaderboard
 // @Failure 500 {object} map[string]interface{}
 // @Router /leaderboard/tournament/{id}/active [get]
 func getActiveLeaderboardByTournamentID(c *gin.Context) {
@@ -335,3 +590,26 @@ func getActiveLeaderboardByTournamentID(c *gin.Context) {
 	}
 	c.JSON(http.StatusOK, leaderboard)
 }
+
+// @Summary Reconcile the leaderboards
+// @Description Compare the global leaderboard, the rating ranking and the leaderboards of running tournaments in Redis with Postgres and rebuild the boards that differ. With dry_run the mismatches are only reported.
+// @Tags leaderboard
+// @Produce  json
+// @Param   dry_run  query  bool  false  "Only report the mismatches"
+// @Success 200 {object} model.LeaderboardReconciliation
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /leaderboard/reconcile [post]
+func reconcileLeaderboards(c *gin.Context) {
+	dryRun, err := strconv.ParseBool(c.DefaultQuery("dry_run", "false"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, map[string]interface{}{"error": "Invalid dry_run"})
+		return
+	}
+	report, err := service.ReconcileLeaderboards(dryRun)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, map[string]interface{}{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
@@ -18,6 +18,8 @@ func UserRoutes(router *gin.Engine) {
 	router.GET("/users/:id", getUserByID)
 	router.GET("/users", getUsers)
 	router.POST("/users/:id/levelup", levelUpUser)
+	router.GET("/users/:id/transactions", getUserTransactions)
+	router.GET("/users/reconcile", reconcileWallets)
 	router.GET("/health", getHealth)
 	router.POST("/clear-database", clearDatabase)
 }
@@ -46,12 +48,13 @@ func createUser(c *gin.Context) {
 }
 
 // @Summary Delete a user
-// @Description Delete a user by ID
+// @Description Delete a user by ID, a clan member leaves their clan first
 // @Tags users
 // @Produce  json
 // @Param   id  path  integer  true  "User ID"
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Router /users/{id} [delete]
 func deleteUser(c *gin.Context) {
@@ -63,7 +66,7 @@ func deleteUser(c *gin.Context) {
 	}
 
 	if err := service.DeleteUser(uint(id)); err != nil {
-		c.JSON(http.StatusInternalServerError, map[string]interface{}{"error": err.Error()})
+		c.JSON(tournamentErrorStatus(err), map[string]interface{}{"error": err.Error()})
 		return
 	}
 	c.JSON(http.StatusOK, map[string]interface{}{"message": "User deleted successfully"})
@@ -158,6 +161,47 @@ func levelUpUser(c *gin.Context) {
 	c.JSON(http.StatusOK, map[string]interface{}{"message": "User leveled up successfully"})
 }
 
+// @Summary Get user transactions
+// @Description Get the wallet ledger of a user, oldest first
+// @Tags users
+// @Produce  json
+// @Param   id  path  integer  true  "User ID"
+// @Success 200 {array} model.Transaction
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /users/{id}/transactions [get]
+func getUserTransactions(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := strconv.ParseUint(idParam, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, map[string]interface{}{"error": "Invalid user ID"})
+		return
+	}
+
+	transactions, err := service.GetUserTransactions(uint(id))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, map[string]interface{}{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, transactions)
+}
+
+// @Summary Reconcile wallets
+// @Description List the users whose money disagrees with their ledger sum
+// @Tags users
+// @Produce  json
+// @Success 200 {array} model.WalletMismatch
+// @Failure 500 {object} map[string]interface{}
+// @Router /users/reconcile [get]
+func reconcileWallets(c *gin.Context) {
+	mismatches, err := service.ReconcileWallets()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, map[string]interface{}{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, mismatches)
+}
+
 // @Summary Get health status
 // @Description Check the health status of the service
 // @Tags health
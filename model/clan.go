@@ -0,0 +1,55 @@
+package model
+
+import "time"
+
+// ClanRole decides what a clan member may do in their clan
+type ClanRole string
+
+const (
+	LeaderRole  ClanRole = "leader"  // runs the clan, its members and its treasury, there is one per clan
+	OfficerRole ClanRole = "officer" // invites users and removes members
+	MemberRole  ClanRole = "member"
+)
+
+type InvitationStatus string
+
+const (
+	InvitationPending  InvitationStatus = "pending"
+	InvitationAccepted InvitationStatus = "accepted"
+	InvitationDeclined InvitationStatus = "declined"
+)
+
+// Clan is a long-lived community of users with a shared treasury
+type Clan struct {
+	ID        uint         `gorm:"primaryKey"`
+	Name      string       `gorm:"uniqueIndex" json:"name" validate:"required"`
+	Tag       string       `json:"tag"`
+	Treasury  int          `json:"treasury" validate:"gte=0"`
+	Members   []ClanMember `json:"members,omitempty"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+// ClanMember is a user's membership of a clan, a user belongs to one clan at most
+type ClanMember struct {
+	UserID   uint      `gorm:"primaryKey;autoIncrement:false" json:"user_id"`
+	ClanID   uint      `gorm:"index" json:"clan_id"`
+	Role     ClanRole  `json:"role"`
+	JoinedAt time.Time `gorm:"autoCreateTime" json:"joined_at"`
+}
+
+// ClanInvitation asks a user to join a clan, the user accepts or declines it
+type ClanInvitation struct {
+	ID        uint             `gorm:"primaryKey"`
+	ClanID    uint             `gorm:"index" json:"clan_id"`
+	UserID    uint             `gorm:"index" json:"user_id"`
+	InvitedBy uint             `json:"invited_by"`
+	Status    InvitationStatus `json:"status"`
+	CreatedAt time.Time        `json:"created_at"`
+}
+
+// ClanRanking is a clan's place on the clan leaderboard, its score adds up the tournament points of its members
+type ClanRanking struct {
+	Rank   int     `json:"rank"`
+	ClanID uint    `json:"clan_id"`
+	Score  float64 `json:"score"`
+}
@@ -11,10 +11,22 @@ const (
 	Passive LeaderboardStatus = "passive"
 )
 
+// LeaderboardWindow is the time span a global leaderboard covers, the daily and weekly boards start empty
+// at the beginning of every UTC day and ISO week and rank what the users' scores changed by since
+type LeaderboardWindow string
+
+const (
+	AllTimeWindow LeaderboardWindow = "all_time"
+	DailyWindow   LeaderboardWindow = "daily"
+	WeeklyWindow  LeaderboardWindow = "weekly"
+)
+
 type Leaderboard struct {
 	ID           uint              `gorm:"primaryKey"`
 	UserID       uint              `json:"user_id" validate:"required"`
+	TeamID       uint              `json:"team_id,omitempty"` // team tournaments: the ranked team, UserID is its captain
 	TournamentID uint              `json:"tournament_id" validate:"required"`
+	SeasonID     *uint             `gorm:"index" json:"season_id,omitempty"` // the season the tournament's points counted for
 	Score        float64           `json:"score" validate:"gte=0"`
 	Status       LeaderboardStatus `json:"status" validate:"required"`
 }
@@ -23,3 +35,63 @@ func (l *Leaderboard) Validate() error {
 	validate := validator.New()
 	return validate.Struct(l)
 }
+
+// LeaderboardEntry is a user's place on a live leaderboard with the profile data clients show next to it
+type LeaderboardEntry struct {
+	Rank   int     `json:"rank"`
+	UserID uint    `json:"user_id"`
+	TeamID uint    `json:"team_id,omitempty"` // team tournaments: the ranked team, UserID is its captain
+	Name   string  `json:"name"`              // the user's name, or the team's name on a team tournament's board
+	Level  int     `json:"level,omitempty"`   // the user's level, team entries have none
+	Avatar string  `json:"avatar,omitempty"`  // the user's profile picture, team entries have none
+	Score  float64 `json:"score"`
+}
+
+// Standing is a player's row in the standings table of a tournament played in rounds
+type Standing struct {
+	Rank         int  `json:"rank"`
+	Group        int  `json:"group,omitempty"` // group stage: the group the rank is within
+	UserID       uint `json:"user_id"`
+	Played       int  `json:"played"`
+	Wins         int  `json:"wins"`
+	Draws        int  `json:"draws"`
+	Losses       int  `json:"losses"`
+	Points       int  `json:"points"`
+	ScoreFor     int  `json:"score_for"`
+	ScoreAgainst int  `json:"score_against"`
+	// Swiss tiebreakers: the points of all opponents, and of the beaten ones plus half of the drawn ones
+	Buchholz        int     `json:"buchholz,omitempty"`
+	SonnebornBerger float64 `json:"sonneborn_berger,omitempty"`
+}
+
+// ScoreDifference is the tiebreaker after head-to-head results
+func (s Standing) ScoreDifference() int {
+	return s.ScoreFor - s.ScoreAgainst
+}
+
+// LeaderboardBoard names a live leaderboard kept in Redis
+type LeaderboardBoard string
+
+const (
+	GlobalBoard     LeaderboardBoard = "global"     // all users by the score of their level and money
+	RatingBoard     LeaderboardBoard = "rating"     // all users by their skill rating
+	TournamentBoard LeaderboardBoard = "tournament" // the entrants of a running tournament by their points
+)
+
+// LeaderboardMismatch is a member whose score in Redis disagrees with Postgres. Expected is nil for a member
+// that should not be on the board, Actual is nil for a member missing from it.
+type LeaderboardMismatch struct {
+	Board        LeaderboardBoard `json:"board"`
+	TournamentID uint             `json:"tournament_id,omitempty"`
+	UserID       uint             `json:"user_id"` // team tournaments: the team ID
+	Expected     *float64         `json:"expected"`
+	Actual       *float64         `json:"actual"`
+}
+
+// LeaderboardReconciliation is the outcome of comparing the live leaderboards with Postgres
+type LeaderboardReconciliation struct {
+	DryRun     bool                  `json:"dry_run"`
+	Checked    int                   `json:"checked"` // boards compared
+	Rebuilt    int                   `json:"rebuilt"` // boards rewritten from Postgres, always 0 in a dry run
+	Mismatches []LeaderboardMismatch `json:"mismatches"`
+}
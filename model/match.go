@@ -0,0 +1,62 @@
+package model
+
+import (
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+type MatchStatus string
+
+const (
+	MatchPending   MatchStatus = "pending"
+	MatchCompleted MatchStatus = "completed"
+	MatchSkipped   MatchStatus = "skipped" // a bracket match that will never have players, e.g. an unneeded reset
+)
+
+// BracketSide tells which part of an elimination bracket a match belongs to
+type BracketSide string
+
+const (
+	WinnersBracket    BracketSide = "winners"
+	LosersBracket     BracketSide = "losers"
+	GrandFinalBracket BracketSide = "grand_final"
+)
+
+// Match is a game between two players of a tournament, a completed match without a winner is a draw.
+// A match made by the matchmaking queue has no tournament, its TournamentID is 0.
+// Bracket matches have a Code unique within the tournament, WinnerTo and LoserTo name the matches
+// the winner and the loser move on to. ByeSlot marks the slot of a bracket match that never gets a player,
+// the match is won by whoever arrives in the other slot.
+type Match struct {
+	ID           uint        `gorm:"primaryKey"`
+	TournamentID uint        `gorm:"index" json:"tournament_id"`
+	Round        int         `json:"round" validate:"gte=1"`
+	Position     int         `json:"position"`
+	Group        int         `json:"group,omitempty"` // group stage: the group the match is played in, 0 for the knockout
+	Bracket      BracketSide `json:"bracket,omitempty"`
+	Code         string      `gorm:"index" json:"code,omitempty"`
+	WinnerTo     string      `json:"winner_to,omitempty"`
+	WinnerToSlot int         `json:"winner_to_slot,omitempty"`
+	LoserTo      string      `json:"loser_to,omitempty"`
+	LoserToSlot  int         `json:"loser_to_slot,omitempty"`
+	ByeSlot      int         `json:"bye_slot,omitempty"`
+	Player1ID    *uint       `json:"player1_id"`
+	Player2ID    *uint       `json:"player2_id"`
+	Player1Score int         `json:"player1_score" validate:"gte=0"`
+	Player2Score int         `json:"player2_score" validate:"gte=0"`
+	WinnerID     *uint       `json:"winner_id"`
+	Status       MatchStatus `json:"status"`
+	CreatedAt    time.Time   `json:"created_at"`
+	UpdatedAt    time.Time   `json:"updated_at"`
+}
+
+// HasPlayer reports whether the user plays in the match
+func (m *Match) HasPlayer(userID uint) bool {
+	return (m.Player1ID != nil && *m.Player1ID == userID) || (m.Player2ID != nil && *m.Player2ID == userID)
+}
+
+func (m *Match) Validate() error {
+	validate := validator.New()
+	return validate.Struct(m)
+}
@@ -0,0 +1,10 @@
+package model
+
+import "time"
+
+// QueueEntry is a user waiting in the matchmaking queue with the rating they queued with
+type QueueEntry struct {
+	UserID   uint      `json:"user_id"`
+	Rating   float64   `json:"rating"`
+	JoinedAt time.Time `json:"joined_at"`
+}
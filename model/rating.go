@@ -0,0 +1,42 @@
+package model
+
+import "time"
+
+// SkillRating is a player's skill estimate: the rating, how uncertain it is and how erratic the player's results are.
+// Elo only uses the rating, Glicko-2 all three. The column defaults rate users created before ratings existed.
+type SkillRating struct {
+	Rating          float64 `gorm:"default:1500" json:"rating"`
+	RatingDeviation float64 `gorm:"default:350" json:"rating_deviation"`
+	Volatility      float64 `gorm:"default:0.06" json:"volatility"`
+}
+
+// DefaultSkillRating is the rating of a player without any rated match
+var DefaultSkillRating = SkillRating{Rating: 1500, RatingDeviation: 350, Volatility: 0.06}
+
+// RatingHistory is a user's rating after a rated match
+type RatingHistory struct {
+	ID           uint      `gorm:"primaryKey"`
+	UserID       uint      `gorm:"index" json:"user_id"`
+	MatchID      uint      `json:"match_id"`
+	TournamentID uint      `json:"tournament_id"`
+	Change       float64   `json:"change"`
+	CreatedAt    time.Time `json:"created_at"`
+	SkillRating
+}
+
+// Ranking is a user's place on the global rating ranking
+type Ranking struct {
+	Rank   int     `json:"rank"`
+	UserID uint    `json:"user_id"`
+	Rating float64 `json:"rating"`
+}
+
+// SoftReset pulls the rating back towards the starting rating by the given share, 0 keeps it and 1 resets it.
+// The rating deviation grows by the same share so the first matches of a new season move the rating more.
+func (s SkillRating) SoftReset(share float64) SkillRating {
+	return SkillRating{
+		Rating:          s.Rating + (DefaultSkillRating.Rating-s.Rating)*share,
+		RatingDeviation: s.RatingDeviation + (DefaultSkillRating.RatingDeviation-s.RatingDeviation)*share,
+		Volatility:      s.Volatility,
+	}
+}
@@ -0,0 +1,136 @@
+package model
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// recurrenceShortcuts are the named recurrences accepted next to the five cron fields
+var recurrenceShortcuts = map[string]string{
+	"@hourly":  "0 * * * *",
+	"@daily":   "0 0 * * *",
+	"@weekly":  "0 0 * * 1",
+	"@monthly": "0 0 1 * *",
+}
+
+// Recurrence is a parsed cron expression of five fields: minute, hour, day of month, month and day of week
+// (0 or 7 is Sunday). A field is *, a value, a range a-b, a step */n or a-b/n, or a comma separated list of
+// those. As in cron a day matches either day field when both are restricted. Times are in UTC.
+type Recurrence struct {
+	minutes, hours, days, months, weekdays map[int]bool
+	anyDay, anyWeekday                     bool
+}
+
+// ParseRecurrence parses a cron expression or one of @hourly, @daily, @weekly and @monthly
+func ParseRecurrence(expression string) (*Recurrence, error) {
+	expression = strings.TrimSpace(expression)
+	if shortcut, ok := recurrenceShortcuts[expression]; ok {
+		expression = shortcut
+	}
+	fields := strings.Fields(expression)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("recurrence %q must have 5 fields", expression)
+	}
+
+	bounds := [5][2]int{{0, 59}, {0, 23}, {1, 31}, {1, 12}, {0, 7}}
+	names := [5]string{"minute", "hour", "day of month", "month", "day of week"}
+	var sets [5]map[int]bool
+	for i, field := range fields {
+		set, err := parseRecurrenceField(field, bounds[i][0], bounds[i][1])
+		if err != nil {
+			return nil, fmt.Errorf("recurrence %s: %v", names[i], err)
+		}
+		sets[i] = set
+	}
+	if sets[4][7] {
+		sets[4][0] = true
+	}
+	return &Recurrence{
+		minutes:    sets[0],
+		hours:      sets[1],
+		days:       sets[2],
+		months:     sets[3],
+		weekdays:   sets[4],
+		anyDay:     fields[2] == "*",
+		anyWeekday: fields[4] == "*",
+	}, nil
+}
+
+// parseRecurrenceField returns the values of a cron field between min and max
+func parseRecurrenceField(field string, min, max int) (map[int]bool, error) {
+	set := map[int]bool{}
+	for _, part := range strings.Split(field, ",") {
+		span, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			var err error
+			if step, err = strconv.Atoi(part[i+1:]); err != nil || step < 1 {
+				return nil, fmt.Errorf("invalid step in %q", part)
+			}
+			span = part[:i]
+		}
+
+		low, high := min, max
+		if span != "*" {
+			bounds := strings.SplitN(span, "-", 2)
+			var err error
+			if low, err = strconv.Atoi(bounds[0]); err != nil {
+				return nil, fmt.Errorf("invalid value in %q", part)
+			}
+			high = low
+			if len(bounds) == 2 {
+				if high, err = strconv.Atoi(bounds[1]); err != nil {
+					return nil, fmt.Errorf("invalid value in %q", part)
+				}
+			} else if step > 1 {
+				// a/n runs from a to the end of the field
+				high = max
+			}
+		}
+		if low < min || high > max || low > high {
+			return nil, fmt.Errorf("%q is outside %d-%d", part, min, max)
+		}
+		for value := low; value <= high; value += step {
+			set[value] = true
+		}
+	}
+	return set, nil
+}
+
+// Next returns the first time after the given one the recurrence fires, the zero time if it never does
+// within five years (e.g. on February 30)
+func (r *Recurrence) Next(after time.Time) time.Time {
+	next := after.UTC().Truncate(time.Minute).Add(time.Minute)
+	limit := next.AddDate(5, 0, 0)
+	for next.Before(limit) {
+		switch {
+		case !r.months[int(next.Month())]:
+			next = time.Date(next.Year(), next.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+		case !r.matchesDay(next):
+			next = time.Date(next.Year(), next.Month(), next.Day()+1, 0, 0, 0, 0, time.UTC)
+		case !r.hours[next.Hour()]:
+			next = next.Truncate(time.Hour).Add(time.Hour)
+		case !r.minutes[next.Minute()]:
+			next = next.Add(time.Minute)
+		default:
+			return next
+		}
+	}
+	return time.Time{}
+}
+
+// matchesDay reports whether the day fits the day fields, either of them when both are restricted
+func (r *Recurrence) matchesDay(at time.Time) bool {
+	day, weekday := r.days[at.Day()], r.weekdays[int(at.Weekday())]
+	switch {
+	case r.anyDay && r.anyWeekday:
+		return true
+	case r.anyDay:
+		return weekday
+	case r.anyWeekday:
+		return day
+	default:
+		return day || weekday
+	}
+}
@@ -0,0 +1,32 @@
+package model
+
+import "time"
+
+type SeasonStatus string
+
+const (
+	SeasonActive   SeasonStatus = "active"   // running or upcoming, its leaderboard lives in Redis
+	SeasonArchived SeasonStatus = "archived" // over, its final standings are in Postgres
+)
+
+// Season is a period of play with its own leaderboard of the tournament points made during it
+type Season struct {
+	ID       uint         `gorm:"primaryKey"`
+	Name     string       `json:"name" validate:"required"`
+	StartsAt time.Time    `gorm:"index" json:"starts_at"`
+	EndsAt   time.Time    `gorm:"index" json:"ends_at"`
+	Status   SeasonStatus `json:"status"`
+	// RatingSoftReset is the share of the distance to the starting rating every rating loses when
+	// the season ends, 0 keeps the ratings and 1 resets them
+	RatingSoftReset float64   `json:"rating_soft_reset" validate:"gte=0,lte=1"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// SeasonLeaderboard is a user's archived final place in a season
+type SeasonLeaderboard struct {
+	ID       uint    `gorm:"primaryKey"`
+	SeasonID uint    `gorm:"index" json:"season_id"`
+	Rank     int     `json:"rank"`
+	UserID   uint    `json:"user_id"`
+	Score    float64 `json:"score"`
+}
@@ -0,0 +1,30 @@
+package model
+
+import "time"
+
+// Default roster size of a team created without one
+const DefaultRosterSize = 5
+
+// Team is a group of users entering team tournaments together, led by its captain
+type Team struct {
+	ID         uint      `gorm:"primaryKey"`
+	Name       string    `json:"name" validate:"required"`
+	CaptainID  uint      `json:"captain_id" validate:"required"`
+	RosterSize int       `json:"roster_size" validate:"gte=1"` // most members the team can have, the captain included
+	Members    []User    `gorm:"many2many:team_members" json:"members"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// TeamMember is the join table between teams and users
+type TeamMember struct {
+	TeamID   uint      `gorm:"primaryKey"`
+	UserID   uint      `gorm:"primaryKey"`
+	JoinedAt time.Time `gorm:"autoCreateTime"`
+}
+
+// TournamentTeam is the join table between tournaments and the teams entered into them
+type TournamentTeam struct {
+	TournamentID uint      `gorm:"primaryKey"`
+	TeamID       uint      `gorm:"primaryKey"`
+	JoinedAt     time.Time `gorm:"autoCreateTime"`
+}
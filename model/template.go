@@ -0,0 +1,134 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+// Defaults used when a tournament template is created without its own timing
+const (
+	DefaultRegistrationMinutes = 60
+	DefaultDurationMinutes     = 120
+	DefaultCreateAheadMinutes  = 24 * 60
+)
+
+// TournamentTemplate describes a recurring event. The scheduler creates its tournaments ahead of time,
+// one for every time its recurrence fires, with the schedule derived from that start time.
+type TournamentTemplate struct {
+	ID uint `gorm:"primaryKey"`
+	// NamePattern names the tournaments, {date} and {time} are replaced by the start date and time in UTC
+	NamePattern     string              `json:"name_pattern" validate:"required"`
+	Prize           int                 `json:"prize"`
+	MaxPlayers      int                 `json:"max_players" validate:"gte=0"`
+	MinPlayers      int                 `json:"min_players" validate:"gte=0"`
+	EntryFee        *int                `json:"entry_fee" validate:"omitempty,gte=0"` // left out for the default fee, 0 for free tournaments
+	PrizeStrategy   PrizeStrategyConfig `gorm:"type:jsonb" json:"prize_strategy"`
+	RefundPolicy    RefundPolicy        `json:"refund_policy"`
+	Format          TournamentFormat    `json:"format"`
+	Seeding         SeedingMethod       `json:"seeding"`
+	GrandFinalReset bool                `json:"grand_final_reset"`
+	Rounds          int                 `json:"rounds" validate:"gte=0"`
+	GroupSize       int                 `json:"group_size" validate:"gte=0"`
+	AdvancePerGroup int                 `json:"advance_per_group" validate:"gte=0"`
+	TeamEntry       bool                `json:"team_entry"`
+	TeamFee         TeamFeePolicy       `json:"team_fee"`
+	// Recurrence is a cron expression of the start times, see ParseRecurrence
+	Recurrence          string `json:"recurrence" validate:"required"`
+	RegistrationMinutes int    `json:"registration_minutes" validate:"gte=0"` // registration opens this long before the start and closes at it
+	DurationMinutes     int    `json:"duration_minutes" validate:"gte=0"`     // the tournament is finalized this long after the start
+	CreateAheadMinutes  int    `json:"create_ahead_minutes" validate:"gte=0"` // tournaments starting within this time are created
+	Paused              bool   `json:"paused"`                                // no new tournaments are created while paused
+	// LastStartsAt is the start time of the latest tournament created, the next one starts after it
+	LastStartsAt *time.Time `json:"last_starts_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// Tournament returns the tournament of the template starting at the given time
+func (t *TournamentTemplate) Tournament(startsAt time.Time) Tournament {
+	startsAt = startsAt.UTC()
+	opensAt := startsAt.Add(-time.Duration(t.RegistrationMinutes) * time.Minute)
+	endsAt := startsAt.Add(time.Duration(t.DurationMinutes) * time.Minute)
+	name := strings.NewReplacer("{date}", startsAt.Format("2006-01-02"), "{time}", startsAt.Format("15:04")).Replace(t.NamePattern)
+
+	templateID := t.ID
+	return Tournament{
+		Name:                 name,
+		Prize:                t.Prize,
+		MaxPlayers:           t.MaxPlayers,
+		MinPlayers:           t.MinPlayers,
+		EntryFee:             t.EntryFee,
+		PrizeStrategy:        t.PrizeStrategy,
+		RefundPolicy:         t.RefundPolicy,
+		Format:               t.Format,
+		Seeding:              t.Seeding,
+		GrandFinalReset:      t.GrandFinalReset,
+		Rounds:               t.Rounds,
+		GroupSize:            t.GroupSize,
+		AdvancePerGroup:      t.AdvancePerGroup,
+		TeamEntry:            t.TeamEntry,
+		TeamFee:              t.TeamFee,
+		TemplateID:           &templateID,
+		RegistrationOpensAt:  &opensAt,
+		RegistrationClosesAt: &startsAt,
+		StartsAt:             &startsAt,
+		EndsAt:               &endsAt,
+	}
+}
+
+// TournamentTemplateUpdate is a partial update of a tournament template, only the fields sent are changed
+type TournamentTemplateUpdate struct {
+	NamePattern         *string              `json:"name_pattern"`
+	Prize               *int                 `json:"prize"`
+	MaxPlayers          *int                 `json:"max_players"`
+	MinPlayers          *int                 `json:"min_players"`
+	EntryFee            *int                 `json:"entry_fee"`
+	PrizeStrategy       *PrizeStrategyConfig `json:"prize_strategy"`
+	RefundPolicy        *RefundPolicy        `json:"refund_policy"`
+	Format              *TournamentFormat    `json:"format"`
+	Seeding             *SeedingMethod       `json:"seeding"`
+	GrandFinalReset     *bool                `json:"grand_final_reset"`
+	Rounds              *int                 `json:"rounds"`
+	GroupSize           *int                 `json:"group_size"`
+	AdvancePerGroup     *int                 `json:"advance_per_group"`
+	TeamEntry           *bool                `json:"team_entry"`
+	TeamFee             *TeamFeePolicy       `json:"team_fee"`
+	Recurrence          *string              `json:"recurrence"`
+	RegistrationMinutes *int                 `json:"registration_minutes"`
+	DurationMinutes     *int                 `json:"duration_minutes"`
+	CreateAheadMinutes  *int                 `json:"create_ahead_minutes"`
+	Paused              *bool                `json:"paused"`
+}
+
+// Apply copies the fields of the update that were sent onto the template
+func (u *TournamentTemplateUpdate) Apply(t *TournamentTemplate) {
+	setIfSent(&t.NamePattern, u.NamePattern)
+	setIfSent(&t.Prize, u.Prize)
+	setIfSent(&t.MaxPlayers, u.MaxPlayers)
+	setIfSent(&t.MinPlayers, u.MinPlayers)
+	if u.EntryFee != nil {
+		t.EntryFee = u.EntryFee
+	}
+	setIfSent(&t.PrizeStrategy, u.PrizeStrategy)
+	setIfSent(&t.RefundPolicy, u.RefundPolicy)
+	setIfSent(&t.Format, u.Format)
+	setIfSent(&t.Seeding, u.Seeding)
+	setIfSent(&t.GrandFinalReset, u.GrandFinalReset)
+	setIfSent(&t.Rounds, u.Rounds)
+	setIfSent(&t.GroupSize, u.GroupSize)
+	setIfSent(&t.AdvancePerGroup, u.AdvancePerGroup)
+	setIfSent(&t.TeamEntry, u.TeamEntry)
+	setIfSent(&t.TeamFee, u.TeamFee)
+	setIfSent(&t.Recurrence, u.Recurrence)
+	setIfSent(&t.RegistrationMinutes, u.RegistrationMinutes)
+	setIfSent(&t.DurationMinutes, u.DurationMinutes)
+	setIfSent(&t.CreateAheadMinutes, u.CreateAheadMinutes)
+	setIfSent(&t.Paused, u.Paused)
+}
+
+// setIfSent sets the field to the value of a partial update when the value was sent
+func setIfSent[T any](field *T, value *T) {
+	if value != nil {
+		*field = *value
+	}
+}
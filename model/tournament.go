@@ -1,6 +1,11 @@
 package model
 
 import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
 	"github.com/go-playground/validator/v10"
 	"gorm.io/gorm"
 )
@@ -8,17 +13,144 @@ import (
 type TournamentStatus string
 
 const (
-	Planned  TournamentStatus = "planned"
-	Ongoing  TournamentStatus = "ongoing"
-	Finished TournamentStatus = "finished"
+	Planned          TournamentStatus = "planned"
+	RegistrationOpen TournamentStatus = "registration_open"
+	Ongoing          TournamentStatus = "ongoing"
+	Finished         TournamentStatus = "finished"
+	Cancelled        TournamentStatus = "cancelled"
+)
+
+type PrizeStrategyType string
+
+const (
+	PercentagePrize     PrizeStrategyType = "percentage"
+	FixedPrize          PrizeStrategyType = "fixed"
+	WinnerTakesAllPrize PrizeStrategyType = "winner_takes_all"
+	TopNSplitPrize      PrizeStrategyType = "top_n_split"
+)
+
+// TournamentFormat decides which matches are generated when the tournament starts
+type TournamentFormat string
+
+const (
+	SingleElimination TournamentFormat = "single_elimination"
+	DoubleElimination TournamentFormat = "double_elimination"
+	RoundRobin        TournamentFormat = "round_robin"
+	Swiss             TournamentFormat = "swiss"
+	GroupStage        TournamentFormat = "group_stage"
+)
+
+// SeedingMethod decides the order players are placed into the bracket, best first
+type SeedingMethod string
+
+const (
+	SeedByLevel SeedingMethod = "level"
+	SeedByScore SeedingMethod = "score"
+)
+
+// RefundPolicy decides how much of the entry fee is returned when a user leaves or the tournament is cancelled
+type RefundPolicy string
+
+const (
+	RefundBeforeStart RefundPolicy = "before_start" // full refund until the tournament starts, none after
+	FullRefund        RefundPolicy = "full"
+	NoRefund          RefundPolicy = "none"
+)
+
+// TeamFeePolicy decides who pays the entry fee of a team entering a team tournament
+type TeamFeePolicy string
+
+const (
+	SplitTeamFee   TeamFeePolicy = "split"   // every member pays an equal share, the captain also pays what does not divide evenly
+	CaptainTeamFee TeamFeePolicy = "captain" // the captain pays the whole fee
+)
+
+// PrizeStrategyConfig describes how the prize pool of a tournament is paid out,
+// an empty config falls back to the default percentage table
+type PrizeStrategyConfig struct {
+	Type        PrizeStrategyType `json:"type"`
+	Percentages []float64         `json:"percentages,omitempty"` // percentage: share of the pool per rank
+	Amounts     []int             `json:"amounts,omitempty"`     // fixed: money per rank
+	TopN        int               `json:"top_n,omitempty"`       // top_n_split: number of ranks sharing the pool
+}
+
+// Value stores the prize strategy as a JSON column
+func (p PrizeStrategyConfig) Value() (driver.Value, error) {
+	return json.Marshal(p)
+}
+
+// Scan reads the prize strategy from a JSON column
+func (p *PrizeStrategyConfig) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*p = PrizeStrategyConfig{}
+		return nil
+	case []byte:
+		return json.Unmarshal(v, p)
+	case string:
+		return json.Unmarshal([]byte(v), p)
+	default:
+		return fmt.Errorf("unsupported prize strategy type %T", value)
+	}
+}
+
+// Defaults used when a tournament is created without its own limits
+const (
+	DefaultMaxPlayers = 10
+	DefaultMinPlayers = 3
+	DefaultEntryFee   = 50
+	// group stage tournaments
+	DefaultGroupSize       = 4
+	DefaultAdvancePerGroup = 2
 )
 
 type Tournament struct {
-	ID     uint             `gorm:"primaryKey"`
-	Name   string           `json:"name" validate:"required"`
-	Status TournamentStatus `json:"status" validate:"required,default=planned"`
-	Prize  int              `json:"prize" validate:"required"`
-	Users  []User           `gorm:"many2many:tournament_users"`
+	ID              uint                `gorm:"primaryKey"`
+	Name            string              `json:"name" validate:"required"`
+	Status          TournamentStatus    `json:"status" validate:"required,default=planned"`
+	Prize           int                 `json:"prize" validate:"required"`
+	MaxPlayers      int                 `json:"max_players" validate:"gte=0"`
+	MinPlayers      int                 `json:"min_players" validate:"gte=0"`
+	EntryFee        *int                `json:"entry_fee" validate:"omitempty,gte=0"` // left out for the default fee, 0 for a free tournament
+	PrizeStrategy   PrizeStrategyConfig `gorm:"type:jsonb" json:"prize_strategy"`
+	RefundPolicy    RefundPolicy        `json:"refund_policy"`
+	Format          TournamentFormat    `json:"format"`
+	Seeding         SeedingMethod       `json:"seeding"`
+	GrandFinalReset bool                `json:"grand_final_reset"`                  // double elimination: replay the final if the losers bracket champion wins it
+	Rounds          int                 `json:"rounds" validate:"gte=0"`            // swiss: rounds to play, 0 plays enough rounds to leave a single unbeaten player
+	GroupSize       int                 `json:"group_size" validate:"gte=0"`        // group stage: players per group
+	AdvancePerGroup int                 `json:"advance_per_group" validate:"gte=0"` // group stage: top players of each group going into the knockout
+	TeamEntry       bool                `json:"team_entry"`                         // entered by teams instead of single players, max and min players count teams
+	TeamFee         TeamFeePolicy       `json:"team_fee"`                           // team tournaments: who pays the entry fee of a team
+	SeasonID        *uint               `gorm:"index" json:"season_id,omitempty"`   // the season running when the tournament was created
+	TemplateID      *uint               `gorm:"index" json:"template_id,omitempty"` // the recurring template the tournament was created from
+	// The schedule is optional, the scheduler moves a tournament along the steps whose time is set:
+	// registration opens, joining stops, the tournament starts or is cancelled without min_players, it is finalized.
+	RegistrationOpensAt  *time.Time `gorm:"index" json:"registration_opens_at,omitempty"`
+	RegistrationClosesAt *time.Time `json:"registration_closes_at,omitempty"`
+	StartsAt             *time.Time `gorm:"index" json:"starts_at,omitempty"`
+	EndsAt               *time.Time `gorm:"index" json:"ends_at,omitempty"`
+	CreatedAt            time.Time  `json:"created_at"`
+	UpdatedAt            time.Time  `json:"updated_at"`
+	Users                []User     `gorm:"many2many:tournament_users"`
+	Teams                []Team     `gorm:"many2many:tournament_teams" json:"teams,omitempty"`
+}
+
+// TournamentUser is the join table between tournaments and users,
+// its composite primary key keeps a user from joining the same tournament twice, also with two different teams
+type TournamentUser struct {
+	TournamentID uint      `gorm:"primaryKey"`
+	UserID       uint      `gorm:"primaryKey"`
+	TeamID       *uint     `gorm:"index"` // team tournaments: the team the user entered with
+	JoinedAt     time.Time `gorm:"autoCreateTime"`
+}
+
+// Fee returns the entry fee a player pays to join, 0 for a free tournament
+func (t *Tournament) Fee() int {
+	if t.EntryFee == nil {
+		return 0
+	}
+	return *t.EntryFee
 }
 
 func (t *Tournament) Validate() error {
@@ -0,0 +1,43 @@
+package model
+
+import (
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+type TransactionReason string
+
+const (
+	EntryFeeTransaction    TransactionReason = "entry_fee"
+	PrizeTransaction       TransactionReason = "prize"
+	LevelUpTransaction     TransactionReason = "level_up"
+	RefundTransaction      TransactionReason = "refund"
+	AdminAdjustTransaction TransactionReason = "admin_adjust"
+	ClanDepositTransaction TransactionReason = "clan_deposit" // money put into a clan treasury
+	ClanPayoutTransaction  TransactionReason = "clan_payout"  // money paid out of a clan treasury
+)
+
+// Transaction is one money movement in a user's wallet ledger,
+// the sum of a user's transactions must equal their Money
+type Transaction struct {
+	ID           uint              `gorm:"primaryKey"`
+	UserID       uint              `gorm:"index" json:"user_id" validate:"required"`
+	Amount       int               `json:"amount"`
+	Reason       TransactionReason `json:"reason" validate:"required"`
+	TournamentID *uint             `json:"tournament_id,omitempty"`
+	ClanID       *uint             `json:"clan_id,omitempty"`
+	CreatedAt    time.Time         `json:"created_at"`
+}
+
+// WalletMismatch flags a user whose Money disagrees with their ledger
+type WalletMismatch struct {
+	UserID        uint `json:"user_id"`
+	Money         int  `json:"money"`
+	LedgerBalance int  `json:"ledger_balance"`
+}
+
+func (t *Transaction) Validate() error {
+	validate := validator.New()
+	return validate.Struct(t)
+}
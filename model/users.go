@@ -11,6 +11,9 @@ type User struct {
 	Money int     `json:"money" validate:"required"`
 	Level int     `json:"level" validate:"required"`
 	Score float64 `json:"score" validate:"gte=0"`
+	// Avatar is the URL of the user's profile picture
+	Avatar string `json:"avatar"`
+	SkillRating
 }
 
 func (u *User) Validate() error {
@@ -18,13 +21,16 @@ func (u *User) Validate() error {
 	return validate.Struct(u)
 }
 
-// BeforeCreate hook to calculate the score before saving the user
+// BeforeCreate hook to calculate the score and give a new user the starting rating before saving the user
 func (u *User) BeforeCreate(tx *gorm.DB) (err error) {
-	u.Score = calculateScore(u)
+	u.Score = CalculateScore(u)
+	if u.SkillRating == (SkillRating{}) {
+		u.SkillRating = DefaultSkillRating
+	}
 	return
 }
 
-// calculateScore calculates the score for a user based on their level and other factors
-func calculateScore(user *User) float64 {
+// CalculateScore calculates the score for a user based on their level and money
+func CalculateScore(user *User) float64 {
 	return float64(user.Level*100 + user.Money)
 }
@@ -1,10 +1,19 @@
 #!/bin/sh
 set -e
 
-echo "Running router tests..."
+# The Postgres tests are skipped without POSTGRES_DSN. The tests service of the docker compose test profile
+# runs this script against its own database with REQUIRE_POSTGRES set, which fails them instead of skipping.
+if [ -z "$POSTGRES_DSN" ]; then
+  echo "POSTGRES_DSN is not set, the Postgres tests will be skipped."
+fi
+
+echo "Running tests..."
 
-if go test -v ./...; then
+if go test -count=1 -v ./...; then
   echo "Tests passed successfully."
+elif [ -n "$REQUIRE_POSTGRES" ]; then
+  echo "Tests failed."
+  exit 1
 else
   echo "Tests failed, but continuing the pipeline."
 fi
@@ -0,0 +1,547 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"tournament-app/internal/crud"
+	"tournament-app/model"
+)
+
+// Codes of the grand final and of its optional reset in a double-elimination bracket
+const (
+	grandFinalCode      = "GF"
+	grandFinalResetCode = "GF2"
+)
+
+// BracketNode is a bracket match together with the matches whose winners play in it
+type BracketNode struct {
+	Match    model.Match    `json:"match"`
+	Children []*BracketNode `json:"children,omitempty"`
+}
+
+// Bracket is the match tree of an elimination tournament, each side rooted at its final
+type Bracket struct {
+	Winners    *BracketNode  `json:"winners"`
+	Losers     *BracketNode  `json:"losers,omitempty"`
+	GrandFinal []model.Match `json:"grand_final,omitempty"`
+}
+
+// SeedPlayers orders the players best first by the seeding method, ties go to the lower user ID
+func SeedPlayers(players []model.User, method model.SeedingMethod) []model.User {
+	seeded := append([]model.User(nil), players...)
+	sort.SliceStable(seeded, func(i, j int) bool {
+		a, b := seeded[i], seeded[j]
+		switch {
+		case method == model.SeedByScore && a.Score != b.Score:
+			return a.Score > b.Score
+		case method != model.SeedByScore && a.Level != b.Level:
+			return a.Level > b.Level
+		default:
+			return a.ID < b.ID
+		}
+	})
+	return seeded
+}
+
+// GenerateSingleElimination builds the matches of a single-elimination bracket for players in seed order.
+// The field is padded to a power of two, the missing players are byes given to the top seeds.
+func GenerateSingleElimination(tournamentID uint, players []model.User) []model.Match {
+	if len(players) < 2 {
+		return nil
+	}
+
+	size := bracketSize(len(players))
+	matches := winnersBracket(tournamentID, size)
+	placePlayers(matches, players, size)
+	resolveByes(matches)
+	return matches
+}
+
+// GenerateDoubleElimination builds the winners bracket, the losers bracket and the grand final
+// for players in seed order. Winners bracket losers drop into the losers bracket, every other
+// drop-in round is reversed so early opponents do not meet again right away.
+// With reset the grand final is followed by a second one, played only if the losers bracket champion wins.
+func GenerateDoubleElimination(tournamentID uint, players []model.User, reset bool) []model.Match {
+	if len(players) < 2 {
+		return nil
+	}
+
+	size := bracketSize(len(players))
+	winnerRounds := 0
+	for count := size; count > 1; count /= 2 {
+		winnerRounds++
+	}
+
+	matches := winnersBracket(tournamentID, size)
+	index := matchIndex(matches)
+
+	// The winners bracket final sends its winner to the grand final
+	final := &matches[index[winnersCode(winnerRounds, 1)]]
+	final.WinnerTo, final.WinnerToSlot = grandFinalCode, 1
+
+	// Losers rounds come in pairs: odd rounds play the survivors among themselves
+	// (round 1 the winners bracket round 1 losers), even rounds add the next winners bracket round's losers
+	loserRounds := 2 * (winnerRounds - 1)
+	for round := 1; round <= loserRounds; round++ {
+		count := size >> ((round+1)/2 + 1)
+		for position := 1; position <= count; position++ {
+			match := model.Match{
+				TournamentID: tournamentID,
+				Round:        round,
+				Position:     position,
+				Bracket:      model.LosersBracket,
+				Code:         losersCode(round, position),
+				Status:       model.MatchPending,
+			}
+			switch {
+			case round == loserRounds:
+				match.WinnerTo, match.WinnerToSlot = grandFinalCode, 2
+			case round%2 == 1:
+				// the survivor waits for a winners bracket loser in the next round
+				match.WinnerTo, match.WinnerToSlot = losersCode(round+1, position), 1
+			default:
+				match.WinnerTo, match.WinnerToSlot = losersCode(round+1, (position+1)/2), 2-position%2
+			}
+			matches = append(matches, match)
+		}
+	}
+
+	// Route every winners bracket loser into the losers bracket
+	for i := range matches {
+		match := &matches[i]
+		if match.Bracket != model.WinnersBracket {
+			continue
+		}
+		switch {
+		case loserRounds == 0:
+			// two players: the loser of the only match gets a second chance in the grand final
+			match.LoserTo, match.LoserToSlot = grandFinalCode, 2
+		case match.Round == 1:
+			match.LoserTo, match.LoserToSlot = losersCode(1, (match.Position+1)/2), 2-match.Position%2
+		default:
+			count := size >> match.Round
+			position := match.Position
+			if match.Round%2 == 0 {
+				position = count + 1 - position
+			}
+			match.LoserTo, match.LoserToSlot = losersCode(2*(match.Round-1), position), 2
+		}
+	}
+
+	matches = append(matches, model.Match{
+		TournamentID: tournamentID,
+		Round:        1,
+		Position:     1,
+		Bracket:      model.GrandFinalBracket,
+		Code:         grandFinalCode,
+		Status:       model.MatchPending,
+	})
+	if reset {
+		matches = append(matches, model.Match{
+			TournamentID: tournamentID,
+			Round:        2,
+			Position:     1,
+			Bracket:      model.GrandFinalBracket,
+			Code:         grandFinalResetCode,
+			Status:       model.MatchPending,
+		})
+	}
+
+	placePlayers(matches, players, size)
+	resolveByes(matches)
+	return matches
+}
+
+// winnersBracket creates the empty matches of an elimination tree for a power-of-two field, round by round.
+// Match W<round>-<position> sends its winner to W<round+1>-<(position+1)/2>.
+func winnersBracket(tournamentID uint, size int) []model.Match {
+	var matches []model.Match
+	for round, count := 1, size/2; count >= 1; round, count = round+1, count/2 {
+		for position := 1; position <= count; position++ {
+			match := model.Match{
+				TournamentID: tournamentID,
+				Round:        round,
+				Position:     position,
+				Bracket:      model.WinnersBracket,
+				Code:         winnersCode(round, position),
+				Status:       model.MatchPending,
+			}
+			if count > 1 {
+				match.WinnerTo = winnersCode(round+1, (position+1)/2)
+				match.WinnerToSlot = 2 - position%2
+			}
+			matches = append(matches, match)
+		}
+	}
+	return matches
+}
+
+func winnersCode(round, position int) string {
+	return fmt.Sprintf("W%d-%d", round, position)
+}
+
+func losersCode(round, position int) string {
+	return fmt.Sprintf("L%d-%d", round, position)
+}
+
+// placePlayers fills the first winners bracket round so that the top seeds meet as late as possible
+func placePlayers(matches []model.Match, players []model.User, size int) {
+	seeds := seedOrder(size)
+	for i := 0; i < size/2; i++ {
+		matches[i].Player1ID = seededPlayer(players, seeds[2*i])
+		matches[i].Player2ID = seededPlayer(players, seeds[2*i+1])
+	}
+}
+
+// resolveByes walks the bracket from the first round on and finds the slots that will never get a player:
+// an empty first round slot, the loser of a bye or anything coming out of a skipped match.
+// A match without any player is skipped, a match with one such slot is a bye won by the other player.
+func resolveByes(matches []model.Match) {
+	index := matchIndex(matches)
+	fed := map[string][3]bool{}
+	for _, match := range matches {
+		if match.WinnerTo != "" {
+			slots := fed[match.WinnerTo]
+			slots[match.WinnerToSlot] = true
+			fed[match.WinnerTo] = slots
+		}
+		if match.LoserTo != "" {
+			slots := fed[match.LoserTo]
+			slots[match.LoserToSlot] = true
+			fed[match.LoserTo] = slots
+		}
+	}
+
+	dead := map[string][3]bool{}
+	markDead := func(code string, slot int) {
+		if code == "" {
+			return
+		}
+		slots := dead[code]
+		slots[slot] = true
+		dead[code] = slots
+	}
+
+	for i := range matches {
+		match := &matches[i]
+		if match.Code == grandFinalResetCode {
+			continue
+		}
+		slot1Dead := dead[match.Code][1] || (match.Player1ID == nil && !fed[match.Code][1])
+		slot2Dead := dead[match.Code][2] || (match.Player2ID == nil && !fed[match.Code][2])
+
+		switch {
+		case slot1Dead && slot2Dead:
+			match.Status = model.MatchSkipped
+			markDead(match.WinnerTo, match.WinnerToSlot)
+			markDead(match.LoserTo, match.LoserToSlot)
+		case slot1Dead || slot2Dead:
+			match.ByeSlot = 1
+			player := match.Player2ID
+			if slot2Dead {
+				match.ByeSlot = 2
+				player = match.Player1ID
+			}
+			markDead(match.LoserTo, match.LoserToSlot)
+			if player != nil {
+				completeMatch(matches, index, i, player, nil, map[int]bool{})
+			}
+		}
+	}
+}
+
+// ApplyBracketResult records the scores of the bracket match with the given code and moves its
+// winner and loser on, finishing any bye they run into. It returns the indexes of the changed matches.
+func ApplyBracketResult(matches []model.Match, code string, player1Score, player2Score int) ([]int, error) {
+	index := matchIndex(matches)
+	i, ok := index[code]
+	if !ok {
+		return nil, fmt.Errorf("bracket match %s not found", code)
+	}
+	match := &matches[i]
+	if match.Status != model.MatchPending {
+		return nil, ErrMatchCompleted
+	}
+	if match.Player1ID == nil || match.Player2ID == nil {
+		return nil, ErrMatchNotReady
+	}
+	if player1Score == player2Score {
+		return nil, ErrDrawNotAllowed
+	}
+
+	match.Player1Score, match.Player2Score = player1Score, player2Score
+	winner, loser := match.Player1ID, match.Player2ID
+	if player2Score > player1Score {
+		winner, loser = loser, winner
+	}
+
+	changed := map[int]bool{}
+	completeMatch(matches, index, i, winner, loser, changed)
+
+	indexes := make([]int, 0, len(changed))
+	for j := range changed {
+		indexes = append(indexes, j)
+	}
+	sort.Ints(indexes)
+	return indexes, nil
+}
+
+// completeMatch finishes the match and moves the winner and the loser into their next matches
+func completeMatch(matches []model.Match, index map[string]int, i int, winner, loser *uint, changed map[int]bool) {
+	match := &matches[i]
+	match.WinnerID = winner
+	match.Status = model.MatchCompleted
+	changed[i] = true
+
+	if match.WinnerTo != "" {
+		moveIntoMatch(matches, index, match.WinnerTo, match.WinnerToSlot, winner, changed)
+	}
+	if match.LoserTo != "" && loser != nil {
+		moveIntoMatch(matches, index, match.LoserTo, match.LoserToSlot, loser, changed)
+	}
+
+	// The reset is only played when the winners bracket champion lost the grand final
+	if match.Code == grandFinalCode {
+		if j, ok := index[grandFinalResetCode]; ok {
+			if *winner == *match.Player1ID {
+				matches[j].Status = model.MatchSkipped
+			} else {
+				matches[j].Player1ID, matches[j].Player2ID = match.Player1ID, match.Player2ID
+			}
+			changed[j] = true
+		}
+	}
+}
+
+// moveIntoMatch puts the player into a slot of the match, a bye is won right away
+func moveIntoMatch(matches []model.Match, index map[string]int, code string, slot int, playerID *uint, changed map[int]bool) {
+	j, ok := index[code]
+	if !ok {
+		return
+	}
+	target := &matches[j]
+	setMatchPlayer(target, slot, playerID)
+	changed[j] = true
+
+	if target.ByeSlot != 0 && target.Status == model.MatchPending {
+		completeMatch(matches, index, j, playerID, nil, changed)
+	}
+}
+
+// setMatchPlayer puts the player into slot 1 or 2 of the match
+func setMatchPlayer(match *model.Match, slot int, playerID *uint) {
+	if slot == 1 {
+		match.Player1ID = playerID
+	} else {
+		match.Player2ID = playerID
+	}
+}
+
+// matchIndex maps the bracket codes to their position in matches
+func matchIndex(matches []model.Match) map[string]int {
+	index := make(map[string]int, len(matches))
+	for i, match := range matches {
+		if match.Code != "" {
+			index[match.Code] = i
+		}
+	}
+	return index
+}
+
+// seededPlayer returns the ID of the player with the given 1-based seed, nil for a bye
+func seededPlayer(players []model.User, seed int) *uint {
+	if seed > len(players) {
+		return nil
+	}
+	id := players[seed-1].ID
+	return &id
+}
+
+// bracketSize returns the smallest power of two that fits the players
+func bracketSize(players int) int {
+	size := 2
+	for size < players {
+		size *= 2
+	}
+	return size
+}
+
+// seedOrder returns the seeds in bracket order so that seed 1 meets seed 2 only in the final,
+// e.g. 1, 8, 4, 5, 2, 7, 3, 6 for eight players
+func seedOrder(size int) []int {
+	order := []int{1}
+	for len(order) < size {
+		next := make([]int, 0, len(order)*2)
+		for _, seed := range order {
+			next = append(next, seed, len(order)*2+1-seed)
+		}
+		order = next
+	}
+	return order
+}
+
+// RankElimination ranks the players of an elimination bracket, given in seed order, by how far they got:
+// the champion first, then the other finalist, then everyone else by the furthest round they reached on the
+// side they were knocked out of, the winners bracket of a single-elimination bracket and the losers bracket
+// of a double-elimination one. A bye counts as a round won, players knocked out in the same round keep
+// their seed order.
+func RankElimination(players []model.User, matches []model.Match) []model.Standing {
+	totals := map[uint]model.Standing{}
+	for _, standing := range tallyStandings(players, matches) {
+		totals[standing.UserID] = standing
+	}
+
+	final := eliminationFinal(matches)
+	side := model.WinnersBracket
+	if final != nil && final.Bracket == model.GrandFinalBracket {
+		side = model.LosersBracket
+	}
+	reached := map[uint]int{}
+	for _, match := range matches {
+		if match.Bracket != side {
+			continue
+		}
+		for _, playerID := range []*uint{match.Player1ID, match.Player2ID} {
+			if playerID != nil && match.Round > reached[*playerID] {
+				reached[*playerID] = match.Round
+			}
+		}
+	}
+
+	// placed puts the finalists above every round, the champion above the other finalist
+	placed := map[uint]int{}
+	if final != nil {
+		for _, playerID := range []*uint{final.Player1ID, final.Player2ID} {
+			if playerID != nil {
+				placed[*playerID] = 1
+			}
+		}
+		if final.WinnerID != nil {
+			placed[*final.WinnerID] = 2
+		}
+	}
+
+	ranking := make([]model.Standing, 0, len(players))
+	for _, player := range players {
+		ranking = append(ranking, totals[player.ID])
+	}
+	sort.SliceStable(ranking, func(i, j int) bool {
+		a, b := ranking[i].UserID, ranking[j].UserID
+		if placed[a] != placed[b] {
+			return placed[a] > placed[b]
+		}
+		return reached[a] > reached[b]
+	})
+	for i := range ranking {
+		ranking[i].Rank = i + 1
+	}
+	return ranking
+}
+
+// eliminationFinal returns the match that decides the title: the winners bracket final of a single-elimination
+// bracket, the grand final of a double-elimination one or its reset once that was played
+func eliminationFinal(matches []model.Match) *model.Match {
+	var final *model.Match
+	for i := range matches {
+		match := &matches[i]
+		switch {
+		case match.Code == grandFinalResetCode && match.Status == model.MatchCompleted:
+			return match
+		case match.Code == grandFinalCode:
+			final = match
+		case match.Bracket == model.WinnersBracket && match.WinnerTo == "":
+			final = match
+		}
+	}
+	return final
+}
+
+// startMatches moves a tournament to ongoing through the transition table and returns its opening matches,
+// the caller creates them in the transaction that saves the status change
+func startMatches(tournament *model.Tournament) ([]model.Match, error) {
+	if !CanTransition(tournament.Status, model.Ongoing) {
+		return nil, fmt.Errorf("%w: %s -> %s", ErrInvalidTransition, tournament.Status, model.Ongoing)
+	}
+	// Every swiss round needs an opponent a player has not met yet
+	if players := len(entrants(tournament)); tournament.Format == model.Swiss && tournament.Rounds > players-1 {
+		return nil, fmt.Errorf("%w: %d rounds need %d players, %d joined", ErrNotEnoughPlayers, tournament.Rounds, tournament.Rounds+1, players)
+	}
+	matches, err := openingMatches(tournament)
+	if err != nil {
+		return nil, err
+	}
+	tournament.Status = model.Ongoing
+	return matches, nil
+}
+
+// openingMatches generates the opening matches of a tournament from its roster
+func openingMatches(tournament *model.Tournament) ([]model.Match, error) {
+	players := SeedPlayers(entrants(tournament), tournament.Seeding)
+
+	switch tournament.Format {
+	case model.SingleElimination:
+		return GenerateSingleElimination(tournament.ID, players), nil
+	case model.DoubleElimination:
+		return GenerateDoubleElimination(tournament.ID, players, tournament.GrandFinalReset), nil
+	case model.RoundRobin:
+		return GenerateRoundRobin(tournament.ID, players), nil
+	case model.GroupStage:
+		return GenerateGroupStage(tournament.ID, players, tournament.GroupSize), nil
+	case model.Swiss:
+		return PairSwissRound(tournament.ID, 1, players, nil)
+	default:
+		return nil, fmt.Errorf("unknown tournament format %q", tournament.Format)
+	}
+}
+
+// openStandings writes the standings of a tournament that just started to its leaderboard,
+// a leaderboard made of standings starts as a table without results
+func openStandings(tournament *model.Tournament) error {
+	if !hasStandings(tournament.Format) {
+		return nil
+	}
+	_, err := refreshStandings(tournament)
+	return err
+}
+
+// GetBracket returns the bracket of a tournament as a tree
+func GetBracket(tournamentID uint) (*Bracket, error) {
+	matches, err := crud.GetMatchesByTournamentID(tournamentID)
+	if err != nil {
+		return nil, err
+	}
+	index := matchIndex(matches)
+
+	bracket := &Bracket{}
+	for _, match := range matches {
+		switch match.Bracket {
+		case model.GrandFinalBracket:
+			bracket.GrandFinal = append(bracket.GrandFinal, match)
+		case model.WinnersBracket, model.LosersBracket:
+			// the root of a side is the match whose winner leaves that side
+			next, ok := index[match.WinnerTo]
+			if ok && matches[next].Bracket == match.Bracket {
+				continue
+			}
+			if match.Bracket == model.WinnersBracket {
+				bracket.Winners = bracketTree(matches, match)
+			} else {
+				bracket.Losers = bracketTree(matches, match)
+			}
+		}
+	}
+	return bracket, nil
+}
+
+// bracketTree builds the node of a match with the matches of the same side feeding into it as children, slot 1 first
+func bracketTree(matches []model.Match, root model.Match) *BracketNode {
+	node := &BracketNode{Match: root}
+	for slot := 1; slot <= 2; slot++ {
+		for _, match := range matches {
+			if match.Bracket == root.Bracket && match.WinnerTo == root.Code && match.WinnerToSlot == slot {
+				node.Children = append(node.Children, bracketTree(matches, match))
+			}
+		}
+	}
+	return node
+}
@@ -0,0 +1,350 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"tournament-app/internal/crud"
+	"tournament-app/model"
+	"tournament-app/validation"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrAlreadyInClan is returned when a user who belongs to a clan joins or creates another one
+	ErrAlreadyInClan = errors.New("user is already in a clan")
+	// ErrNotInClan is returned when the user is not a member of the clan
+	ErrNotInClan = errors.New("user is not a member of the clan")
+	// ErrClanNameTaken is returned when a clan is created with the name of another clan
+	ErrClanNameTaken = errors.New("clan name is already taken")
+	// ErrClanPermission is returned when the user's clan role does not allow the action
+	ErrClanPermission = errors.New("clan role does not allow this action")
+	// ErrAlreadyInvited is returned when the user already has a pending invitation to the clan
+	ErrAlreadyInvited = errors.New("user already has a pending invitation to the clan")
+	// ErrInvitationAnswered is returned when an invitation that was accepted or declined is answered again
+	ErrInvitationAnswered = errors.New("clan invitation was already answered")
+	// ErrLeaderLeaving is returned when the leader leaves their clan without handing over the leadership
+	ErrLeaderLeaving = errors.New("the clan leader cannot leave the clan")
+	// ErrTreasuryNotEmpty is returned when a clan with money in its treasury is deleted
+	ErrTreasuryNotEmpty = errors.New("clan treasury is not empty")
+	// ErrInsufficientTreasury is returned when more money is paid out than the clan treasury holds
+	ErrInsufficientTreasury = errors.New("clan treasury does not have enough money")
+)
+
+// clanRoleRank orders the clan roles, a member may only remove members of a lower rank
+var clanRoleRank = map[model.ClanRole]int{
+	model.MemberRole:  1,
+	model.OfficerRole: 2,
+	model.LeaderRole:  3,
+}
+
+// CreateClan creates a clan led by the given user, who must not be in a clan yet
+func CreateClan(clan *model.Clan, leaderID uint) error {
+	clan.Treasury = 0
+	clan.Members = nil
+	if err := validation.ValidateClan(clan); err != nil {
+		return err
+	}
+	memberships, err := crud.GetClanMemberships([]uint{leaderID})
+	if err != nil {
+		return err
+	}
+	if len(memberships) > 0 {
+		return ErrAlreadyInClan
+	}
+
+	err = crud.CreateClan(clan, leaderID)
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return ErrClanNameTaken
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := crud.AddClanToLeaderboard(clan.ID); err != nil {
+		return err
+	}
+	return crud.AddClanMemberResults(clan.ID, leaderID)
+}
+
+func GetClanByID(id uint) (*model.Clan, error) {
+	return crud.GetClanByID(id)
+}
+
+func GetClans() ([]model.Clan, error) {
+	return crud.GetClans()
+}
+
+// GetClanRankings returns the clans ranked by the tournament points of their members from Redis
+func GetClanRankings(start, stop int64) ([]model.ClanRanking, error) {
+	return crud.GetClanLeaderboard(start, stop)
+}
+
+// GetClanInvitations returns the clan invitations a user has not answered yet
+func GetClanInvitations(userID uint) ([]model.ClanInvitation, error) {
+	if _, err := crud.GetUserByID(userID); err != nil {
+		return nil, err
+	}
+	return crud.GetPendingClanInvitations(userID)
+}
+
+// InviteToClan lets the leader or an officer of a clan invite a user who is not in a clan
+func InviteToClan(clanID, inviterID, userID uint) (*model.ClanInvitation, error) {
+	memberships, err := crud.GetClanMemberships([]uint{userID})
+	if err != nil {
+		return nil, err
+	}
+	if len(memberships) > 0 {
+		return nil, ErrAlreadyInClan
+	}
+
+	invitation := model.ClanInvitation{ClanID: clanID, UserID: userID, InvitedBy: inviterID, Status: model.InvitationPending}
+	err = crud.InviteToClan(&invitation, func(clan *model.Clan, user *model.User, pending []model.ClanInvitation) error {
+		if clanRoleRank[clanRole(clan, inviterID)] < clanRoleRank[model.OfficerRole] {
+			return fmt.Errorf("%w: only the leader and officers invite users", ErrClanPermission)
+		}
+		if len(pending) > 0 {
+			return ErrAlreadyInvited
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+// AcceptClanInvitation makes the invited user a member of the clan, their tournament points
+// so far are added to the clan's score
+func AcceptClanInvitation(invitationID, userID uint) (*model.Clan, error) {
+	invitation, clan, err := crud.AnswerClanInvitation(invitationID, func(invitation *model.ClanInvitation, clan *model.Clan) error {
+		if err := answerInvitation(invitation, userID); err != nil {
+			return err
+		}
+		invitation.Status = model.InvitationAccepted
+		return nil
+	})
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return nil, ErrAlreadyInClan
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := crud.AddClanMemberResults(clan.ID, invitation.UserID); err != nil {
+		return nil, err
+	}
+	return clan, nil
+}
+
+// DeclineClanInvitation turns down a clan invitation
+func DeclineClanInvitation(invitationID, userID uint) error {
+	_, _, err := crud.AnswerClanInvitation(invitationID, func(invitation *model.ClanInvitation, clan *model.Clan) error {
+		if err := answerInvitation(invitation, userID); err != nil {
+			return err
+		}
+		invitation.Status = model.InvitationDeclined
+		return nil
+	})
+	return err
+}
+
+// answerInvitation checks that the invitation is the user's own and still pending
+func answerInvitation(invitation *model.ClanInvitation, userID uint) error {
+	if invitation.UserID != userID {
+		return fmt.Errorf("%w: only the invited user answers an invitation", ErrClanPermission)
+	}
+	if invitation.Status != model.InvitationPending {
+		return ErrInvitationAnswered
+	}
+	return nil
+}
+
+// LeaveClan takes a member other than the leader out of their clan together with their tournament points
+func LeaveClan(clanID, userID uint) error {
+	clan, err := crud.RemoveClanMember(clanID, userID, func(clan *model.Clan) error {
+		switch clanRole(clan, userID) {
+		case "":
+			return ErrNotInClan
+		case model.LeaderRole:
+			return fmt.Errorf("%w: hand over the leadership or delete the clan", ErrLeaderLeaving)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return crud.RemoveClanMemberResults(clan.ID, userID)
+}
+
+// KickClanMember lets the leader remove anyone and an officer remove members from the clan
+func KickClanMember(clanID, actorID, userID uint) error {
+	clan, err := crud.RemoveClanMember(clanID, userID, func(clan *model.Clan) error {
+		role := clanRole(clan, userID)
+		if role == "" {
+			return ErrNotInClan
+		}
+		actor := clanRole(clan, actorID)
+		if clanRoleRank[actor] < clanRoleRank[model.OfficerRole] || clanRoleRank[actor] <= clanRoleRank[role] {
+			return fmt.Errorf("%w: a %s cannot remove a %s", ErrClanPermission, actor, role)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return crud.RemoveClanMemberResults(clan.ID, userID)
+}
+
+// SetClanRole lets the leader change the role of another member. Making a member the leader
+// hands over the leadership, the former leader becomes an officer.
+func SetClanRole(clanID, actorID, userID uint, role model.ClanRole) (*model.Clan, error) {
+	if err := validation.ValidateClanRole(role); err != nil {
+		return nil, err
+	}
+	return crud.UpdateClanRoles(clanID, func(clan *model.Clan) ([]model.ClanMember, error) {
+		if clanRole(clan, actorID) != model.LeaderRole {
+			return nil, fmt.Errorf("%w: only the leader changes roles", ErrClanPermission)
+		}
+		if clanRole(clan, userID) == "" {
+			return nil, ErrNotInClan
+		}
+		if userID == actorID {
+			return nil, fmt.Errorf("%w: the leader hands over the leadership to another member", ErrClanPermission)
+		}
+
+		var changed []model.ClanMember
+		for i := range clan.Members {
+			member := &clan.Members[i]
+			switch {
+			case member.UserID == userID:
+				member.Role = role
+			case member.UserID == actorID && role == model.LeaderRole:
+				member.Role = model.OfficerRole
+			default:
+				continue
+			}
+			changed = append(changed, *member)
+		}
+		return changed, nil
+	})
+}
+
+// DepositToClan moves money from a member's wallet into their clan's treasury
+func DepositToClan(clanID, userID uint, amount int) (*model.Clan, error) {
+	if amount <= 0 {
+		return nil, errors.New("deposit amount must be positive")
+	}
+	clan, user, err := crud.ApplyClanTreasury(clanID, userID, model.ClanDepositTransaction, func(clan *model.Clan, user *model.User) error {
+		if clanRole(clan, user.ID) == "" {
+			return ErrNotInClan
+		}
+		if user.Money < amount {
+			return fmt.Errorf("insufficient funds")
+		}
+		user.Money -= amount
+		clan.Treasury += amount
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := crud.UpdateLeaderboard(fmt.Sprintf("%d", user.ID), model.CalculateScore(user)); err != nil {
+		return nil, err
+	}
+	return clan, nil
+}
+
+// PayOutFromClan lets the leader pay money from the clan treasury to a member
+func PayOutFromClan(clanID, actorID, userID uint, amount int) (*model.Clan, error) {
+	if amount <= 0 {
+		return nil, errors.New("payout amount must be positive")
+	}
+	clan, user, err := crud.ApplyClanTreasury(clanID, userID, model.ClanPayoutTransaction, func(clan *model.Clan, user *model.User) error {
+		if clanRole(clan, actorID) != model.LeaderRole {
+			return fmt.Errorf("%w: only the leader pays out of the treasury", ErrClanPermission)
+		}
+		if clanRole(clan, user.ID) == "" {
+			return ErrNotInClan
+		}
+		if clan.Treasury < amount {
+			return fmt.Errorf("%w: %d of %d", ErrInsufficientTreasury, clan.Treasury, amount)
+		}
+		clan.Treasury -= amount
+		user.Money += amount
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := crud.UpdateLeaderboard(fmt.Sprintf("%d", user.ID), model.CalculateScore(user)); err != nil {
+		return nil, err
+	}
+	return clan, nil
+}
+
+// DeleteClan lets the leader delete a clan with an empty treasury
+func DeleteClan(clanID, actorID uint) error {
+	clan, err := crud.DeleteClan(clanID, func(clan *model.Clan) error {
+		if clanRole(clan, actorID) != model.LeaderRole {
+			return fmt.Errorf("%w: only the leader deletes the clan", ErrClanPermission)
+		}
+		if clan.Treasury > 0 {
+			return fmt.Errorf("%w: %d left", ErrTreasuryNotEmpty, clan.Treasury)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return crud.RemoveClanFromLeaderboard(clan.ID)
+}
+
+// recordClanResults adds the points every player made in a finished tournament to their results,
+// to their clan's score and to the season's leaderboard. Each member of a team gets the points of their team.
+// A season that was archived before the tournament finished gets the points added to its final standings.
+func recordClanResults(tournament *model.Tournament, leaderboard []model.Leaderboard, seasonID uint) error {
+	points := map[uint]float64{}
+	for _, entry := range leaderboard {
+		if entry.TeamID == 0 {
+			points[entry.UserID] += entry.Score
+			continue
+		}
+		memberIDs, err := crud.GetTeamEntryMembers(tournament.ID, entry.TeamID)
+		if err != nil {
+			return err
+		}
+		for _, memberID := range memberIDs {
+			points[memberID] += entry.Score
+		}
+	}
+
+	userIDs := make([]uint, 0, len(points))
+	for userID := range points {
+		userIDs = append(userIDs, userID)
+	}
+	memberships, err := crud.GetClanMemberships(userIDs)
+	if err != nil {
+		return err
+	}
+	clans := make(map[uint]uint, len(memberships))
+	for _, membership := range memberships {
+		clans[membership.UserID] = membership.ClanID
+	}
+	if seasonID == 0 {
+		return crud.AddTournamentResults(points, clans, 0)
+	}
+	return crud.AddSeasonTournamentResults(points, clans, seasonID)
+}
+
+// clanRole returns the role of the user in the clan, or an empty role when they are not a member
+func clanRole(clan *model.Clan, userID uint) model.ClanRole {
+	for _, member := range clan.Members {
+		if member.UserID == userID {
+			return member.Role
+		}
+	}
+	return ""
+}
@@ -0,0 +1,211 @@
+package service
+
+import (
+	"sort"
+	"tournament-app/internal/crud"
+	"tournament-app/model"
+)
+
+// GroupStandings is the standings table of one group of a group stage tournament
+type GroupStandings struct {
+	Group     int              `json:"group"`
+	Standings []model.Standing `json:"standings"`
+}
+
+// SplitGroups deals the players, in seed order, into groups of about groupSize players with snake seeding:
+// seeds 1..n go to groups 1..n, the next seeds back from group n to 1, and so on.
+// There are never so many groups that one of them ends up with a single player.
+func SplitGroups(players []model.User, groupSize int) [][]model.User {
+	if len(players) == 0 || groupSize < 1 {
+		return nil
+	}
+
+	count := (len(players) + groupSize - 1) / groupSize
+	if count > len(players)/2 {
+		count = len(players) / 2
+	}
+	if count < 1 {
+		count = 1
+	}
+
+	groups := make([][]model.User, count)
+	for i, player := range players {
+		group := i % count
+		if (i/count)%2 == 1 {
+			group = count - 1 - group
+		}
+		groups[group] = append(groups[group], player)
+	}
+	return groups
+}
+
+// GenerateGroupStage splits the players in seed order into groups that each play a round robin
+func GenerateGroupStage(tournamentID uint, players []model.User, groupSize int) []model.Match {
+	var matches []model.Match
+	for i, group := range SplitGroups(players, groupSize) {
+		for _, match := range GenerateRoundRobin(tournamentID, group) {
+			match.Group = i + 1
+			matches = append(matches, match)
+		}
+	}
+	return matches
+}
+
+// ComputeGroupStandings builds the standings table of every group from the group matches,
+// a player belongs to the group they have matches in
+func ComputeGroupStandings(players []model.User, matches []model.Match) []GroupStandings {
+	members := map[int]map[uint]bool{}
+	groupMatches := map[int][]model.Match{}
+	for _, match := range matches {
+		if match.Group == 0 {
+			continue
+		}
+		if members[match.Group] == nil {
+			members[match.Group] = map[uint]bool{}
+		}
+		for _, playerID := range []*uint{match.Player1ID, match.Player2ID} {
+			if playerID != nil {
+				members[match.Group][*playerID] = true
+			}
+		}
+		groupMatches[match.Group] = append(groupMatches[match.Group], match)
+	}
+
+	groups := make([]GroupStandings, 0, len(members))
+	for group := range members {
+		var users []model.User
+		for _, player := range players {
+			if members[group][player.ID] {
+				users = append(users, player)
+			}
+		}
+		standings := ComputeStandings(users, groupMatches[group])
+		for i := range standings {
+			standings[i].Group = group
+		}
+		groups = append(groups, GroupStandings{Group: group, Standings: standings})
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].Group < groups[j].Group
+	})
+	return groups
+}
+
+// GenerateGroupKnockout builds the single-elimination playoff for the top advance players of each group.
+// The qualifiers are seeded by their group rank first, so group winners get the top seeds,
+// then by points, score difference and scores made. The knockout is only built once every group match is played.
+func GenerateGroupKnockout(tournamentID uint, players []model.User, matches []model.Match, advance int) []model.Match {
+	if !groupStageFinished(matches) {
+		return nil
+	}
+
+	var qualifiers []model.User
+	for _, standing := range orderGroupRanks(ComputeGroupStandings(players, matches), advance) {
+		qualifiers = append(qualifiers, model.User{ID: standing.UserID})
+	}
+	return GenerateSingleElimination(tournamentID, qualifiers)
+}
+
+// orderGroupRanks lists the standings rows with rank up to maxRank across all groups, best group rank first,
+// rows of the same group rank by points, score difference, scores made and group
+func orderGroupRanks(groups []GroupStandings, maxRank int) []model.Standing {
+	var rows []model.Standing
+	for _, group := range groups {
+		for _, standing := range group.Standings {
+			if standing.Rank <= maxRank {
+				rows = append(rows, standing)
+			}
+		}
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		a, b := rows[i], rows[j]
+		switch {
+		case a.Rank != b.Rank:
+			return a.Rank < b.Rank
+		case a.Points != b.Points:
+			return a.Points > b.Points
+		case a.ScoreDifference() != b.ScoreDifference():
+			return a.ScoreDifference() > b.ScoreDifference()
+		case a.ScoreFor != b.ScoreFor:
+			return a.ScoreFor > b.ScoreFor
+		default:
+			return a.Group < b.Group
+		}
+	})
+	return rows
+}
+
+// groupStageFinished reports whether every group match has a result and the knockout was not built yet
+func groupStageFinished(matches []model.Match) bool {
+	groupMatches := 0
+	for _, match := range matches {
+		switch {
+		case match.Group == 0:
+			return false
+		case match.Status == model.MatchPending:
+			return false
+		}
+		groupMatches++
+	}
+	return groupMatches > 0
+}
+
+// RankGroupStage ranks all players of a group stage tournament: the knockout players by how far they got,
+// the champion first, then everyone else by group rank, points, score difference and scores made
+func RankGroupStage(players []model.User, matches []model.Match) []model.Standing {
+	totals := map[uint]model.Standing{}
+	for _, standing := range tallyStandings(players, matches) {
+		totals[standing.UserID] = standing
+	}
+
+	// reached is the furthest knockout round a player got to, the champion one round past the final
+	reached := map[uint]int{}
+	for _, match := range matches {
+		if match.Bracket == "" {
+			continue
+		}
+		for _, playerID := range []*uint{match.Player1ID, match.Player2ID} {
+			if playerID != nil && match.Round > reached[*playerID] {
+				reached[*playerID] = match.Round
+			}
+		}
+		if match.WinnerTo == "" && match.WinnerID != nil {
+			reached[*match.WinnerID] = match.Round + 1
+		}
+	}
+
+	groupRows := orderGroupRanks(ComputeGroupStandings(players, matches), len(players))
+	order := make(map[uint]int, len(groupRows))
+	for i, row := range groupRows {
+		order[row.UserID] = i
+	}
+
+	ranking := make([]model.Standing, 0, len(groupRows))
+	for _, row := range groupRows {
+		ranking = append(ranking, totals[row.UserID])
+	}
+	sort.SliceStable(ranking, func(i, j int) bool {
+		a, b := ranking[i].UserID, ranking[j].UserID
+		if reached[a] != reached[b] {
+			return reached[a] > reached[b]
+		}
+		return order[a] < order[b]
+	})
+	for i := range ranking {
+		ranking[i].Rank = i + 1
+	}
+	return ranking
+}
+
+// GetGroupStandings returns the standings table of every group of a tournament
+func GetGroupStandings(tournamentID uint) ([]GroupStandings, error) {
+	tournament, err := crud.GetTournamentByID(tournamentID)
+	if err != nil {
+		return nil, err
+	}
+	matches, err := crud.GetMatchesByTournamentID(tournamentID)
+	if err != nil {
+		return nil, err
+	}
+	return ComputeGroupStandings(entrants(tournament), matches), nil
+}
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"tournament-app/internal/crud"
+	"tournament-app/model"
+)
+
+// ErrNotRanked is returned when the user has no entry on the requested leaderboard
+var ErrNotRanked = errors.New("user is not on the leaderboard")
+
+// MaxAroundRadius caps the number of entries shown above and below a user
+const MaxAroundRadius = 50
+
+// GetLeaderboard returns a range of the global leaderboard of the window with each user's profile data,
+// an empty window reads the all-time board
+func GetLeaderboard(window model.LeaderboardWindow, start, stop int64) ([]model.LeaderboardEntry, error) {
+	window, err := leaderboardWindow(window)
+	if err != nil {
+		return nil, err
+	}
+	leaderboard, err := crud.GetLeaderboard(window, start, stop)
+	if err != nil {
+		return nil, err
+	}
+	return enrichEntries(nil, rankEntries(leaderboard, start))
+}
+
+// GetLeaderboardRank returns the user's entry on a tournament's live leaderboard, or on the global
+// leaderboard of the window when no tournament is given. On a team tournament's board the user
+// is found through the team they entered with.
+func GetLeaderboardRank(userID, tournamentID uint, window model.LeaderboardWindow) (*model.LeaderboardEntry, error) {
+	entries, err := GetLeaderboardAround(userID, tournamentID, window, 0)
+	if err != nil {
+		return nil, err
+	}
+	// Without a radius the user's own entry is the only one
+	return &entries[0], nil
+}
+
+// GetLeaderboardAround returns the user's entry with up to radius entries above and below it,
+// on the same leaderboards as GetLeaderboardRank
+func GetLeaderboardAround(userID, tournamentID uint, window model.LeaderboardWindow, radius int64) ([]model.LeaderboardEntry, error) {
+	if radius < 0 {
+		radius = 0
+	}
+	if radius > MaxAroundRadius {
+		radius = MaxAroundRadius
+	}
+
+	var tournament *model.Tournament
+	member := userID
+	if tournamentID != 0 {
+		if window != "" && window != model.AllTimeWindow {
+			return nil, fmt.Errorf("%w: tournament leaderboards have no time windows", ErrInvalidWindow)
+		}
+		var err error
+		tournament, err = crud.GetTournamentByID(tournamentID)
+		if err != nil {
+			return nil, err
+		}
+		if tournament.TeamEntry {
+			member = 0
+			for i := range tournament.Teams {
+				if isMember(&tournament.Teams[i], userID) {
+					member = tournament.Teams[i].ID
+				}
+			}
+			if member == 0 {
+				return nil, fmt.Errorf("%w: user %d has no team in the tournament", ErrNotRanked, userID)
+			}
+		}
+	} else {
+		var err error
+		window, err = leaderboardWindow(window)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	entries, found, err := crud.GetLeaderboardAround(tournamentID, window, member, radius)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("%w: user %d", ErrNotRanked, userID)
+	}
+	return enrichEntries(tournament, entries)
+}
+
+// rankEntries turns a range of a leaderboard read from the given start rank into ranked entries
+func rankEntries(leaderboard []model.Leaderboard, start int64) []model.LeaderboardEntry {
+	entries := make([]model.LeaderboardEntry, 0, len(leaderboard))
+	for i, entry := range leaderboard {
+		entries = append(entries, model.LeaderboardEntry{
+			Rank:   int(start) + i + 1,
+			UserID: entry.UserID,
+			Score:  entry.Score,
+		})
+	}
+	return entries
+}
+
+// enrichEntries fills in the profile data of ranked entries, the users are read in a single query
+// instead of one lookup per entry. A team tournament's entries are its teams, named after the team
+// and ranked under their captains.
+func enrichEntries(tournament *model.Tournament, entries []model.LeaderboardEntry) ([]model.LeaderboardEntry, error) {
+	if tournament != nil && tournament.TeamEntry {
+		for i := range entries {
+			entries[i].TeamID = entries[i].UserID
+			if team := tournamentTeam(tournament, entries[i].TeamID); team != nil {
+				entries[i].UserID = team.CaptainID
+				entries[i].Name = team.Name
+			}
+		}
+		return entries, nil
+	}
+
+	ids := make([]uint, 0, len(entries))
+	for _, entry := range entries {
+		ids = append(ids, entry.UserID)
+	}
+	users, err := crud.GetUsersByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	profiles := make(map[uint]*model.User, len(users))
+	for i := range users {
+		profiles[users[i].ID] = &users[i]
+	}
+	for i := range entries {
+		if user, ok := profiles[entries[i].UserID]; ok {
+			entries[i].Name = user.Name
+			entries[i].Level = user.Level
+			entries[i].Avatar = user.Avatar
+		}
+	}
+	return entries, nil
+}
@@ -0,0 +1,179 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"tournament-app/internal/crud"
+	"tournament-app/model"
+	"tournament-app/validation"
+)
+
+// Points a player earns on the tournament leaderboard for a match result
+const (
+	winPoints  = 3
+	drawPoints = 1
+)
+
+var (
+	// ErrMatchNotInTournament is returned when the match belongs to another tournament
+	ErrMatchNotInTournament = errors.New("match does not belong to the tournament")
+	// ErrMatchCompleted is returned when a result is reported for a match that already has one
+	ErrMatchCompleted = errors.New("match result was already reported")
+	// ErrDrawNotAllowed is returned when a bracket match is reported as a draw
+	ErrDrawNotAllowed = errors.New("bracket matches cannot end in a draw")
+	// ErrMatchNotReady is returned when a result is reported before both players are known
+	ErrMatchNotReady = errors.New("match does not have two players yet")
+)
+
+// CreateMatch adds a pending match between two players of an ongoing tournament
+func CreateMatch(match *model.Match) error {
+	tournament, err := crud.GetTournamentByID(match.TournamentID)
+	if err != nil {
+		return err
+	}
+	if tournament.Status != model.Ongoing {
+		return fmt.Errorf("%w: matches can only be added to an ongoing tournament", ErrInvalidTransition)
+	}
+	for _, playerID := range []*uint{match.Player1ID, match.Player2ID} {
+		if playerID != nil && !isEntrant(tournament, *playerID) {
+			return fmt.Errorf("%w: user %d", ErrNotJoined, *playerID)
+		}
+	}
+
+	match.Status = model.MatchPending
+	match.WinnerID = nil
+	match.Player1Score, match.Player2Score = 0, 0
+	if match.Round == 0 {
+		match.Round = 1
+	}
+	if err := validation.ValidateMatch(match); err != nil {
+		return err
+	}
+	return crud.CreateMatch(match)
+}
+
+func GetMatchesByTournamentID(tournamentID uint) ([]model.Match, error) {
+	return crud.GetMatchesByTournamentID(tournamentID)
+}
+
+// ReportMatchResult records the scores of a pending match, the higher score wins and equal scores are a draw.
+// Bracket matches cannot end in a draw, their winner and loser move on to their next matches.
+// The standings of a round-robin, swiss or group stage tournament are recomputed from all of its results,
+// the knockout of a group stage tournament is built with its last group match result.
+// Both players are rated from the result, teams of a team tournament are not rated.
+// The tournament's leaderboard in Redis is updated once the result is saved.
+func ReportMatchResult(tournamentID, matchID uint, player1Score, player2Score int) (*model.Match, error) {
+	var tournament *model.Tournament
+	var reported *model.Match
+	_, err := crud.ReportMatchResult(tournamentID, func(locked *model.Tournament, matches []model.Match) ([]model.Match, error) {
+		// The status is checked on the locked row, a tournament being finalized takes no more results
+		if locked.Status != model.Ongoing {
+			return nil, fmt.Errorf("%w: results can only be reported for an ongoing tournament", ErrInvalidTransition)
+		}
+		tournament = locked
+
+		i := -1
+		for j := range matches {
+			if matches[j].ID == matchID {
+				i = j
+			}
+		}
+		if i < 0 {
+			return nil, ErrMatchNotInTournament
+		}
+
+		var changed []int
+		if matches[i].Bracket != "" {
+			indexes, err := ApplyBracketResult(matches, matches[i].Code, player1Score, player2Score)
+			if err != nil {
+				return nil, err
+			}
+			changed = indexes
+		} else {
+			if err := applyMatchResult(&matches[i], player1Score, player2Score); err != nil {
+				return nil, err
+			}
+			changed = []int{i}
+		}
+
+		saved := make([]model.Match, 0, len(changed))
+		for _, j := range changed {
+			if err := validation.ValidateMatch(&matches[j]); err != nil {
+				return nil, err
+			}
+			saved = append(saved, matches[j])
+		}
+		reported = &matches[i]
+
+		// The last group match brings the knockout
+		if tournament.Format == model.GroupStage && matches[i].Group != 0 {
+			saved = append(saved, GenerateGroupKnockout(tournamentID, entrants(tournament), matches, tournament.AdvancePerGroup)...)
+		}
+		return saved, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// A leaderboard made of standings depends on all results so far, knockout matches add their points
+	if hasStandings(tournament.Format) && reported.Bracket == "" {
+		if _, err := refreshStandings(tournament); err != nil {
+			return nil, err
+		}
+	} else if err := applyMatchToLeaderboard(reported); err != nil {
+		return nil, err
+	}
+
+	if !tournament.TeamEntry {
+		if err := rateMatch(reported); err != nil {
+			return nil, err
+		}
+	}
+	return reported, nil
+}
+
+// applyMatchResult records the scores of a match outside a bracket, equal scores are a draw
+func applyMatchResult(match *model.Match, player1Score, player2Score int) error {
+	if match.Status != model.MatchPending {
+		return ErrMatchCompleted
+	}
+	if match.Player1ID == nil || match.Player2ID == nil {
+		return ErrMatchNotReady
+	}
+
+	match.Player1Score = player1Score
+	match.Player2Score = player2Score
+	match.Status = model.MatchCompleted
+	switch {
+	case player1Score > player2Score:
+		match.WinnerID = match.Player1ID
+	case player2Score > player1Score:
+		match.WinnerID = match.Player2ID
+	default:
+		match.WinnerID = nil
+	}
+	return nil
+}
+
+// applyMatchToLeaderboard gives the winner win points, or both players draw points
+func applyMatchToLeaderboard(match *model.Match) error {
+	if match.WinnerID != nil {
+		return crud.IncrementTournamentLeaderboard(match.TournamentID, fmt.Sprintf("%d", *match.WinnerID), winPoints)
+	}
+	for _, playerID := range []*uint{match.Player1ID, match.Player2ID} {
+		if err := crud.IncrementTournamentLeaderboard(match.TournamentID, fmt.Sprintf("%d", *playerID), drawPoints); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// hasJoined reports whether the user is on the tournament's roster
+func hasJoined(tournament *model.Tournament, userID uint) bool {
+	for _, user := range tournament.Users {
+		if user.ID == userID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,272 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"sync"
+	"time"
+	"tournament-app/internal/crud"
+	"tournament-app/model"
+	"tournament-app/validation"
+)
+
+var (
+	// ErrAlreadyQueued is returned when a user joins the matchmaking queue twice
+	ErrAlreadyQueued = errors.New("user is already in the matchmaking queue")
+	// ErrNotQueued is returned when a user leaves the matchmaking queue without being in it
+	ErrNotQueued = errors.New("user is not in the matchmaking queue")
+)
+
+// Clock tells the time, tests replace it with a fake one
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+// MatchmakingConfig controls how far apart in rating matched players may be and how often the queue is matched
+type MatchmakingConfig struct {
+	// BaseWindow is the rating difference a player accepts right after joining
+	BaseWindow float64
+	// WidenPerSecond is added to the window for every second spent waiting
+	WidenPerSecond float64
+	// MaxWindow caps the window however long the player waits
+	MaxWindow float64
+	// Interval is the time between two matching passes of the worker
+	Interval time.Duration
+}
+
+// DefaultMatchmakingConfig starts at 100 rating points apart and reaches 800 after 70 seconds
+var DefaultMatchmakingConfig = MatchmakingConfig{
+	BaseWindow:     100,
+	WidenPerSecond: 10,
+	MaxWindow:      800,
+	Interval:       time.Second,
+}
+
+// RatingWindow returns the rating difference a player accepts after waiting for the given time
+func (c MatchmakingConfig) RatingWindow(waited time.Duration) float64 {
+	if waited < 0 {
+		waited = 0
+	}
+	return math.Min(c.BaseWindow+c.WidenPerSecond*waited.Seconds(), c.MaxWindow)
+}
+
+// PairQueue matches the queued players whose ratings are close enough. The longest waiting players
+// are matched first, each with the closest rated player still unmatched, as long as the difference
+// fits the window of the one who waited longer.
+func PairQueue(queue []model.QueueEntry, now time.Time, config MatchmakingConfig) [][2]model.QueueEntry {
+	waiting := append([]model.QueueEntry(nil), queue...)
+	sort.SliceStable(waiting, func(i, j int) bool {
+		if !waiting[i].JoinedAt.Equal(waiting[j].JoinedAt) {
+			return waiting[i].JoinedAt.Before(waiting[j].JoinedAt)
+		}
+		return waiting[i].UserID < waiting[j].UserID
+	})
+
+	matched := make([]bool, len(waiting))
+	var pairs [][2]model.QueueEntry
+	for i, player := range waiting {
+		if matched[i] {
+			continue
+		}
+		window := config.RatingWindow(now.Sub(player.JoinedAt))
+
+		best := -1
+		for j := i + 1; j < len(waiting); j++ {
+			if matched[j] {
+				continue
+			}
+			difference := math.Abs(waiting[j].Rating - player.Rating)
+			if difference > window {
+				continue
+			}
+			if best < 0 || difference < math.Abs(waiting[best].Rating-player.Rating) {
+				best = j
+			}
+		}
+		if best >= 0 {
+			matched[i], matched[best] = true, true
+			pairs = append(pairs, [2]model.QueueEntry{player, waiting[best]})
+		}
+	}
+	return pairs
+}
+
+// Matchmaker keeps matching the players in the matchmaking queue in the background
+type Matchmaker struct {
+	Clock  Clock
+	Config MatchmakingConfig
+	// CreateMatch stores the match made for two players
+	CreateMatch func(match *model.Match) error
+
+	mu   sync.Mutex
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewMatchmaker returns a matchmaker that creates its matches in the database
+func NewMatchmaker(clock Clock, config MatchmakingConfig) *Matchmaker {
+	return &Matchmaker{Clock: clock, Config: config, CreateMatch: crud.CreateMatch}
+}
+
+// Enqueue puts the user on the queue with their current rating
+func (m *Matchmaker) Enqueue(userID uint) error {
+	user, err := crud.GetUserByID(userID)
+	if err != nil {
+		return err
+	}
+	added, err := crud.EnqueueMatchmaking(model.QueueEntry{UserID: user.ID, Rating: user.Rating, JoinedAt: m.Clock.Now()})
+	if err != nil {
+		return err
+	}
+	if !added {
+		return ErrAlreadyQueued
+	}
+	return nil
+}
+
+// Dequeue takes the user off the queue
+func (m *Matchmaker) Dequeue(userID uint) error {
+	removed, err := crud.DequeueMatchmaking(userID)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return ErrNotQueued
+	}
+	return nil
+}
+
+// RunOnce matches the queue once and returns the matches it created. A pair is only matched
+// if both players are still queued, a pair whose match cannot be created goes back on the queue.
+func (m *Matchmaker) RunOnce() ([]model.Match, error) {
+	now := m.Clock.Now()
+	queue, err := crud.GetMatchmakingQueue(now)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []model.Match
+	for _, pair := range PairQueue(queue, now, m.Config) {
+		removed, err := crud.RemoveMatchedPair(pair[0].UserID, pair[1].UserID)
+		if err != nil {
+			return matches, err
+		}
+		if !removed {
+			continue
+		}
+
+		player1, player2 := pair[0].UserID, pair[1].UserID
+		match := model.Match{Round: 1, Player1ID: &player1, Player2ID: &player2, Status: model.MatchPending}
+		if err := validation.ValidateMatch(&match); err != nil {
+			requeue(pair)
+			return matches, err
+		}
+		if err := m.CreateMatch(&match); err != nil {
+			requeue(pair)
+			return matches, fmt.Errorf("failed to create match: %w", err)
+		}
+		matches = append(matches, match)
+	}
+	return matches, nil
+}
+
+// requeue puts a pair taken off the queue back on it with their original join times
+func requeue(pair [2]model.QueueEntry) {
+	for _, entry := range pair {
+		if _, err := crud.EnqueueMatchmaking(entry); err != nil {
+			log.Printf("Failed to put user %d back on the matchmaking queue: %v", entry.UserID, err)
+		}
+	}
+}
+
+// Start runs the matching passes in the background until Stop is called
+func (m *Matchmaker) Start() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.stop != nil {
+		return
+	}
+	m.stop = make(chan struct{})
+	m.done = make(chan struct{})
+
+	go func(stop, done chan struct{}) {
+		defer close(done)
+		ticker := time.NewTicker(m.Config.Interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				if _, err := m.RunOnce(); err != nil {
+					log.Printf("Matchmaking failed: %v", err)
+				}
+			}
+		}
+	}(m.stop, m.done)
+}
+
+// Stop ends the background matching and waits for a running pass to finish
+func (m *Matchmaker) Stop() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.stop == nil {
+		return
+	}
+	close(m.stop)
+	<-m.done
+	m.stop, m.done = nil, nil
+}
+
+// matchmaker is the queue served by the API
+var matchmaker = NewMatchmaker(systemClock{}, DefaultMatchmakingConfig)
+
+// StartMatchmaking starts matching the players of the matchmaking queue in the background
+func StartMatchmaking() {
+	matchmaker.Start()
+}
+
+// StopMatchmaking stops the background matching
+func StopMatchmaking() {
+	matchmaker.Stop()
+}
+
+// JoinMatchmakingQueue puts the user on the matchmaking queue
+func JoinMatchmakingQueue(userID uint) error {
+	return matchmaker.Enqueue(userID)
+}
+
+// LeaveMatchmakingQueue takes the user off the matchmaking queue
+func LeaveMatchmakingQueue(userID uint) error {
+	return matchmaker.Dequeue(userID)
+}
+
+// GetMatchmakingMatches returns the matches the matchmaking queue made for the user, the newest first
+func GetMatchmakingMatches(userID uint) ([]model.Match, error) {
+	return crud.GetMatchmakingMatchesByUserID(userID)
+}
+
+// ReportMatchmakingResult records the scores of a match made by the matchmaking queue and rates both players
+func ReportMatchmakingResult(matchID uint, player1Score, player2Score int) (*model.Match, error) {
+	match, err := crud.ReportMatchmakingResult(matchID, func(match *model.Match) error {
+		if err := applyMatchResult(match, player1Score, player2Score); err != nil {
+			return err
+		}
+		return validation.ValidateMatch(match)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := rateMatch(match); err != nil {
+		return nil, err
+	}
+	return match, nil
+}
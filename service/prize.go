@@ -0,0 +1,137 @@
+package service
+
+import (
+	"fmt"
+	"tournament-app/internal/crud"
+	"tournament-app/model"
+)
+
+// defaultPrizePercentages is used by tournaments without their own prize strategy
+var defaultPrizePercentages = []float64{50, 25, 12.5, 6.25}
+
+// PrizeStrategy splits a tournament's prize pool among the ranked players
+type PrizeStrategy interface {
+	// Payouts returns the prize for each rank, first place first, never paying out more than pool in total
+	Payouts(pool, players int) []int
+}
+
+// Payout is the prize a rank receives when the tournament is finalized
+type Payout struct {
+	Rank  int `json:"rank"`
+	Prize int `json:"prize"`
+}
+
+// NewPrizeStrategy builds the prize strategy described by the tournament's config
+func NewPrizeStrategy(config model.PrizeStrategyConfig) (PrizeStrategy, error) {
+	switch config.Type {
+	case "":
+		return percentageStrategy{percentages: defaultPrizePercentages}, nil
+	case model.PercentagePrize:
+		return percentageStrategy{percentages: config.Percentages}, nil
+	case model.FixedPrize:
+		return fixedStrategy{amounts: config.Amounts}, nil
+	case model.WinnerTakesAllPrize:
+		return winnerTakesAllStrategy{}, nil
+	case model.TopNSplitPrize:
+		if config.TopN < 1 {
+			return nil, fmt.Errorf("top_n_split prize strategy needs top_n of at least 1")
+		}
+		return topNSplitStrategy{n: config.TopN}, nil
+	default:
+		return nil, fmt.Errorf("unknown prize strategy %q", config.Type)
+	}
+}
+
+// percentageStrategy pays each rank a share of the pool
+type percentageStrategy struct {
+	percentages []float64
+}
+
+func (s percentageStrategy) Payouts(pool, players int) []int {
+	payouts := make([]int, 0, len(s.percentages))
+	for i := 0; i < len(s.percentages) && i < players; i++ {
+		payouts = append(payouts, int(float64(pool)*s.percentages[i]/100))
+	}
+	return capPayouts(pool, payouts)
+}
+
+// fixedStrategy pays each rank a fixed amount, e.g. 200/100/50
+type fixedStrategy struct {
+	amounts []int
+}
+
+func (s fixedStrategy) Payouts(pool, players int) []int {
+	payouts := make([]int, 0, len(s.amounts))
+	for i := 0; i < len(s.amounts) && i < players; i++ {
+		payouts = append(payouts, s.amounts[i])
+	}
+	return capPayouts(pool, payouts)
+}
+
+// winnerTakesAllStrategy pays the whole pool to the first place
+type winnerTakesAllStrategy struct{}
+
+func (winnerTakesAllStrategy) Payouts(pool, players int) []int {
+	if players < 1 {
+		return nil
+	}
+	return []int{pool}
+}
+
+// topNSplitStrategy splits the pool evenly among the first n ranks
+type topNSplitStrategy struct {
+	n int
+}
+
+func (s topNSplitStrategy) Payouts(pool, players int) []int {
+	n := s.n
+	if players < n {
+		n = players
+	}
+	if n < 1 {
+		return nil
+	}
+	payouts := make([]int, n)
+	for i := range payouts {
+		payouts[i] = pool / n
+	}
+	return payouts
+}
+
+// capPayouts cuts the payouts down so their sum never exceeds the pool
+func capPayouts(pool int, payouts []int) []int {
+	remaining := pool
+	for i, prize := range payouts {
+		if prize < 0 {
+			prize = 0
+		}
+		if prize > remaining {
+			prize = remaining
+		}
+		payouts[i] = prize
+		remaining -= prize
+	}
+	return payouts
+}
+
+// GetTournamentPayouts previews the payout plan for the given number of ranked players
+func GetTournamentPayouts(tournamentID uint, players int) ([]Payout, error) {
+	tournament, err := crud.GetTournamentByID(tournamentID)
+	if err != nil {
+		return nil, err
+	}
+	if players <= 0 {
+		players = len(entrants(tournament))
+	}
+
+	strategy, err := NewPrizeStrategy(tournament.PrizeStrategy)
+	if err != nil {
+		return nil, err
+	}
+
+	payouts := []Payout{}
+	for i, prize := range strategy.Payouts(tournament.Prize, players) {
+		payouts = append(payouts, Payout{Rank: i + 1, Prize: prize})
+	}
+	return payouts, nil
+}
@@ -0,0 +1,168 @@
+package service
+
+import (
+	"math"
+	"tournament-app/internal/crud"
+	"tournament-app/model"
+)
+
+// Rating is a skill rating system that rates the two players of a match
+type Rating interface {
+	// Update returns the ratings of both players after a match, score is 1 when player 1 won,
+	// 0.5 for a draw and 0 when player 2 won
+	Update(player1, player2 model.SkillRating, score float64) (model.SkillRating, model.SkillRating)
+}
+
+// ratingSystem rates the reported matches, Elo{K: 32} can be swapped in
+var ratingSystem Rating = Glicko2{Tau: 0.5}
+
+// glicko2Scale converts between the Glicko and the Glicko-2 scale
+const glicko2Scale = 173.7178
+
+// Glicko2 is the Glicko-2 rating system, Tau limits how fast the volatility changes (0.3 to 1.2)
+type Glicko2 struct {
+	Tau float64
+}
+
+// Update rates each player from the match as a rating period of its own
+func (g Glicko2) Update(player1, player2 model.SkillRating, score float64) (model.SkillRating, model.SkillRating) {
+	return g.Period(player1, []model.SkillRating{player2}, []float64{score}),
+		g.Period(player2, []model.SkillRating{player1}, []float64{1 - score})
+}
+
+// Period returns the player's rating after a rating period against the opponents with the given scores
+func (g Glicko2) Period(player model.SkillRating, opponents []model.SkillRating, scores []float64) model.SkillRating {
+	mu := (player.Rating - 1500) / glicko2Scale
+	phi := player.RatingDeviation / glicko2Scale
+	if len(opponents) == 0 {
+		// A player without games only grows more uncertain
+		return model.SkillRating{
+			Rating:          player.Rating,
+			RatingDeviation: math.Sqrt(phi*phi+player.Volatility*player.Volatility) * glicko2Scale,
+			Volatility:      player.Volatility,
+		}
+	}
+
+	// Estimated variance v and improvement delta from the game outcomes
+	var variance, improvement float64
+	for i, opponent := range opponents {
+		muJ := (opponent.Rating - 1500) / glicko2Scale
+		gJ := glicko2G(opponent.RatingDeviation / glicko2Scale)
+		expected := 1 / (1 + math.Exp(-gJ*(mu-muJ)))
+		variance += gJ * gJ * expected * (1 - expected)
+		improvement += gJ * (scores[i] - expected)
+	}
+	variance = 1 / variance
+	delta := variance * improvement
+
+	volatility := g.volatility(phi, player.Volatility, variance, delta)
+	phiStar := math.Sqrt(phi*phi + volatility*volatility)
+	newPhi := 1 / math.Sqrt(1/(phiStar*phiStar)+1/variance)
+	newMu := mu + newPhi*newPhi*improvement
+
+	return model.SkillRating{
+		Rating:          newMu*glicko2Scale + 1500,
+		RatingDeviation: newPhi * glicko2Scale,
+		Volatility:      volatility,
+	}
+}
+
+// volatility finds the new volatility with the Illinois algorithm from step 5 of the Glicko-2 paper
+func (g Glicko2) volatility(phi, sigma, variance, delta float64) float64 {
+	const epsilon = 0.000001
+	a := math.Log(sigma * sigma)
+	f := func(x float64) float64 {
+		ex := math.Exp(x)
+		return ex*(delta*delta-phi*phi-variance-ex)/(2*math.Pow(phi*phi+variance+ex, 2)) - (x-a)/(g.Tau*g.Tau)
+	}
+
+	A := a
+	var B float64
+	if delta*delta > phi*phi+variance {
+		B = math.Log(delta*delta - phi*phi - variance)
+	} else {
+		k := 1.0
+		for f(a-k*g.Tau) < 0 {
+			k++
+		}
+		B = a - k*g.Tau
+	}
+
+	fA, fB := f(A), f(B)
+	for math.Abs(B-A) > epsilon {
+		C := A + (A-B)*fA/(fB-fA)
+		fC := f(C)
+		if fC*fB <= 0 {
+			A, fA = B, fB
+		} else {
+			fA /= 2
+		}
+		B, fB = C, fC
+	}
+	return math.Exp(A / 2)
+}
+
+func glicko2G(phi float64) float64 {
+	return 1 / math.Sqrt(1+3*phi*phi/(math.Pi*math.Pi))
+}
+
+// Elo is the Elo rating system, K is the most a rating moves after one match
+type Elo struct {
+	K float64
+}
+
+// Update moves both ratings by K times the difference between the score and the expected score
+func (e Elo) Update(player1, player2 model.SkillRating, score float64) (model.SkillRating, model.SkillRating) {
+	expected := 1 / (1 + math.Pow(10, (player2.Rating-player1.Rating)/400))
+	change := e.K * (score - expected)
+	player1.Rating += change
+	player2.Rating -= change
+	return player1, player2
+}
+
+// matchScore is player 1's score of a completed match for the rating systems
+func matchScore(match *model.Match) float64 {
+	switch {
+	case match.WinnerID == nil:
+		return 0.5
+	case *match.WinnerID == *match.Player1ID:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// rateMatch updates the ratings of both players of a completed match and their place on the rating ranking
+func rateMatch(match *model.Match) error {
+	if match.Status != model.MatchCompleted || match.Player1ID == nil || match.Player2ID == nil {
+		return nil
+	}
+
+	player1, player2, err := crud.ApplyMatchRating(match, func(player1, player2 *model.User) error {
+		player1.SkillRating, player2.SkillRating = ratingSystem.Update(player1.SkillRating, player2.SkillRating, matchScore(match))
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, player := range []*model.User{player1, player2} {
+		if err := crud.UpdateRatingRanking(player.ID, player.Rating); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetRankings returns the users ranked by their skill rating from Redis
+func GetRankings(start, stop int64) ([]model.Ranking, error) {
+	return crud.GetRatingRanking(start, stop)
+}
+
+// GetRatingHistory returns a user's rating after each of their rated matches, oldest first
+func GetRatingHistory(userID uint) ([]model.RatingHistory, error) {
+	if _, err := crud.GetUserByID(userID); err != nil {
+		return nil, err
+	}
+	return crud.GetRatingHistory(userID)
+}
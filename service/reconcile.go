@@ -0,0 +1,147 @@
+package service
+
+import (
+	"sort"
+	"tournament-app/internal/crud"
+	"tournament-app/model"
+)
+
+// ReconcileLeaderboards compares the live leaderboards in Redis with what Postgres says they hold: the global
+// leaderboard, the rating ranking and the leaderboards of the tournaments open for registration or ongoing.
+// Unless dryRun is set, every board that differs is rebuilt from Postgres. Updates made while a board is
+// rebuilt can be lost, so the rebuild is meant for startup or quiet times.
+func ReconcileLeaderboards(dryRun bool) (*model.LeaderboardReconciliation, error) {
+	report := &model.LeaderboardReconciliation{DryRun: dryRun, Mismatches: []model.LeaderboardMismatch{}}
+
+	users, err := crud.GetUsers()
+	if err != nil {
+		return nil, err
+	}
+	scores := make(map[uint]float64, len(users))
+	ratings := make(map[uint]float64, len(users))
+	for i := range users {
+		scores[users[i].ID] = model.CalculateScore(&users[i])
+		ratings[users[i].ID] = users[i].Rating
+	}
+	if err := reconcileBoard(report, model.GlobalBoard, 0, scores, nil); err != nil {
+		return nil, err
+	}
+	if err := reconcileBoard(report, model.RatingBoard, 0, ratings, nil); err != nil {
+		return nil, err
+	}
+
+	tournaments, err := crud.GetLiveTournaments()
+	if err != nil {
+		return nil, err
+	}
+	for i := range tournaments {
+		tournament := &tournaments[i]
+		points, standings, err := tournamentPoints(tournament)
+		if err != nil {
+			return nil, err
+		}
+		if err := reconcileBoard(report, model.TournamentBoard, tournament.ID, points, standings); err != nil {
+			return nil, err
+		}
+	}
+	return report, nil
+}
+
+// reconcileBoard adds the differences between a board in Redis and the expected scores to the report and,
+// outside a dry run, rewrites a board that differs. A standings table is saved before its board is rewritten.
+func reconcileBoard(report *model.LeaderboardReconciliation, board model.LeaderboardBoard, tournamentID uint, expected map[uint]float64, standings []model.Standing) error {
+	actual, err := crud.GetBoardScores(board, tournamentID)
+	if err != nil {
+		return err
+	}
+	report.Checked++
+
+	mismatches := boardMismatches(board, tournamentID, expected, actual)
+	if len(mismatches) == 0 {
+		return nil
+	}
+	report.Mismatches = append(report.Mismatches, mismatches...)
+	if report.DryRun {
+		return nil
+	}
+
+	if standings != nil {
+		if err := crud.SaveTournamentStandings(tournamentID, standings); err != nil {
+			return err
+		}
+	}
+	if err := crud.ReplaceBoard(board, tournamentID, expected); err != nil {
+		return err
+	}
+	report.Rebuilt++
+	return nil
+}
+
+// boardMismatches lists the members whose score differs between the expected and the actual board, by member ID
+func boardMismatches(board model.LeaderboardBoard, tournamentID uint, expected, actual map[uint]float64) []model.LeaderboardMismatch {
+	var mismatches []model.LeaderboardMismatch
+	for member, score := range expected {
+		score := score
+		got, ok := actual[member]
+		if ok && got == score {
+			continue
+		}
+		mismatch := model.LeaderboardMismatch{Board: board, TournamentID: tournamentID, UserID: member, Expected: &score}
+		if ok {
+			mismatch.Actual = &got
+		}
+		mismatches = append(mismatches, mismatch)
+	}
+	for member, score := range actual {
+		score := score
+		if _, ok := expected[member]; !ok {
+			mismatches = append(mismatches, model.LeaderboardMismatch{Board: board, TournamentID: tournamentID, UserID: member, Actual: &score})
+		}
+	}
+	sort.Slice(mismatches, func(i, j int) bool {
+		return mismatches[i].UserID < mismatches[j].UserID
+	})
+	return mismatches
+}
+
+// tournamentPoints computes a running tournament's leaderboard from its entrants and matches the way match
+// results build it: every entrant starts at 0, standings formats take the points of their standings table and
+// any other played match gives win or draw points. Byes give no points. The standings are returned for the
+// formats keeping them.
+func tournamentPoints(tournament *model.Tournament) (map[uint]float64, []model.Standing, error) {
+	points := map[uint]float64{}
+	for _, entrant := range entrants(tournament) {
+		points[entrant.ID] = 0
+	}
+
+	var standings []model.Standing
+	if hasStandings(tournament.Format) {
+		var err error
+		if standings, err = tournamentStandings(tournament); err != nil {
+			return nil, nil, err
+		}
+		for _, standing := range standings {
+			points[standing.UserID] = float64(standing.Points)
+		}
+	}
+
+	matches, err := crud.GetMatchesByTournamentID(tournament.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, match := range matches {
+		if match.Status != model.MatchCompleted || match.ByeSlot != 0 {
+			continue
+		}
+		if hasStandings(tournament.Format) && match.Bracket == "" {
+			continue
+		}
+		if match.WinnerID != nil {
+			points[*match.WinnerID] += winPoints
+			continue
+		}
+		points[*match.Player1ID] += drawPoints
+		points[*match.Player2ID] += drawPoints
+	}
+	return points, standings, nil
+}
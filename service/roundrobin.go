@@ -0,0 +1,244 @@
+package service
+
+import (
+	"sort"
+	"tournament-app/internal/crud"
+	"tournament-app/model"
+)
+
+// GenerateRoundRobin schedules every player against every other player once with the circle method.
+// The first player stays in place while the others rotate one step each round. With an odd number
+// of players one player sits out every round, shown as a skipped match with only that player.
+func GenerateRoundRobin(tournamentID uint, players []model.User) []model.Match {
+	if len(players) < 2 {
+		return nil
+	}
+
+	circle := make([]*uint, 0, len(players)+1)
+	for i := range players {
+		circle = append(circle, &players[i].ID)
+	}
+	if len(circle)%2 == 1 {
+		circle = append(circle, nil)
+	}
+
+	n := len(circle)
+	var matches []model.Match
+	for round := 1; round < n; round++ {
+		for i := 0; i < n/2; i++ {
+			player1, player2 := circle[i], circle[n-1-i]
+			// the fixed player switches slots every round so nobody is always player 1
+			if i == 0 && round%2 == 0 {
+				player1, player2 = player2, player1
+			}
+
+			match := model.Match{
+				TournamentID: tournamentID,
+				Round:        round,
+				Position:     i + 1,
+				Player1ID:    player1,
+				Player2ID:    player2,
+				Status:       model.MatchPending,
+			}
+			switch {
+			case player1 == nil:
+				match.Player1ID, match.Player2ID = player2, nil
+				fallthrough
+			case player2 == nil:
+				match.ByeSlot = 2
+				match.Status = model.MatchSkipped
+			}
+			matches = append(matches, match)
+		}
+
+		// rotate everyone but the first player one step clockwise
+		last := circle[n-1]
+		copy(circle[2:], circle[1:n-1])
+		circle[1] = last
+	}
+	return matches
+}
+
+// ComputeStandings builds the standings table of the players from the completed matches.
+// Players are ranked by points, then by the points they took from each other, then by score
+// difference and scores made, the lower user ID last.
+func ComputeStandings(players []model.User, matches []model.Match) []model.Standing {
+	standings := tallyStandings(players, matches)
+	sort.SliceStable(standings, func(i, j int) bool {
+		if standings[i].Points != standings[j].Points {
+			return standings[i].Points > standings[j].Points
+		}
+		return standings[i].UserID < standings[j].UserID
+	})
+
+	// Break the ties inside each group of players level on points
+	for start := 0; start < len(standings); {
+		end := start + 1
+		for end < len(standings) && standings[end].Points == standings[start].Points {
+			end++
+		}
+		if end-start > 1 {
+			breakTies(standings[start:end], matches)
+		}
+		start = end
+	}
+
+	for i := range standings {
+		standings[i].Rank = i + 1
+	}
+	return standings
+}
+
+// tallyStandings adds up the completed matches of every player in roster order, a bye counts as a win
+func tallyStandings(players []model.User, matches []model.Match) []model.Standing {
+	rows := make(map[uint]*model.Standing, len(players))
+	standings := make([]model.Standing, len(players))
+	for i, player := range players {
+		standings[i] = model.Standing{UserID: player.ID}
+		rows[player.ID] = &standings[i]
+	}
+
+	for _, match := range matches {
+		if match.Status != model.MatchCompleted || match.Player1ID == nil {
+			continue
+		}
+		home := rows[*match.Player1ID]
+		if match.Player2ID == nil {
+			if home != nil && match.WinnerID != nil {
+				home.Played++
+				home.Wins++
+				home.Points += winPoints
+			}
+			continue
+		}
+		away := rows[*match.Player2ID]
+		if home == nil || away == nil {
+			continue
+		}
+		recordResult(home, match.Player1Score, match.Player2Score)
+		recordResult(away, match.Player2Score, match.Player1Score)
+	}
+	return standings
+}
+
+// breakTies orders players level on points by head-to-head points, score difference and scores made
+func breakTies(group []model.Standing, matches []model.Match) {
+	tied := make(map[uint]bool, len(group))
+	for _, standing := range group {
+		tied[standing.UserID] = true
+	}
+
+	headToHead := map[uint]int{}
+	for _, match := range completedMatches(matches) {
+		if !tied[*match.Player1ID] || !tied[*match.Player2ID] {
+			continue
+		}
+		switch {
+		case match.WinnerID != nil:
+			headToHead[*match.WinnerID] += winPoints
+		default:
+			headToHead[*match.Player1ID] += drawPoints
+			headToHead[*match.Player2ID] += drawPoints
+		}
+	}
+
+	sort.SliceStable(group, func(i, j int) bool {
+		a, b := group[i], group[j]
+		switch {
+		case headToHead[a.UserID] != headToHead[b.UserID]:
+			return headToHead[a.UserID] > headToHead[b.UserID]
+		case a.ScoreDifference() != b.ScoreDifference():
+			return a.ScoreDifference() > b.ScoreDifference()
+		case a.ScoreFor != b.ScoreFor:
+			return a.ScoreFor > b.ScoreFor
+		default:
+			return a.UserID < b.UserID
+		}
+	})
+}
+
+// recordResult adds one match to a player's row from the player's point of view
+func recordResult(standing *model.Standing, scoreFor, scoreAgainst int) {
+	standing.Played++
+	standing.ScoreFor += scoreFor
+	standing.ScoreAgainst += scoreAgainst
+	switch {
+	case scoreFor > scoreAgainst:
+		standing.Wins++
+		standing.Points += winPoints
+	case scoreFor == scoreAgainst:
+		standing.Draws++
+		standing.Points += drawPoints
+	default:
+		standing.Losses++
+	}
+}
+
+// completedMatches returns the matches played between two players
+func completedMatches(matches []model.Match) []model.Match {
+	var completed []model.Match
+	for _, match := range matches {
+		if match.Status == model.MatchCompleted && match.Player1ID != nil && match.Player2ID != nil {
+			completed = append(completed, match)
+		}
+	}
+	return completed
+}
+
+// GetStandings returns the standings table of a tournament. An ongoing tournament's standings
+// are read from its leaderboard in Redis, a finished one's are computed from its matches.
+func GetStandings(tournamentID uint) ([]model.Standing, error) {
+	tournament, err := crud.GetTournamentByID(tournamentID)
+	if err != nil {
+		return nil, err
+	}
+	if tournament.Status != model.Ongoing {
+		return tournamentStandings(tournament)
+	}
+
+	standings, err := crud.GetTournamentStandings(tournamentID)
+	if err != nil {
+		return nil, err
+	}
+	if len(standings) > 0 {
+		return standings, nil
+	}
+	return refreshStandings(tournament)
+}
+
+// refreshStandings recomputes a tournament's standings and stores them on its leaderboard in Redis
+func refreshStandings(tournament *model.Tournament) ([]model.Standing, error) {
+	standings, err := tournamentStandings(tournament)
+	if err != nil {
+		return nil, err
+	}
+	if err := crud.SaveTournamentStandings(tournament.ID, standings); err != nil {
+		return nil, err
+	}
+	return standings, nil
+}
+
+// tournamentStandings computes a tournament's standings from its roster and matches
+func tournamentStandings(tournament *model.Tournament) ([]model.Standing, error) {
+	matches, err := crud.GetMatchesByTournamentID(tournament.ID)
+	if err != nil {
+		return nil, err
+	}
+	switch tournament.Format {
+	case model.Swiss:
+		return ComputeSwissStandings(entrants(tournament), matches), nil
+	case model.GroupStage:
+		var standings []model.Standing
+		for _, group := range ComputeGroupStandings(entrants(tournament), matches) {
+			standings = append(standings, group.Standings...)
+		}
+		return standings, nil
+	default:
+		return ComputeStandings(entrants(tournament), matches), nil
+	}
+}
+
+// hasStandings reports whether the format keeps a standings table on the tournament's leaderboard
+func hasStandings(format model.TournamentFormat) bool {
+	return format == model.RoundRobin || format == model.Swiss || format == model.GroupStage
+}
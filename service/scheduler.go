@@ -0,0 +1,200 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+	"tournament-app/internal/crud"
+	"tournament-app/model"
+)
+
+// DefaultSchedulerInterval is the time between two passes of the tournament scheduler
+const DefaultSchedulerInterval = 10 * time.Second
+
+// ScheduledTransition is a status change the scheduler made to a tournament
+type ScheduledTransition struct {
+	TournamentID uint
+	From         model.TournamentStatus
+	To           model.TournamentStatus
+}
+
+// TournamentScheduler creates the tournaments of the recurring templates and moves scheduled tournaments along
+// their lifecycle in the background. It keeps no state of its own: every pass reads the templates and the
+// tournaments whose scheduled time has come from Postgres, so steps missed while the server was down are caught
+// up on the next pass.
+type TournamentScheduler struct {
+	Clock    Clock
+	Interval time.Duration
+
+	mu   sync.Mutex
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewTournamentScheduler returns a scheduler reading the time from the clock
+func NewTournamentScheduler(clock Clock, interval time.Duration) *TournamentScheduler {
+	return &TournamentScheduler{Clock: clock, Interval: interval}
+}
+
+// RunOnce rolls over the seasons that ended and creates the tournaments of the recurring templates, then opens the registrations, starts the tournaments
+// and finalizes the tournaments that are due, in that order, and returns the transitions it made. A created tournament
+// moves from no status to planned. A tournament that reaches starts_at still planned or without min_players
+// entrants, or whose ends_at has already passed by then, is cancelled. A tournament that fails is skipped and the others go on.
+func (s *TournamentScheduler) RunOnce() ([]ScheduledTransition, error) {
+	now := s.Clock.Now()
+	var transitions []ScheduledTransition
+	var errs []error
+
+	if _, err := RolloverSeasons(now); err != nil {
+		errs = append(errs, err)
+	}
+
+	created, err := CreateTemplateTournaments(now)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	for _, tournament := range created {
+		transitions = append(transitions, ScheduledTransition{TournamentID: tournament.ID, To: model.Planned})
+	}
+
+	steps := []struct {
+		due func(at time.Time) ([]model.Tournament, error)
+		run func(tournament *model.Tournament, now time.Time) (model.TournamentStatus, error)
+	}{
+		{crud.GetTournamentsToOpen, openScheduled},
+		{crud.GetTournamentsToStart, startScheduled},
+		{crud.GetTournamentsToEnd, endScheduled},
+	}
+	for _, step := range steps {
+		tournaments, err := step.due(now)
+		if err != nil {
+			return transitions, err
+		}
+		for i := range tournaments {
+			tournament := &tournaments[i]
+			to, err := step.run(tournament, now)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("tournament %d: %w", tournament.ID, err))
+				continue
+			}
+			transitions = append(transitions, ScheduledTransition{TournamentID: tournament.ID, From: tournament.Status, To: to})
+		}
+	}
+	return transitions, errors.Join(errs...)
+}
+
+// openScheduled opens the registration of a planned tournament
+func openScheduled(tournament *model.Tournament, now time.Time) (model.TournamentStatus, error) {
+	return model.RegistrationOpen, OpenRegistration(tournament.ID)
+}
+
+// startScheduled starts or cancels a tournament whose start time came as ScheduledStart decides. A tournament
+// that turns out to lack players for its swiss rounds when it starts is cancelled too.
+func startScheduled(tournament *model.Tournament, now time.Time) (model.TournamentStatus, error) {
+	current, err := crud.GetTournamentByID(tournament.ID)
+	if err != nil {
+		return "", err
+	}
+	if ScheduledStart(current, now) == model.Cancelled {
+		return model.Cancelled, CancelTournament(current.ID)
+	}
+	err = StartTournament(current.ID)
+	if errors.Is(err, ErrNotEnoughPlayers) {
+		return model.Cancelled, CancelTournament(current.ID)
+	}
+	return model.Ongoing, err
+}
+
+// endScheduled finalizes or cancels an ongoing tournament whose end time came as ScheduledEnd decides
+func endScheduled(tournament *model.Tournament, now time.Time) (model.TournamentStatus, error) {
+	current, err := crud.GetTournamentByID(tournament.ID)
+	if err != nil {
+		return "", err
+	}
+	if ScheduledEnd(current) == model.Cancelled {
+		return model.Cancelled, CancelTournament(current.ID)
+	}
+	return model.Finished, FinalizeTournament(current.ID)
+}
+
+// ScheduledStart decides what becomes of a tournament whose starts_at came at the given time. It is cancelled
+// when it is still planned, as nobody could join it, when its ends_at has passed by then, when it has fewer than
+// min_players entrants or too few entrants for its swiss rounds, and started otherwise.
+func ScheduledStart(tournament *model.Tournament, now time.Time) model.TournamentStatus {
+	players := len(entrants(tournament))
+	switch {
+	case tournament.Status == model.Planned:
+		return model.Cancelled
+	case tournament.EndsAt != nil && !now.Before(*tournament.EndsAt):
+		return model.Cancelled
+	case players < tournament.MinPlayers:
+		return model.Cancelled
+	case tournament.Format == model.Swiss && tournament.Rounds > players-1:
+		return model.Cancelled
+	default:
+		return model.Ongoing
+	}
+}
+
+// ScheduledEnd decides what becomes of an ongoing tournament whose ends_at came: it is finalized,
+// or cancelled when it was left with fewer than min_players entrants
+func ScheduledEnd(tournament *model.Tournament) model.TournamentStatus {
+	if len(entrants(tournament)) < tournament.MinPlayers {
+		return model.Cancelled
+	}
+	return model.Finished
+}
+
+// Start runs a pass right away to catch up and then one every interval until Stop is called
+func (s *TournamentScheduler) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stop != nil {
+		return
+	}
+	s.stop = make(chan struct{})
+	s.done = make(chan struct{})
+
+	go func(stop, done chan struct{}) {
+		defer close(done)
+		ticker := time.NewTicker(s.Interval)
+		defer ticker.Stop()
+		for {
+			if _, err := s.RunOnce(); err != nil {
+				log.Printf("Tournament scheduling failed: %v", err)
+			}
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}(s.stop, s.done)
+}
+
+// Stop ends the background scheduling and waits for a running pass to finish
+func (s *TournamentScheduler) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stop == nil {
+		return
+	}
+	close(s.stop)
+	<-s.done
+	s.stop, s.done = nil, nil
+}
+
+// scheduler is the tournament scheduler of the API
+var scheduler = NewTournamentScheduler(systemClock{}, DefaultSchedulerInterval)
+
+// StartTournamentScheduler starts moving the scheduled tournaments along in the background
+func StartTournamentScheduler() {
+	scheduler.Start()
+}
+
+// StopTournamentScheduler stops the background scheduling
+func StopTournamentScheduler() {
+	scheduler.Stop()
+}
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+	"tournament-app/internal/crud"
+	"tournament-app/model"
+	"tournament-app/validation"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrSeasonOverlap is returned when a season is created for a time another season already covers
+	ErrSeasonOverlap = errors.New("season overlaps another season")
+	// ErrSeasonArchived is returned when a season is archived twice
+	ErrSeasonArchived = errors.New("season is already archived")
+)
+
+// CreateSeason creates a season that does not overlap any active season
+func CreateSeason(season *model.Season) error {
+	season.Status = model.SeasonActive
+	if err := validation.ValidateSeason(season); err != nil {
+		return err
+	}
+	return crud.CreateSeason(season, func(overlapping []model.Season) error {
+		if len(overlapping) > 0 {
+			return fmt.Errorf("%w: %s", ErrSeasonOverlap, overlapping[0].Name)
+		}
+		return nil
+	})
+}
+
+func GetSeasonByID(id uint) (*model.Season, error) {
+	return crud.GetSeasonByID(id)
+}
+
+func GetSeasons() ([]model.Season, error) {
+	return crud.GetSeasons()
+}
+
+// CurrentSeason returns the season running at the given time, or nil when no season is running. It only
+// reads, the seasons that ended are rolled over by the tournament scheduler.
+func CurrentSeason(now time.Time) (*model.Season, error) {
+	season, err := crud.GetSeasonAt(now)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return season, err
+}
+
+// RolloverSeasons archives every season that is over at the given time and returns them. The final standings
+// move from Redis to Postgres, the season's rating soft reset is applied and, unless a later season was
+// already planned, the next monthly season is started.
+func RolloverSeasons(now time.Time) ([]model.Season, error) {
+	ended, err := crud.GetEndedSeasons(now)
+	if err != nil {
+		return nil, err
+	}
+
+	var archived []model.Season
+	for _, season := range ended {
+		stored, users, err := crud.ArchiveSeason(season.ID, func(season *model.Season, upcoming []model.Season) ([]model.SeasonLeaderboard, *model.Season, error) {
+			if season.Status == model.SeasonArchived {
+				return nil, nil, ErrSeasonArchived
+			}
+			standings, err := crud.GetSeasonLeaderboard(season.ID, 0, -1)
+			if err != nil {
+				return nil, nil, err
+			}
+			if len(upcoming) > 0 {
+				return standings, nil, nil
+			}
+			next := NextSeason(*season, now)
+			return standings, &next, nil
+		})
+		// Another rollover archived the season first
+		if errors.Is(err, ErrSeasonArchived) {
+			continue
+		}
+		if err != nil {
+			return archived, fmt.Errorf("failed to archive season %d: %w", season.ID, err)
+		}
+
+		ratings := make(map[uint]float64, len(users))
+		for _, user := range users {
+			ratings[user.ID] = user.Rating
+		}
+		if err := crud.SetRatingRankings(ratings); err != nil {
+			return archived, err
+		}
+		if err := crud.RemoveSeasonLeaderboard(stored.ID); err != nil {
+			return archived, err
+		}
+		archived = append(archived, *stored)
+	}
+	return archived, nil
+}
+
+// NextSeason returns the monthly season following the given one. Months that passed without a season
+// running are skipped, so the returned season is running or upcoming at the given time.
+func NextSeason(last model.Season, now time.Time) model.Season {
+	start := last.EndsAt
+	end := start.AddDate(0, 1, 0)
+	for !end.After(now) {
+		start, end = end, end.AddDate(0, 1, 0)
+	}
+	return model.Season{
+		Name:            start.Format("January 2006"),
+		StartsAt:        start,
+		EndsAt:          end,
+		Status:          model.SeasonActive,
+		RatingSoftReset: last.RatingSoftReset,
+	}
+}
+
+// GetSeasonLeaderboard returns a season's leaderboard, live from Redis while the season runs
+// and the archived final standings from Postgres once it is over
+func GetSeasonLeaderboard(seasonID uint, start, stop int64) ([]model.SeasonLeaderboard, error) {
+	season, err := crud.GetSeasonByID(seasonID)
+	if err != nil {
+		return nil, err
+	}
+	if season.Status == model.SeasonArchived {
+		return crud.GetArchivedSeasonLeaderboard(season.ID, start, stop)
+	}
+	return crud.GetSeasonLeaderboard(season.ID, start, stop)
+}
+
+// seasonID returns the ID of the season running now, 0 when no season is running
+func seasonID() (uint, error) {
+	season, err := CurrentSeason(time.Now())
+	if err != nil || season == nil {
+		return 0, err
+	}
+	return season.ID, nil
+}
@@ -0,0 +1,298 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"tournament-app/internal/crud"
+	"tournament-app/model"
+)
+
+var (
+	// ErrRoundNotFinished is returned when the next round is requested before every match of the current one has a result
+	ErrRoundNotFinished = errors.New("current round is not finished")
+	// ErrNoRoundsLeft is returned when the next round is requested after the last round of the tournament
+	ErrNoRoundsLeft = errors.New("all rounds of the tournament were played")
+	// ErrNoPairing is returned when the players cannot be paired without a rematch
+	ErrNoPairing = errors.New("no pairing without a rematch")
+)
+
+// maxSwissPairingSteps caps the backtracking of a round's pairing. Score groups are tried first, so a pairing
+// is found in a few steps when there is one, and a field that cannot be paired fails here instead of trying
+// every ordering of the players.
+const maxSwissPairingSteps = 100000
+
+// ComputeSwissStandings builds the standings table of a swiss tournament from the completed matches.
+// Players are ranked by points, then by Buchholz, then by Sonneborn-Berger, the lower user ID last.
+func ComputeSwissStandings(players []model.User, matches []model.Match) []model.Standing {
+	standings := tallySwissStandings(players, matches)
+	sort.SliceStable(standings, func(i, j int) bool {
+		if swissLess(standings[j], standings[i]) {
+			return true
+		}
+		if swissLess(standings[i], standings[j]) {
+			return false
+		}
+		return standings[i].UserID < standings[j].UserID
+	})
+	for i := range standings {
+		standings[i].Rank = i + 1
+	}
+	return standings
+}
+
+// tallySwissStandings adds the Buchholz and Sonneborn-Berger tiebreakers to the players' rows, in roster order
+func tallySwissStandings(players []model.User, matches []model.Match) []model.Standing {
+	standings := tallyStandings(players, matches)
+	points := make(map[uint]int, len(standings))
+	for _, standing := range standings {
+		points[standing.UserID] = standing.Points
+	}
+
+	rows := make(map[uint]*model.Standing, len(standings))
+	for i := range standings {
+		rows[standings[i].UserID] = &standings[i]
+	}
+	for _, match := range completedMatches(matches) {
+		home, away := rows[*match.Player1ID], rows[*match.Player2ID]
+		if home == nil || away == nil {
+			continue
+		}
+		home.Buchholz += points[away.UserID]
+		away.Buchholz += points[home.UserID]
+		switch {
+		case match.WinnerID == nil:
+			home.SonnebornBerger += float64(points[away.UserID]) / 2
+			away.SonnebornBerger += float64(points[home.UserID]) / 2
+		case *match.WinnerID == home.UserID:
+			home.SonnebornBerger += float64(points[away.UserID])
+		default:
+			away.SonnebornBerger += float64(points[home.UserID])
+		}
+	}
+	return standings
+}
+
+// swissLess reports whether a ranks below b on points and tiebreakers alone
+func swissLess(a, b model.Standing) bool {
+	switch {
+	case a.Points != b.Points:
+		return a.Points < b.Points
+	case a.Buchholz != b.Buchholz:
+		return a.Buchholz < b.Buchholz
+	default:
+		return a.SonnebornBerger < b.SonnebornBerger
+	}
+}
+
+// PairSwissRound pairs the players for the given round from the matches played so far, players in seed order.
+// Players meet opponents on the same points where possible, the top half of a score group against its
+// bottom half, and never meet the same opponent twice. With an odd number of players the lowest ranked
+// player without a bye so far gets one, which counts as a win.
+func PairSwissRound(tournamentID uint, round int, players []model.User, matches []model.Match) ([]model.Match, error) {
+	if len(players) < 2 {
+		return nil, nil
+	}
+
+	// Rank on points and tiebreakers, players level on everything keep their seed order
+	standings := tallySwissStandings(players, matches)
+	sort.SliceStable(standings, func(i, j int) bool {
+		return swissLess(standings[j], standings[i])
+	})
+	order := make([]uint, len(standings))
+	points := make(map[uint]int, len(standings))
+	for i, standing := range standings {
+		order[i] = standing.UserID
+		points[standing.UserID] = standing.Points
+	}
+
+	played := map[[2]uint]bool{}
+	hadBye := map[uint]bool{}
+	for _, match := range matches {
+		switch {
+		case match.Player1ID != nil && match.Player2ID != nil:
+			played[pairKey(*match.Player1ID, *match.Player2ID)] = true
+		case match.Player1ID != nil && match.Status == model.MatchCompleted:
+			hadBye[*match.Player1ID] = true
+		}
+	}
+
+	var pairs [][2]uint
+	var bye *uint
+	steps := maxSwissPairingSteps
+	if len(order)%2 == 0 {
+		found, ok := pairSwissPlayers(order, points, played, &steps)
+		if !ok {
+			return nil, fmt.Errorf("%w: round %d", ErrNoPairing, round)
+		}
+		pairs = found
+	} else {
+		// Try the bye from the bottom up, once everyone had one anybody can get another
+		candidates := make([]int, 0, len(order))
+		for i := len(order) - 1; i >= 0; i-- {
+			if !hadBye[order[i]] {
+				candidates = append(candidates, i)
+			}
+		}
+		if len(candidates) == 0 {
+			for i := len(order) - 1; i >= 0; i-- {
+				candidates = append(candidates, i)
+			}
+		}
+		for _, i := range candidates {
+			rest := append(append([]uint(nil), order[:i]...), order[i+1:]...)
+			if found, ok := pairSwissPlayers(rest, points, played, &steps); ok {
+				pairs = found
+				id := order[i]
+				bye = &id
+				break
+			}
+		}
+		if bye == nil {
+			return nil, fmt.Errorf("%w: round %d", ErrNoPairing, round)
+		}
+	}
+
+	result := make([]model.Match, 0, len(pairs)+1)
+	for i, pair := range pairs {
+		player1, player2 := pair[0], pair[1]
+		result = append(result, model.Match{
+			TournamentID: tournamentID,
+			Round:        round,
+			Position:     i + 1,
+			Player1ID:    &player1,
+			Player2ID:    &player2,
+			Status:       model.MatchPending,
+		})
+	}
+	if bye != nil {
+		result = append(result, model.Match{
+			TournamentID: tournamentID,
+			Round:        round,
+			Position:     len(pairs) + 1,
+			Player1ID:    bye,
+			WinnerID:     bye,
+			ByeSlot:      2,
+			Status:       model.MatchCompleted,
+		})
+	}
+	return result, nil
+}
+
+// pairSwissPlayers pairs the players in ranking order by backtracking: the best unpaired player
+// takes the first opponent they have not met yet, trying the others if the rest cannot be paired.
+// Every attempt takes one of the steps left, the search gives up once they run out.
+func pairSwissPlayers(order []uint, points map[uint]int, played map[[2]uint]bool, steps *int) ([][2]uint, bool) {
+	if len(order) == 0 {
+		return nil, true
+	}
+	if *steps <= 0 {
+		return nil, false
+	}
+	*steps--
+
+	player := order[0]
+	for _, i := range swissCandidates(order, points) {
+		opponent := order[i]
+		if played[pairKey(player, opponent)] {
+			continue
+		}
+		rest := make([]uint, 0, len(order)-2)
+		for j := 1; j < len(order); j++ {
+			if j != i {
+				rest = append(rest, order[j])
+			}
+		}
+		if pairs, ok := pairSwissPlayers(rest, points, played, steps); ok {
+			return append([][2]uint{{player, opponent}}, pairs...), true
+		}
+	}
+	return nil, false
+}
+
+// swissCandidates returns the indexes of the opponents for order[0] in the order they are tried:
+// the top of the bottom half of their score group first, then the rest of the group, then lower groups
+func swissCandidates(order []uint, points map[uint]int) []int {
+	group := 1
+	for group < len(order) && points[order[group]] == points[order[0]] {
+		group++
+	}
+
+	candidates := make([]int, 0, len(order)-1)
+	half := group / 2
+	if half < 1 {
+		half = 1
+	}
+	for i := half; i < group; i++ {
+		candidates = append(candidates, i)
+	}
+	for i := half - 1; i >= 1; i-- {
+		candidates = append(candidates, i)
+	}
+	for i := group; i < len(order); i++ {
+		candidates = append(candidates, i)
+	}
+	return candidates
+}
+
+// pairKey identifies a pair of players regardless of their order
+func pairKey(a, b uint) [2]uint {
+	if a > b {
+		a, b = b, a
+	}
+	return [2]uint{a, b}
+}
+
+// swissRounds returns the number of rounds of a swiss tournament, by default enough
+// rounds for a single player to win all of their matches
+func swissRounds(tournament *model.Tournament) int {
+	if tournament.Rounds > 0 {
+		return tournament.Rounds
+	}
+	players := len(entrants(tournament))
+	if players < 2 {
+		return 1
+	}
+	return int(math.Ceil(math.Log2(float64(players))))
+}
+
+// NextRound pairs the next round of a swiss tournament once every match of the current round has a result
+func NextRound(tournamentID uint) ([]model.Match, error) {
+	var tournament *model.Tournament
+	matches, err := crud.CreateRound(tournamentID, func(locked *model.Tournament, matches []model.Match) ([]model.Match, error) {
+		if locked.Status != model.Ongoing {
+			return nil, fmt.Errorf("%w: rounds can only be paired in an ongoing tournament", ErrInvalidTransition)
+		}
+		if locked.Format != model.Swiss {
+			return nil, fmt.Errorf("%w: only swiss tournaments are paired round by round", ErrInvalidTransition)
+		}
+
+		current := 0
+		for _, match := range matches {
+			if match.Round > current {
+				current = match.Round
+			}
+		}
+		for _, match := range matches {
+			if match.Round == current && match.Status == model.MatchPending {
+				return nil, fmt.Errorf("%w: round %d", ErrRoundNotFinished, current)
+			}
+		}
+		if current >= swissRounds(locked) {
+			return nil, fmt.Errorf("%w: %d of %d", ErrNoRoundsLeft, current, swissRounds(locked))
+		}
+
+		tournament = locked
+		return PairSwissRound(locked.ID, current+1, SeedPlayers(entrants(locked), locked.Seeding), matches)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// A bye is a win, so the standings change as soon as the round is paired
+	if _, err := refreshStandings(tournament); err != nil {
+		return nil, err
+	}
+	return matches, nil
+}
@@ -0,0 +1,256 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+	"tournament-app/internal/crud"
+	"tournament-app/model"
+	"tournament-app/validation"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrTeamFull is returned when a user joins a team whose roster is full
+	ErrTeamFull = errors.New("team roster is full")
+	// ErrAlreadyMember is returned when the user is already on the team
+	ErrAlreadyMember = errors.New("user is already a member of the team")
+	// ErrNotMember is returned when the user is not on the team
+	ErrNotMember = errors.New("user is not a member of the team")
+	// ErrCaptainLeaving is returned when the captain is removed from their own team
+	ErrCaptainLeaving = errors.New("the captain cannot leave the team")
+	// ErrTeamEntered is returned when the roster of a team in an open or ongoing tournament changes
+	ErrTeamEntered = errors.New("team is entered in an open or ongoing tournament")
+	// ErrRosterIncomplete is returned when a team without a full roster enters a tournament
+	ErrRosterIncomplete = errors.New("team roster is not complete")
+	// ErrTeamTournament is returned when a single user joins or leaves a tournament entered by teams
+	ErrTeamTournament = errors.New("tournament is entered by teams")
+	// ErrSoloTournament is returned when a team enters a tournament played by single users
+	ErrSoloTournament = errors.New("tournament is entered by single players")
+)
+
+// CreateTeam creates a team led by its captain, who becomes its first member
+func CreateTeam(team *model.Team) error {
+	if team.RosterSize == 0 {
+		team.RosterSize = model.DefaultRosterSize
+	}
+	team.Members = nil
+	if err := validation.ValidateTeam(team); err != nil {
+		return err
+	}
+	return crud.CreateTeam(team)
+}
+
+func GetTeamByID(id uint) (*model.Team, error) {
+	return crud.GetTeamByID(id)
+}
+
+func GetTeams() ([]model.Team, error) {
+	return crud.GetTeams()
+}
+
+// AddTeamMember puts a user on a team with room on its roster. The roster of a team entered
+// in an open or ongoing tournament is fixed until the tournament ends.
+func AddTeamMember(teamID, userID uint) (*model.Team, error) {
+	team, err := crud.AddTeamMember(teamID, userID, func(team *model.Team, user *model.User, entered []model.Tournament) error {
+		if len(entered) > 0 {
+			return fmt.Errorf("%w: tournament %d", ErrTeamEntered, entered[0].ID)
+		}
+		if isMember(team, user.ID) {
+			return ErrAlreadyMember
+		}
+		if len(team.Members) >= team.RosterSize {
+			return ErrTeamFull
+		}
+		return nil
+	})
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return nil, ErrAlreadyMember
+	}
+	return team, err
+}
+
+// RemoveTeamMember takes a user other than the captain off a team that is not in an open or ongoing tournament
+func RemoveTeamMember(teamID, userID uint) (*model.Team, error) {
+	return crud.RemoveTeamMember(teamID, userID, func(team *model.Team, entered []model.Tournament) error {
+		if len(entered) > 0 {
+			return fmt.Errorf("%w: tournament %d", ErrTeamEntered, entered[0].ID)
+		}
+		if !isMember(team, userID) {
+			return ErrNotMember
+		}
+		if team.CaptainID == userID {
+			return ErrCaptainLeaving
+		}
+		return nil
+	})
+}
+
+// DeleteTeam deletes a team that is not in an open or ongoing tournament
+func DeleteTeam(id uint) error {
+	return crud.DeleteTeam(id, func(team *model.Team, entered []model.Tournament) error {
+		if len(entered) > 0 {
+			return fmt.Errorf("%w: tournament %d", ErrTeamEntered, entered[0].ID)
+		}
+		return nil
+	})
+}
+
+// JoinTournamentAsTeam enters a team with a full roster into a team tournament.
+// The entry fee is split across the members or paid by the captain under the tournament's team fee policy,
+// registration, entry fees and auto-start with the opening matches are applied in one Postgres transaction
+// and Redis is only updated after that transaction is committed.
+func JoinTournamentAsTeam(tournamentID, teamID uint) error {
+	tournament, team, err := crud.JoinTournamentAsTeam(tournamentID, teamID, func(tournament *model.Tournament, team *model.Team) error {
+		if !acceptsEntries(tournament, time.Now()) {
+			return ErrRegistrationClosed
+		}
+		if !tournament.TeamEntry {
+			return ErrSoloTournament
+		}
+		if isEntrant(tournament, team.ID) {
+			return ErrAlreadyJoined
+		}
+		if len(tournament.Teams) >= tournament.MaxPlayers {
+			return ErrTournamentFull
+		}
+		if len(team.Members) < team.RosterSize {
+			return fmt.Errorf("%w: %d of %d members", ErrRosterIncomplete, len(team.Members), team.RosterSize)
+		}
+
+		shares := teamShares(team, tournament.Fee(), tournament.TeamFee)
+		for i := range team.Members {
+			if team.Members[i].Money < shares[team.Members[i].ID] {
+				return fmt.Errorf("user %d does not have enough money to join the tournament", team.Members[i].ID)
+			}
+		}
+		for i := range team.Members {
+			team.Members[i].Money -= shares[team.Members[i].ID]
+		}
+		return nil
+	}, startWhenFull)
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return ErrAlreadyJoined
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, member := range team.Members {
+		if err := crud.UpdateLeaderboard(fmt.Sprintf("%d", member.ID), model.CalculateScore(&member)); err != nil {
+			return err
+		}
+	}
+	// The team collects the points of its match results on the tournament's leaderboard
+	if err := UpdateTournamentLeaderboard(tournament.ID, team.ID, 0); err != nil {
+		return err
+	}
+
+	// The entry that filled the tournament started it
+	if tournament.Status == model.Ongoing {
+		return openStandings(tournament)
+	}
+	return nil
+}
+
+// LeaveTournamentAsTeam withdraws a team from a team tournament, every member gets their share
+// of the entry fee back under the tournament's refund policy. Teams can only leave while the registration is open.
+func LeaveTournamentAsTeam(tournamentID, teamID uint) error {
+	tournament, members, err := crud.LeaveTournamentAsTeam(tournamentID, teamID, func(tournament *model.Tournament) error {
+		if tournament.Status != model.RegistrationOpen {
+			return fmt.Errorf("%w: cannot leave a %s tournament", ErrInvalidTransition, tournament.Status)
+		}
+		if !tournament.TeamEntry {
+			return ErrSoloTournament
+		}
+		if !isEntrant(tournament, teamID) {
+			return ErrNotJoined
+		}
+		return nil
+	}, func(tournament *model.Tournament, user *model.User, paid int) error {
+		user.Money += refundAmount(tournament.RefundPolicy, tournament.Status, paid)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, member := range members {
+		if err := crud.UpdateLeaderboard(fmt.Sprintf("%d", member.ID), model.CalculateScore(&member)); err != nil {
+			return err
+		}
+	}
+	return crud.RemoveFromTournamentLeaderboard(tournament.ID, fmt.Sprintf("%d", teamID))
+}
+
+// teamShares splits an amount of money across the members of a team, the captain takes what does
+// not divide evenly. Under the captain policy the captain takes all of it.
+func teamShares(team *model.Team, amount int, policy model.TeamFeePolicy) map[uint]int {
+	shares := make(map[uint]int, len(team.Members))
+	if policy == model.CaptainTeamFee || len(team.Members) == 0 {
+		shares[team.CaptainID] = amount
+		return shares
+	}
+	for _, member := range team.Members {
+		shares[member.ID] = amount / len(team.Members)
+	}
+	shares[team.CaptainID] += amount % len(team.Members)
+	return shares
+}
+
+// entrants returns who plays the matches of a tournament: its users, or in a team tournament its teams.
+// A team plays under its own ID with the average level and score of its members, which it is seeded by.
+func entrants(tournament *model.Tournament) []model.User {
+	if !tournament.TeamEntry {
+		return tournament.Users
+	}
+	players := make([]model.User, 0, len(tournament.Teams))
+	for _, team := range tournament.Teams {
+		player := model.User{ID: team.ID, Name: team.Name}
+		if len(team.Members) > 0 {
+			var score float64
+			for _, member := range team.Members {
+				player.Level += member.Level
+				score += member.Score
+			}
+			player.Level /= len(team.Members)
+			player.Score = score / float64(len(team.Members))
+		}
+		players = append(players, player)
+	}
+	return players
+}
+
+// isEntrant reports whether the user, or in a team tournament the team, is entered into the tournament
+func isEntrant(tournament *model.Tournament, id uint) bool {
+	if !tournament.TeamEntry {
+		return hasJoined(tournament, id)
+	}
+	for _, team := range tournament.Teams {
+		if team.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// isMember reports whether the user is on the team's roster
+func isMember(team *model.Team, userID uint) bool {
+	for _, member := range team.Members {
+		if member.ID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// tournamentTeam returns the team entered into the tournament with the given ID
+func tournamentTeam(tournament *model.Tournament, teamID uint) *model.Team {
+	for i := range tournament.Teams {
+		if tournament.Teams[i].ID == teamID {
+			return &tournament.Teams[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+	"tournament-app/internal/crud"
+	"tournament-app/model"
+	"tournament-app/validation"
+)
+
+// ErrInvalidTemplate is returned when a tournament template or the tournaments it would create are not valid
+var ErrInvalidTemplate = errors.New("invalid tournament template")
+
+// maxTemplateTournaments caps the tournaments a template creates in one pass, e.g. a recurrence firing
+// every minute with a long create-ahead time catches up over several passes
+const maxTemplateTournaments = 100
+
+// CreateTournamentTemplate validates and creates a recurring tournament template
+func CreateTournamentTemplate(template *model.TournamentTemplate) error {
+	template.LastStartsAt = nil
+	if err := validateTemplate(template); err != nil {
+		return err
+	}
+	return crud.CreateTournamentTemplate(template)
+}
+
+// UpdateTournamentTemplate changes the settings of a template that the update sends, the others are kept.
+// The tournaments already created from it are not changed, the next ones follow the new settings.
+func UpdateTournamentTemplate(id uint, update *model.TournamentTemplateUpdate) (*model.TournamentTemplate, error) {
+	return crud.UpdateTournamentTemplate(id, func(template *model.TournamentTemplate) error {
+		update.Apply(template)
+		return validateTemplate(template)
+	})
+}
+
+// validateTemplate fills in the default timing of a template and checks it together with a tournament made from it
+func validateTemplate(template *model.TournamentTemplate) error {
+	if template.RegistrationMinutes == 0 {
+		template.RegistrationMinutes = model.DefaultRegistrationMinutes
+	}
+	if template.DurationMinutes == 0 {
+		template.DurationMinutes = model.DefaultDurationMinutes
+	}
+	if template.CreateAheadMinutes == 0 {
+		template.CreateAheadMinutes = model.DefaultCreateAheadMinutes
+	}
+	if err := validation.ValidateTournamentTemplate(template); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidTemplate, err)
+	}
+
+	sample := template.Tournament(time.Now())
+	tournamentDefaults(&sample)
+	if err := validation.ValidateTournament(&sample); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidTemplate, err)
+	}
+	return nil
+}
+
+// GetTournamentTemplateByID retrieves a template by its ID
+func GetTournamentTemplateByID(id uint) (*model.TournamentTemplate, error) {
+	return crud.GetTournamentTemplateByID(id)
+}
+
+// GetTournamentTemplates retrieves all templates
+func GetTournamentTemplates() ([]model.TournamentTemplate, error) {
+	return crud.GetTournamentTemplates()
+}
+
+// DeleteTournamentTemplate deletes a template, the tournaments created from it stay and keep the link
+func DeleteTournamentTemplate(id uint) error {
+	return crud.DeleteTournamentTemplate(id)
+}
+
+// GetTemplateTournaments retrieves the tournaments created from a template, also after it was deleted
+func GetTemplateTournaments(templateID uint) ([]model.Tournament, error) {
+	return crud.GetTournamentsByTemplateID(templateID)
+}
+
+// CreateTemplateTournaments creates the tournaments of every template that is not paused starting within its
+// create-ahead time from now. A template continues after the last tournament it created, start times that
+// passed while nothing was running are skipped. A template that fails is skipped and the others go on.
+func CreateTemplateTournaments(now time.Time) ([]model.Tournament, error) {
+	templates, err := crud.GetRunningTournamentTemplates()
+	if err != nil {
+		return nil, err
+	}
+
+	var created []model.Tournament
+	var errs []error
+	for i := range templates {
+		tournaments, err := crud.CreateTemplateTournaments(templates[i].ID, func(template *model.TournamentTemplate) ([]model.Tournament, error) {
+			return planTemplateTournaments(template, now)
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("template %d: %w", templates[i].ID, err))
+			continue
+		}
+		created = append(created, tournaments...)
+	}
+	return created, errors.Join(errs...)
+}
+
+// planTemplateTournaments returns the tournaments of the template due by now and moves its last_starts_at past them
+func planTemplateTournaments(template *model.TournamentTemplate, now time.Time) ([]model.Tournament, error) {
+	if template.Paused {
+		return nil, nil
+	}
+	recurrence, err := model.ParseRecurrence(template.Recurrence)
+	if err != nil {
+		return nil, err
+	}
+
+	from := now
+	if template.LastStartsAt != nil && template.LastStartsAt.After(from) {
+		from = *template.LastStartsAt
+	}
+	horizon := now.Add(time.Duration(template.CreateAheadMinutes) * time.Minute)
+
+	var tournaments []model.Tournament
+	for len(tournaments) < maxTemplateTournaments {
+		startsAt := recurrence.Next(from)
+		if startsAt.IsZero() || startsAt.After(horizon) {
+			break
+		}
+		tournament := template.Tournament(startsAt)
+		if err := prepareTournament(&tournament); err != nil {
+			return nil, err
+		}
+		tournaments = append(tournaments, tournament)
+		from = startsAt
+	}
+	if len(tournaments) > 0 {
+		template.LastStartsAt = &from
+	}
+	return tournaments, nil
+}
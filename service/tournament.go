@@ -1,37 +1,317 @@
 package service
 
 import (
+	"errors"
 	"fmt"
+	"time"
 	"tournament-app/internal/crud"
 	"tournament-app/model"
 	"tournament-app/validation"
+
+	"gorm.io/gorm"
 )
 
-func CreateTournament(tournament *model.Tournament) error {
-	tournament.Status = model.Planned
-	if err := crud.CreateTournament(tournament); err != nil {
-		return err
+var (
+	// ErrInvalidTransition is returned when a tournament cannot move to the requested status
+	ErrInvalidTransition = errors.New("invalid tournament status transition")
+	// ErrRegistrationClosed is returned when joining a tournament that is not accepting players
+	ErrRegistrationClosed = errors.New("tournament registration is not open")
+	// ErrAlreadyJoined is returned when the user is already registered in the tournament
+	ErrAlreadyJoined = errors.New("user already joined the tournament")
+	// ErrNotJoined is returned when the user is not registered in the tournament
+	ErrNotJoined = errors.New("user has not joined the tournament")
+	// ErrTournamentFull is returned when the tournament already has max_players users
+	ErrTournamentFull = errors.New("tournament is full")
+	// ErrNotEnoughPlayers is returned when fewer than min_players users joined the tournament
+	ErrNotEnoughPlayers = errors.New("tournament does not have enough players")
+	// ErrInvalidWindow is returned when a leaderboard is requested for an unknown time window
+	ErrInvalidWindow = errors.New("invalid leaderboard window")
+	// ErrInvalidTournament is returned when the settings of a created or updated tournament are not valid
+	ErrInvalidTournament = errors.New("invalid tournament")
+)
+
+// tournamentTransitions lists the statuses each tournament status may move to
+var tournamentTransitions = map[model.TournamentStatus][]model.TournamentStatus{
+	model.Planned:          {model.RegistrationOpen, model.Cancelled},
+	model.RegistrationOpen: {model.Ongoing, model.Cancelled},
+	model.Ongoing:          {model.Finished, model.Cancelled},
+}
+
+// CanTransition reports whether a tournament may move from one status to another
+func CanTransition(from, to model.TournamentStatus) bool {
+	for _, next := range tournamentTransitions[from] {
+		if next == to {
+			return true
+		}
 	}
+	return false
+}
 
-	// Create an empty leaderboard for the each tournament created
-	leaderboard := model.Leaderboard{
-		TournamentID: tournament.ID,
+// transitionTournament moves the tournament to the given status and saves it
+func transitionTournament(tournament *model.Tournament, to model.TournamentStatus) error {
+	if !CanTransition(tournament.Status, to) {
+		return fmt.Errorf("%w: %s -> %s", ErrInvalidTransition, tournament.Status, to)
 	}
-	if err := CreateLeaderboardEntry(&leaderboard); err != nil {
+	tournament.Status = to
+	return crud.UpdateTournament(tournament)
+}
+
+func CreateTournament(tournament *model.Tournament) error {
+	if err := prepareTournament(tournament); err != nil {
 		return err
 	}
 
+	// The tournament's leaderboard sorted set is created in Redis by the first join
+	return crud.CreateTournament(tournament)
+}
+
+// prepareTournament fills in the defaults of a new tournament, validates it and tags it with the season it is played in
+func prepareTournament(tournament *model.Tournament) error {
+	tournamentDefaults(tournament)
+	if err := validation.ValidateTournament(tournament); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidTournament, err)
+	}
+
+	// Tag the tournament with the season it is played in
+	tournament.SeasonID = nil
+	season, err := seasonID()
+	if err != nil {
+		return fmt.Errorf("failed to retrieve season: %w", err)
+	}
+	if season != 0 {
+		tournament.SeasonID = &season
+	}
 	return nil
 }
 
+// tournamentDefaults sets a new tournament planned and fills in the settings left empty
+func tournamentDefaults(tournament *model.Tournament) {
+	tournament.Status = model.Planned
+	if tournament.MaxPlayers == 0 {
+		tournament.MaxPlayers = model.DefaultMaxPlayers
+	}
+	if tournament.MinPlayers == 0 {
+		tournament.MinPlayers = model.DefaultMinPlayers
+	}
+	if tournament.EntryFee == nil {
+		fee := model.DefaultEntryFee
+		tournament.EntryFee = &fee
+	}
+	if tournament.RefundPolicy == "" {
+		tournament.RefundPolicy = model.RefundBeforeStart
+	}
+	if tournament.TeamEntry && tournament.TeamFee == "" {
+		tournament.TeamFee = model.SplitTeamFee
+	}
+	if tournament.Format == "" {
+		tournament.Format = model.SingleElimination
+	}
+	if tournament.Seeding == "" {
+		tournament.Seeding = model.SeedByLevel
+	}
+	if tournament.Format == model.GroupStage && tournament.GroupSize == 0 {
+		tournament.GroupSize = model.DefaultGroupSize
+	}
+	if tournament.Format == model.GroupStage && tournament.AdvancePerGroup == 0 {
+		tournament.AdvancePerGroup = model.DefaultAdvancePerGroup
+	}
+}
+
+// UpdateTournament updates the tournament details, status changes go through the lifecycle functions
 func UpdateTournament(tournament *model.Tournament) error {
-	if err := validation.ValidateTournament(tournament); err != nil {
+	current, err := crud.GetTournamentByID(tournament.ID)
+	if err != nil {
 		return err
 	}
+	if tournament.Status != "" && tournament.Status != current.Status {
+		return fmt.Errorf("%w: status can only be changed through the tournament lifecycle endpoints", ErrInvalidTransition)
+	}
+	tournament.Status = current.Status
+	tournament.SeasonID = current.SeasonID
+	tournament.TemplateID = current.TemplateID
+	tournament.CreatedAt = current.CreatedAt
+
+	// Keep the current limits when they are not part of the update
+	if tournament.MaxPlayers == 0 {
+		tournament.MaxPlayers = current.MaxPlayers
+	}
+	if tournament.MinPlayers == 0 {
+		tournament.MinPlayers = current.MinPlayers
+	}
+	if tournament.EntryFee == nil {
+		tournament.EntryFee = current.EntryFee
+	}
+	if tournament.RefundPolicy == "" {
+		tournament.RefundPolicy = current.RefundPolicy
+	}
+	if tournament.TeamFee == "" {
+		tournament.TeamFee = current.TeamFee
+	}
+	if tournament.TeamEntry && tournament.TeamFee == "" {
+		tournament.TeamFee = model.SplitTeamFee
+	}
+	if tournament.Format == "" {
+		tournament.Format = current.Format
+	}
+	if tournament.Seeding == "" {
+		tournament.Seeding = current.Seeding
+	}
+	if tournament.Rounds == 0 {
+		tournament.Rounds = current.Rounds
+	}
+	if tournament.GroupSize == 0 {
+		tournament.GroupSize = current.GroupSize
+	}
+	if tournament.AdvancePerGroup == 0 {
+		tournament.AdvancePerGroup = current.AdvancePerGroup
+	}
+	if tournament.RegistrationOpensAt == nil {
+		tournament.RegistrationOpensAt = current.RegistrationOpensAt
+	}
+	if tournament.RegistrationClosesAt == nil {
+		tournament.RegistrationClosesAt = current.RegistrationClosesAt
+	}
+	if tournament.StartsAt == nil {
+		tournament.StartsAt = current.StartsAt
+	}
+	if tournament.EndsAt == nil {
+		tournament.EndsAt = current.EndsAt
+	}
+	if tournament.Format == model.GroupStage && tournament.GroupSize == 0 {
+		tournament.GroupSize = model.DefaultGroupSize
+	}
+	if tournament.Format == model.GroupStage && tournament.AdvancePerGroup == 0 {
+		tournament.AdvancePerGroup = model.DefaultAdvancePerGroup
+	}
+	if tournament.Format != current.Format && current.Status == model.Ongoing {
+		return fmt.Errorf("%w: the format of an ongoing tournament cannot be changed", ErrInvalidTransition)
+	}
+	if tournament.TeamEntry != current.TeamEntry && len(current.Users) > 0 {
+		return fmt.Errorf("%w: team_entry cannot change once players joined", ErrInvalidTransition)
+	}
+	if joined := len(entrants(current)); tournament.MaxPlayers < joined {
+		return fmt.Errorf("%w: tournament max_players cannot be less than the %d joined entrants", ErrInvalidTournament, joined)
+	}
+
+	if err := validation.ValidateTournament(tournament); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidTournament, err)
+	}
 	return crud.UpdateTournament(tournament)
 }
 
+// OpenRegistration lets players join a planned tournament
+func OpenRegistration(tournamentID uint) error {
+	tournament, err := crud.GetTournamentByID(tournamentID)
+	if err != nil {
+		return err
+	}
+	return transitionTournament(tournament, model.RegistrationOpen)
+}
+
+// StartTournament closes the registration and starts the tournament once min_players users joined,
+// the opening matches of the tournament's format are generated from the roster and created with the status change
+func StartTournament(tournamentID uint) error {
+	tournament, err := crud.StartTournament(tournamentID, func(tournament *model.Tournament) ([]model.Match, error) {
+		if joined := len(entrants(tournament)); joined < tournament.MinPlayers {
+			return nil, fmt.Errorf("%w: %d of %d joined", ErrNotEnoughPlayers, joined, tournament.MinPlayers)
+		}
+		return startMatches(tournament)
+	})
+	if err != nil {
+		return err
+	}
+	return openStandings(tournament)
+}
+
+// startWhenFull starts a tournament the latest entry filled and returns its opening matches,
+// a tournament with seats left is not changed
+func startWhenFull(tournament *model.Tournament) ([]model.Match, error) {
+	if len(entrants(tournament)) < tournament.MaxPlayers {
+		return nil, nil
+	}
+	return startMatches(tournament)
+}
+
+// CancelTournament cancels a tournament that has not finished yet, refunds the entry fees
+// under the tournament's refund policy and drops its live leaderboard
+func CancelTournament(tournamentID uint) error {
+	var statusBeforeCancel model.TournamentStatus
+	tournament, err := crud.CancelTournament(tournamentID, func(tournament *model.Tournament) error {
+		if !CanTransition(tournament.Status, model.Cancelled) {
+			return fmt.Errorf("%w: %s -> %s", ErrInvalidTransition, tournament.Status, model.Cancelled)
+		}
+		statusBeforeCancel = tournament.Status
+		tournament.Status = model.Cancelled
+		return nil
+	}, func(tournament *model.Tournament, user *model.User, paid int) error {
+		user.Money += refundAmount(tournament.RefundPolicy, statusBeforeCancel, paid)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, user := range tournament.Users {
+		if err := crud.UpdateLeaderboard(fmt.Sprintf("%d", user.ID), model.CalculateScore(&user)); err != nil {
+			return err
+		}
+	}
+	return RemoveTournamentLeaderboard(tournament.ID)
+}
+
+// LeaveTournament withdraws a user from a tournament and refunds the entry fee under the tournament's refund policy.
+// Players can only leave while the registration is open, once the matches are drawn they have to be played.
+func LeaveTournament(tournamentID, userID uint) error {
+	tournament, user, err := crud.LeaveTournament(tournamentID, userID, func(tournament *model.Tournament, user *model.User, paid int) error {
+		if tournament.Status != model.RegistrationOpen {
+			return fmt.Errorf("%w: cannot leave a %s tournament", ErrInvalidTransition, tournament.Status)
+		}
+		if tournament.TeamEntry {
+			return ErrTeamTournament
+		}
+		if !hasJoined(tournament, user.ID) {
+			return ErrNotJoined
+		}
+
+		user.Money += refundAmount(tournament.RefundPolicy, tournament.Status, paid)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := crud.UpdateLeaderboard(fmt.Sprintf("%d", user.ID), model.CalculateScore(user)); err != nil {
+		return err
+	}
+	return crud.RemoveFromTournamentLeaderboard(tournament.ID, fmt.Sprintf("%d", user.ID))
+}
+
+// refundAmount returns how much of the paid entry fee goes back to the user
+func refundAmount(policy model.RefundPolicy, status model.TournamentStatus, paid int) int {
+	switch policy {
+	case model.FullRefund:
+		return paid
+	case model.NoRefund:
+		return 0
+	default:
+		if status == model.Planned || status == model.RegistrationOpen {
+			return paid
+		}
+		return 0
+	}
+}
+
+// DeleteTournament deletes a tournament, an unfinished one is cancelled first so the entry fees are refunded
 func DeleteTournament(id uint) error {
+	tournament, err := crud.GetTournamentByID(id)
+	if err != nil {
+		return err
+	}
+	if CanTransition(tournament.Status, model.Cancelled) {
+		if err := CancelTournament(id); err != nil {
+			return err
+		}
+	}
 	return crud.DeleteTournament(id)
 }
 
@@ -47,76 +327,109 @@ func GetOngoingTournaments() ([]model.Tournament, error) {
 	return crud.GetOngoingTournaments()
 }
 
+// EndTournament finalizes an ongoing tournament and distributes the prizes
 func EndTournament(tournamentID uint) error {
 	tournament, err := crud.GetTournamentByID(tournamentID)
 	if err != nil {
 		return err
 	}
 
-	//10 kişiden fazla katılım olursa ya da turnuva elle bitirilirse
-	if len(tournament.Users) >= 10 || tournament.Status == model.Finished {
-		tournament.Status = model.Finished
-		if err := crud.UpdateTournament(tournament); err != nil {
-			return err
-		}
-		return nil
+	// min_players kişiden az katılım varsa turnuva bitirilemez
+	if joined := len(entrants(tournament)); joined < tournament.MinPlayers {
+		return fmt.Errorf("%w: %d of %d joined", ErrNotEnoughPlayers, joined, tournament.MinPlayers)
 	}
 
-	return fmt.Errorf("tournament cannot be ended")
+	return FinalizeTournament(tournament.ID)
 }
 
-// JoinTournament allows a user to join a tournament
-func JoinTournament(tournamentID, userID uint) error {
-	tournament, err := crud.GetTournamentByID(tournamentID)
-	if err != nil {
-		return err
+// acceptsEntries reports whether the tournament's registration is open and its registration_closes_at has not passed
+func acceptsEntries(tournament *model.Tournament, now time.Time) bool {
+	if tournament.Status != model.RegistrationOpen {
+		return false
 	}
+	return tournament.RegistrationClosesAt == nil || now.Before(*tournament.RegistrationClosesAt)
+}
 
-	// Check if the tournament is already finished
-	if tournament.Status == model.Finished {
-		return fmt.Errorf("cannot join a finished tournament")
-	}
+// JoinTournament allows a user to join a tournament.
+// Registration, entry fee and auto-start with the opening matches are applied in one Postgres transaction,
+// Redis is only updated after that transaction is committed.
+func JoinTournament(tournamentID, userID uint) error {
+	tournament, user, err := crud.JoinTournament(tournamentID, userID, func(tournament *model.Tournament, user *model.User) error {
+		// Only tournaments with an open registration accept new players
+		if !acceptsEntries(tournament, time.Now()) {
+			return ErrRegistrationClosed
+		}
+		if tournament.TeamEntry {
+			return ErrTeamTournament
+		}
+		if hasJoined(tournament, user.ID) {
+			return ErrAlreadyJoined
+		}
+		if len(tournament.Users) >= tournament.MaxPlayers {
+			return ErrTournamentFull
+		}
 
-	user, err := crud.GetUserByID(userID)
+		// Decrease user's money by the tournament's entry fee
+		if user.Money < tournament.Fee() {
+			return fmt.Errorf("user does not have enough money to join the tournament")
+		}
+		user.Money -= tournament.Fee()
+		return nil
+	}, startWhenFull)
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return ErrAlreadyJoined
+	}
 	if err != nil {
 		return err
 	}
 
-	// Decrease user's money by 50
-	if user.Money < 50 {
-		return fmt.Errorf("user does not have enough money to join the tournament")
+	// Update the global leaderboard in Redis and put the user on the tournament's leaderboard,
+	// where they collect points from their match results
+	if err := crud.UpdateLeaderboard(fmt.Sprintf("%d", user.ID), model.CalculateScore(user)); err != nil {
+		return err
 	}
-	user.Money -= 50
-	if err := crud.UpdateUser(user); err != nil {
+	if err := UpdateTournamentLeaderboard(tournament.ID, user.ID, 0); err != nil {
 		return err
 	}
 
-	tournament.Users = append(tournament.Users, *user)
-	if len(tournament.Users) >= 10 {
-		tournament.Status = model.Finished // Finish the tournament if user count exceeds 10
-		if err := SetLeaderboard("leaderboard:"+tournament.Name, tournament.Users); err != nil {
-			return err
-		}
-		if err := FinalizeTournament("leaderboard:" + tournament.Name); err != nil {
-			return err
-		}
-	}
-	if err := crud.UpdateTournament(tournament); err != nil {
-		return err
+	// The join that filled the tournament started it
+	if tournament.Status == model.Ongoing {
+		return openStandings(tournament)
 	}
+	return nil
+}
 
-	// Update leaderboard in Redis when someone joins the tournament
-	score := calculateScore(user)
-	if err := crud.UpdateLeaderboard(fmt.Sprintf("%d", user.ID), score); err != nil {
-		return err
+// GetTournamentLeaderboard returns the live ranking of a tournament from Redis with each user's profile data,
+// a team tournament ranks its teams under their captains
+func GetTournamentLeaderboard(tournamentID uint, start, stop int64) ([]model.LeaderboardEntry, error) {
+	tournament, err := crud.GetTournamentByID(tournamentID)
+	if err != nil {
+		return nil, err
+	}
+	leaderboard, err := crud.GetTournamentLeaderboard(tournamentID, start, stop)
+	if err != nil {
+		return nil, err
 	}
+	return enrichEntries(tournament, rankEntries(leaderboard, start))
+}
 
-	return nil
+// UpdateTournamentLeaderboard sets a user's score on a tournament's ranking
+func UpdateTournamentLeaderboard(tournamentID, userID uint, score float64) error {
+	return crud.UpdateTournamentLeaderboard(tournamentID, fmt.Sprintf("%d", userID), score)
 }
 
-// Status active olan leaderboardları görmek için
-func GetActiveLeaderboard(start, stop int64) ([]model.Leaderboard, error) {
-	leaderboard, err := crud.GetLeaderboard(start, stop)
+// RemoveTournamentLeaderboard drops a tournament's ranking from Redis
+func RemoveTournamentLeaderboard(tournamentID uint) error {
+	return crud.RemoveLeaderboardFromRedis(tournamentID)
+}
+
+// Status active olan leaderboardları görmek için, an empty window reads the all-time board
+func GetActiveLeaderboard(window model.LeaderboardWindow, start, stop int64) ([]model.Leaderboard, error) {
+	window, err := leaderboardWindow(window)
+	if err != nil {
+		return nil, err
+	}
+	leaderboard, err := crud.GetLeaderboard(window, start, stop)
 	if err != nil {
 		return nil, err
 	}
@@ -130,31 +443,84 @@ func GetActiveLeaderboard(start, stop int64) ([]model.Leaderboard, error) {
 	return activeLeaderboard, nil
 }
 
-// bir kişinin katıldığı active leaderboardları görmek için
+// leaderboardWindow checks the requested leaderboard window, no window means the all-time board
+func leaderboardWindow(window model.LeaderboardWindow) (model.LeaderboardWindow, error) {
+	if window == "" {
+		return model.AllTimeWindow, nil
+	}
+	if err := validation.ValidateLeaderboardWindow(window); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidWindow, err)
+	}
+	return window, nil
+}
+
+// bir kişinin katıldığı active leaderboardları görmek için, the user's entries on the live boards in Redis.
+// On a team tournament's board the entry is the one of the team the user entered with.
 func GetActiveLeaderboardByUserID(userID uint) ([]model.Leaderboard, error) {
-	leaderboard, err := crud.GetLeaderboardByUserID(userID)
+	statuses, err := crud.GetLeaderboardStatuses()
+	if err != nil {
+		return nil, err
+	}
+	tournaments, err := crud.GetLiveTournaments()
 	if err != nil {
 		return nil, err
 	}
 
-	var activeLeaderboard []model.Leaderboard
-	for _, entry := range leaderboard {
-		if entry.Status == model.Active {
-			activeLeaderboard = append(activeLeaderboard, entry)
+	activeLeaderboard := []model.Leaderboard{}
+	for i := range tournaments {
+		tournament := &tournaments[i]
+		if statuses[tournament.ID] != model.Active {
+			continue
+		}
+		entry := model.Leaderboard{UserID: userID, TournamentID: tournament.ID, Status: model.Active}
+		member := userID
+		if tournament.TeamEntry {
+			member = 0
+			for j := range tournament.Teams {
+				if isMember(&tournament.Teams[j], userID) {
+					member = tournament.Teams[j].ID
+					entry.TeamID = member
+				}
+			}
+			if member == 0 {
+				continue
+			}
+		}
+
+		score, found, err := crud.GetTournamentScore(tournament.ID, member)
+		if err != nil {
+			return nil, err
 		}
+		if !found {
+			continue
+		}
+		entry.Score = score
+		activeLeaderboard = append(activeLeaderboard, entry)
 	}
 	return activeLeaderboard, nil
 }
 
-// Turnuvaya ait active leaderboardu görmek için
+// Turnuvaya ait active leaderboardu görmek için, empty once the tournament was finalized
 func GetActiveLeaderboardByTournamentID(tournamentID uint) ([]model.Leaderboard, error) {
-	leaderboard, err := crud.GetLeaderboardByTournamentID(tournamentID)
+	status, err := crud.GetLeaderboardStatus(tournamentID)
 	if err != nil {
 		return nil, err
 	}
+	if status != model.Active {
+		return []model.Leaderboard{}, nil
+	}
 
-	var activeLeaderboard []model.Leaderboard
-	for _, entry := range leaderboard {
+	tournament, err := crud.GetTournamentByID(tournamentID)
+	if err != nil {
+		return nil, err
+	}
+	leaderboard, err := crud.GetTournamentLeaderboard(tournamentID, 0, -1)
+	if err != nil {
+		return nil, err
+	}
+
+	activeLeaderboard := []model.Leaderboard{}
+	for _, entry := range teamLeaderboard(tournament, leaderboard) {
 		if entry.Status == model.Active {
 			activeLeaderboard = append(activeLeaderboard, entry)
 		}
@@ -178,89 +544,145 @@ func GetFinishedLeaderboardByTournamentID(tournamentID uint) ([]model.Leaderboar
 	return finishedLeaderboard, nil
 }
 
-func FinalizeTournament(key string) error {
-
-	tournament, err := crud.GetTournamentByKey(key)
-	if err != nil {
-		return fmt.Errorf("failed to retrieve tournament: %v", err)
-	}
-
-	// Check if the tournament is active
-	if tournament.Status != model.Ongoing {
-		return fmt.Errorf("tournament is not active (status=ongoing)")
-	}
-
-	// Retrieve the leaderboard for the tournament
-	leaderboard, err := GetActiveLeaderboardByTournamentID(tournament.ID)
-	if err != nil {
-		return fmt.Errorf("failed to retrieve leaderboard: %v", err)
-	}
+// FinalizeTournament pays the prizes, saves the final leaderboard and finishes the tournament in a single
+// transaction on the locked tournament, so a tournament is paid out once even when the scheduler and a manual
+// end race or a step fails and is retried. The clan and season points and the Redis leaderboard follow.
+func FinalizeTournament(tournamentID uint) error {
+	tournament, leaderboard, err := crud.FinalizeTournament(tournamentID, func(tournament *model.Tournament) ([]model.Leaderboard, map[uint]int, error) {
+		// Only an ongoing tournament can be finalized
+		if !CanTransition(tournament.Status, model.Finished) {
+			return nil, nil, fmt.Errorf("%w: %s -> %s", ErrInvalidTransition, tournament.Status, model.Finished)
+		}
 
-	// Distribute prizes based on the leaderboard standings
-	for i, entry := range leaderboard {
-		user, err := crud.GetUserByID(entry.UserID)
+		leaderboard, err := tournamentRanking(tournament)
 		if err != nil {
-			return fmt.Errorf("failed to retrieve user: %v", err)
+			return nil, nil, fmt.Errorf("failed to retrieve leaderboard: %v", err)
 		}
 
-		// Calculate prize based on position
-		prize := calculatePrize(tournament.Prize, i+1)
-		user.Money += prize
+		strategy, err := NewPrizeStrategy(tournament.PrizeStrategy)
+		if err != nil {
+			return nil, nil, err
+		}
+		payouts := strategy.Payouts(tournament.Prize, len(leaderboard))
+
+		// Distribute prizes based on the leaderboard standings
+		prizes := map[uint]int{}
+		for i, entry := range leaderboard {
+			if i >= len(payouts) {
+				break
+			}
+			shares, err := prizeShares(tournament, entry, payouts[i])
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to pay prize: %v", err)
+			}
+			for userID, share := range shares {
+				prizes[userID] += share
+			}
+		}
 
-		// Update user
-		if err := crud.UpdateUser(user); err != nil {
-			return fmt.Errorf("failed to update user: %v", err)
+		// The leaderboard is saved to PostgreSQL as passive, its points count for the season the tournament was created in
+		for i := range leaderboard {
+			leaderboard[i].Status = model.Passive
+			leaderboard[i].SeasonID = tournament.SeasonID
 		}
-	}
 
-	// Save the leaderboard to PostgreSQL
-	if err := crud.SaveLeaderboard(tournament.ID, leaderboard); err != nil {
-		return fmt.Errorf("failed to save leaderboard: %v", err)
+		tournament.Status = model.Finished
+		return leaderboard, prizes, nil
+	})
+	if err != nil {
+		return err
 	}
 
-	// Update leaderboard status to passive
-	for _, entry := range leaderboard {
-		entry.Status = model.Passive
-		if err := crud.UpdateLeaderboardEntry(&entry); err != nil {
-			return fmt.Errorf("failed to update leaderboard entry: %v", err)
-		}
+	// The players' points count towards their clans and the season
+	var season uint
+	if tournament.SeasonID != nil {
+		season = *tournament.SeasonID
 	}
-
-	// Remove the leaderboard from Redis
-	if err := crud.RemoveLeaderboardFromRedis(tournament.ID); err != nil {
-		return fmt.Errorf("failed to remove leaderboard from Redis: %v", err)
+	if err := recordClanResults(tournament, leaderboard, season); err != nil {
+		return fmt.Errorf("failed to update clan leaderboard: %v", err)
 	}
 
-	// Update the tournament status to closed
-	tournament.Status = model.Finished
-	if err := crud.UpdateTournament(tournament); err != nil { // pointer used for tournament
-		return fmt.Errorf("failed to update tournament: %v", err)
+	// Mark the leaderboard in Redis passive, its live ranking is no longer needed
+	if err := crud.ArchiveTournamentLeaderboard(tournament.ID); err != nil {
+		return fmt.Errorf("failed to archive leaderboard in Redis: %v", err)
 	}
 
 	return nil
 }
 
-// calculatePrize calculates the prize based on the total prize pool and the position
-func calculatePrize(totalPrize, position int) int {
-	switch position {
-	case 1:
-		return totalPrize / 2 // 50% for 1st place
-	case 2:
-		return totalPrize / 4 // 25% for 2nd place
-	case 3:
-		return totalPrize / 8 // 12.5% for 3rd place
+// prizeShares splits a ranked entry's prize by user, the prize of a team is split across the members who entered with it
+func prizeShares(tournament *model.Tournament, entry model.Leaderboard, prize int) (map[uint]int, error) {
+	if entry.TeamID == 0 {
+		return map[uint]int{entry.UserID: prize}, nil
+	}
+	memberIDs, err := crud.GetTeamEntryMembers(tournament.ID, entry.TeamID)
+	if err != nil {
+		return nil, err
+	}
+	team := &model.Team{CaptainID: entry.UserID}
+	for _, memberID := range memberIDs {
+		team.Members = append(team.Members, model.User{ID: memberID})
+	}
+	return teamShares(team, prize, model.SplitTeamFee), nil
+}
+
+// tournamentRanking returns the final ranking of a tournament, best first. Elimination tournaments are ranked
+// by how far each player got in the bracket, group stage tournaments by their knockout and then their groups,
+// round-robin and swiss tournaments by their standings table.
+func tournamentRanking(tournament *model.Tournament) ([]model.Leaderboard, error) {
+	var standings []model.Standing
+	switch tournament.Format {
+	case model.SingleElimination, model.DoubleElimination, model.GroupStage:
+		matches, err := crud.GetMatchesByTournamentID(tournament.ID)
+		if err != nil {
+			return nil, err
+		}
+		if tournament.Format == model.GroupStage {
+			standings = RankGroupStage(entrants(tournament), matches)
+		} else {
+			standings = RankElimination(SeedPlayers(entrants(tournament), tournament.Seeding), matches)
+		}
 	default:
-		return totalPrize / 16 // 6.25% for other places
+		var err error
+		if standings, err = tournamentStandings(tournament); err != nil {
+			return nil, err
+		}
+	}
+
+	leaderboard := make([]model.Leaderboard, 0, len(standings))
+	for _, standing := range standings {
+		leaderboard = append(leaderboard, model.Leaderboard{
+			UserID:       standing.UserID,
+			TournamentID: tournament.ID,
+			Score:        float64(standing.Points),
+		})
+	}
+	return teamLeaderboard(tournament, leaderboard), nil
+}
+
+// teamLeaderboard turns the entries of a team tournament's leaderboard, which are kept by team ID,
+// into entries of the team led by its captain
+func teamLeaderboard(tournament *model.Tournament, leaderboard []model.Leaderboard) []model.Leaderboard {
+	if !tournament.TeamEntry {
+		return leaderboard
 	}
+	for i := range leaderboard {
+		leaderboard[i].TeamID = leaderboard[i].UserID
+		if team := tournamentTeam(tournament, leaderboard[i].TeamID); team != nil {
+			leaderboard[i].UserID = team.CaptainID
+		}
+	}
+	return leaderboard
 }
 
-func SetLeaderboard(key string, users []model.User) error {
+func SetLeaderboard(tournamentID uint, users []model.User) error {
 	for _, user := range users {
-		score := calculateScore(&user)
+		score := model.CalculateScore(&user)
 		if err := crud.CreateLeaderboardEntry(&model.Leaderboard{
-			UserID: user.ID,
-			Score:  score,
-			Status: model.Active,
+			UserID:       user.ID,
+			TournamentID: tournamentID,
+			Score:        score,
+			Status:       model.Active,
 		}); err != nil {
 			return err
 		}
@@ -269,27 +691,26 @@ func SetLeaderboard(key string, users []model.User) error {
 }
 
 func LevelUpUser(userID uint) error {
-	user, err := crud.GetUserByID(userID)
-	if err != nil {
-		return err
-	}
-
-	// Calculate the cost to level up
-	cost := 100 + (user.Level * 50)
-
-	if user.Money < cost {
-		return fmt.Errorf("insufficient funds")
-	}
-
-	// Deduct the cost and increase the user's level
-	user.Money -= cost
-	user.Level += 1
+	user, err := applyTransaction(&model.Transaction{
+		UserID: userID,
+		Reason: model.LevelUpTransaction,
+	}, func(user *model.User) error {
+		// Calculate the cost to level up
+		cost := 100 + (user.Level * 50)
+
+		if user.Money < cost {
+			return fmt.Errorf("insufficient funds")
+		}
 
-	// Recalculate the user's score
-	user.Score = calculateScore(user)
+		// Deduct the cost and increase the user's level
+		user.Money -= cost
+		user.Level += 1
 
-	// Update the user's data in PostgreSQL
-	if err := crud.UpdateUser(user); err != nil {
+		// Recalculate the user's score
+		user.Score = model.CalculateScore(user)
+		return nil
+	})
+	if err != nil {
 		return err
 	}
 
@@ -301,11 +722,6 @@ func LevelUpUser(userID uint) error {
 	return nil
 }
 
-// calculateScore calculates the score for a user based on their level and other factors
-func calculateScore(user *model.User) float64 {
-	return float64(user.Level*100 + user.Money)
-}
-
 func CreateLeaderboardEntry(entry *model.Leaderboard) error {
 	// If UserID is not provided, skip user-related operations
 	if entry.UserID == 0 {
@@ -318,7 +734,7 @@ func CreateLeaderboardEntry(entry *model.Leaderboard) error {
 	}
 
 	// Calculate score based on user's level and money every time a new leaderboard is created
-	entry.Score = calculateScore(user)
+	entry.Score = model.CalculateScore(user)
 
 	return crud.CreateLeaderboardEntry(entry)
 }
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"fmt"
 	"tournament-app/internal/crud"
 	"tournament-app/model"
 	"tournament-app/validation"
@@ -11,15 +12,54 @@ func CreateUser(user *model.User) error {
 	if err := validation.ValidateUser(user); err != nil {
 		return err
 	}
-	return crud.CreateUser(user)
+	if err := crud.CreateUser(user); err != nil {
+		return err
+	}
+
+	// A new user enters the global leaderboard and the rating ranking with the starting rating
+	if err := crud.UpdateLeaderboard(fmt.Sprintf("%d", user.ID), model.CalculateScore(user)); err != nil {
+		return err
+	}
+	return crud.UpdateRatingRanking(user.ID, user.Rating)
 }
 
-// UpdateUser validates and updates an existing user
+// UpdateUser validates and updates an existing user, a money change is recorded as an admin adjustment.
+// The rating only changes through rated matches.
 func UpdateUser(user *model.User) error {
 	if err := validation.ValidateUser(user); err != nil {
 		return err
 	}
-	return crud.UpdateUser(user)
+	_, err := applyTransaction(&model.Transaction{
+		UserID: user.ID,
+		Reason: model.AdminAdjustTransaction,
+	}, func(stored *model.User) error {
+		rating := stored.SkillRating
+		*stored = *user
+		stored.SkillRating = rating
+		return nil
+	})
+	return err
+}
+
+// GetUserTransactions returns the wallet ledger of a user, oldest first
+func GetUserTransactions(userID uint) ([]model.Transaction, error) {
+	if _, err := crud.GetUserByID(userID); err != nil {
+		return nil, err
+	}
+	return crud.GetTransactionsByUserID(userID)
+}
+
+// ReconcileWallets flags the users whose money disagrees with their ledger sum
+func ReconcileWallets() ([]model.WalletMismatch, error) {
+	return crud.GetWalletMismatches()
+}
+
+// applyTransaction validates the ledger transaction before the user's wallet is changed
+func applyTransaction(transaction *model.Transaction, apply func(user *model.User) error) (*model.User, error) {
+	if err := validation.ValidateTransaction(transaction); err != nil {
+		return nil, err
+	}
+	return crud.ApplyTransaction(transaction, apply)
 }
 
 // GetUserByID retrieves a user by their ID
@@ -46,8 +86,25 @@ func PerformHealthCheck() (string, error) {
 	return "Service is healthy", nil
 }
 
+// DeleteUser deletes a user, a clan member leaves their clan first
 func DeleteUser(id uint) error {
-	return crud.DeleteUser(id)
+	memberships, err := crud.GetClanMemberships([]uint{id})
+	if err != nil {
+		return err
+	}
+	for _, membership := range memberships {
+		if err := LeaveClan(membership.ClanID, id); err != nil {
+			return err
+		}
+	}
+
+	if err := crud.DeleteUser(id); err != nil {
+		return err
+	}
+	if err := crud.RemoveFromLeaderboard(fmt.Sprintf("%d", id)); err != nil {
+		return err
+	}
+	return crud.RemoveFromRatingRanking(id)
 }
 
 func ClearDatabase() error {
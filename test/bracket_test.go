@@ -0,0 +1,433 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+
+	"tournament-app/model"
+	"tournament-app/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// makePlayers returns n users with IDs 1..n and descending levels, so user 1 is the top seed
+func makePlayers(n int) []model.User {
+	players := make([]model.User, n)
+	for i := range players {
+		players[i] = model.User{ID: uint(i + 1), Name: fmt.Sprintf("Player%d", i+1), Level: 100 - i}
+	}
+	return players
+}
+
+func TestSeedPlayers(t *testing.T) {
+	players := []model.User{
+		{ID: 1, Level: 2, Score: 900},
+		{ID: 2, Level: 5, Score: 100},
+		{ID: 3, Level: 5, Score: 500},
+	}
+
+	byLevel := service.SeedPlayers(players, model.SeedByLevel)
+	assert.Equal(t, []uint{2, 3, 1}, []uint{byLevel[0].ID, byLevel[1].ID, byLevel[2].ID})
+
+	byScore := service.SeedPlayers(players, model.SeedByScore)
+	assert.Equal(t, []uint{1, 3, 2}, []uint{byScore[0].ID, byScore[1].ID, byScore[2].ID})
+}
+
+func TestGenerateSingleElimination(t *testing.T) {
+	tests := []struct {
+		players int
+		size    int
+	}{
+		{2, 2}, {3, 4}, {4, 4}, {5, 8}, {6, 8}, {7, 8}, {8, 8}, {9, 16}, {12, 16}, {16, 16},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%d players", tt.players), func(t *testing.T) {
+			matches := service.GenerateSingleElimination(7, makePlayers(tt.players))
+			require.Len(t, matches, tt.size-1)
+
+			codes := map[string]model.Match{}
+			for _, match := range matches {
+				assert.Equal(t, uint(7), match.TournamentID)
+				codes[match.Code] = match
+			}
+
+			finals := 0
+			byes := 0
+			seen := map[uint]int{}
+			for _, match := range matches {
+				if match.WinnerTo == "" {
+					finals++
+				} else {
+					assert.Contains(t, codes, match.WinnerTo, "%s advances into a known match", match.Code)
+				}
+				if match.Round == 1 {
+					for _, player := range []*uint{match.Player1ID, match.Player2ID} {
+						if player != nil {
+							seen[*player]++
+						}
+					}
+					if match.Status == model.MatchCompleted {
+						byes++
+						// A bye winner is already waiting in the next round
+						next := codes[match.WinnerTo]
+						assert.True(t, next.HasPlayer(*match.WinnerID))
+						assert.LessOrEqual(t, *match.WinnerID, uint(tt.size-tt.players), "byes go to the top seeds")
+					}
+				}
+			}
+			assert.Equal(t, 1, finals)
+			assert.Equal(t, tt.size-tt.players, byes)
+			assert.Len(t, seen, tt.players)
+			for player, count := range seen {
+				assert.Equal(t, 1, count, "player %d is placed once", player)
+			}
+		})
+	}
+}
+
+func TestSingleEliminationSeedsMeetInFinal(t *testing.T) {
+	matches := service.GenerateSingleElimination(1, makePlayers(8))
+	first := matches[0]
+	assert.Equal(t, "W1-1", first.Code)
+	assert.Equal(t, uint(1), *first.Player1ID)
+	assert.Equal(t, uint(8), *first.Player2ID)
+
+	// Seeds 1 and 2 start in different halves of the bracket
+	var half = map[uint]string{}
+	for _, match := range matches[:4] {
+		half[*match.Player1ID] = codeHalf(match.Position)
+		half[*match.Player2ID] = codeHalf(match.Position)
+	}
+	assert.NotEqual(t, half[1], half[2])
+}
+
+func codeHalf(position int) string {
+	if position <= 2 {
+		return "top"
+	}
+	return "bottom"
+}
+
+// rankedIDs returns the user IDs of a ranking, best first
+func rankedIDs(ranking []model.Standing) []uint {
+	ids := make([]uint, 0, len(ranking))
+	for _, standing := range ranking {
+		ids = append(ids, standing.UserID)
+	}
+	return ids
+}
+
+func TestRankElimination(t *testing.T) {
+	// The top seed's bye puts them into the final after a single match, level on wins with the finalist
+	players := makePlayers(3)
+	matches := service.GenerateSingleElimination(1, players)
+	_, err := service.ApplyBracketResult(matches, "W1-2", 0, 2)
+	require.NoError(t, err)
+	_, err = service.ApplyBracketResult(matches, "W2-1", 2, 1)
+	require.NoError(t, err)
+	ranking := service.RankElimination(players, matches)
+	assert.Equal(t, []uint{1, 3, 2}, rankedIDs(ranking))
+	assert.Equal(t, []int{1, 2, 3}, []int{ranking[0].Rank, ranking[1].Rank, ranking[2].Rank})
+
+	// Every underdog wins until the final: the semi-final losers follow the finalist, then the first round losers,
+	// both in seed order
+	players = makePlayers(8)
+	matches = service.GenerateSingleElimination(1, players)
+	for _, code := range []string{"W1-1", "W1-2", "W1-3", "W1-4", "W2-1", "W2-2"} {
+		_, err := service.ApplyBracketResult(matches, code, 0, 1)
+		require.NoError(t, err)
+	}
+	_, err = service.ApplyBracketResult(matches, "W3-1", 1, 0)
+	require.NoError(t, err)
+	ranking = service.RankElimination(players, matches)
+	assert.Equal(t, []uint{5, 6, 7, 8, 1, 2, 3, 4}, rankedIDs(ranking))
+}
+
+func TestGenerateDoubleElimination(t *testing.T) {
+	for players := 2; players <= 32; players++ {
+		for _, reset := range []bool{false, true} {
+			t.Run(fmt.Sprintf("%d players reset %v", players, reset), func(t *testing.T) {
+				matches := service.GenerateDoubleElimination(3, makePlayers(players), reset)
+				size := 2
+				for size < players {
+					size *= 2
+				}
+
+				sides := map[model.BracketSide]int{}
+				codes := map[string]model.Match{}
+				for _, match := range matches {
+					sides[match.Bracket]++
+					codes[match.Code] = match
+				}
+				require.Len(t, codes, len(matches), "codes are unique")
+				assert.Equal(t, size-1, sides[model.WinnersBracket])
+				assert.Equal(t, size-2, sides[model.LosersBracket])
+				finals := 1
+				if reset {
+					finals = 2
+				}
+				assert.Equal(t, finals, sides[model.GrandFinalBracket])
+
+				// Every slot is fed by exactly one match or starts with a player
+				fed := map[string]int{}
+				for _, match := range matches {
+					if match.WinnerTo != "" {
+						next, ok := codes[match.WinnerTo]
+						require.True(t, ok, "%s advances into a known match", match.Code)
+						if match.Bracket == model.LosersBracket {
+							assert.NotEqual(t, model.WinnersBracket, next.Bracket)
+						}
+						fed[fmt.Sprintf("%s/%d", match.WinnerTo, match.WinnerToSlot)]++
+					}
+					if match.LoserTo != "" {
+						assert.Equal(t, model.WinnersBracket, match.Bracket, "only winners bracket losers drop down")
+						assert.NotEqual(t, model.WinnersBracket, codes[match.LoserTo].Bracket)
+						fed[fmt.Sprintf("%s/%d", match.LoserTo, match.LoserToSlot)]++
+					}
+				}
+				for _, match := range matches {
+					if match.Code == "GF2" {
+						continue
+					}
+					for slot := 1; slot <= 2; slot++ {
+						key := fmt.Sprintf("%s/%d", match.Code, slot)
+						if match.Bracket == model.WinnersBracket && match.Round == 1 {
+							assert.Zero(t, fed[key], key)
+						} else {
+							assert.Equal(t, 1, fed[key], key)
+						}
+					}
+				}
+
+				// Every winners bracket loser drops into the losers round that waits for them: round 1 losers
+				// pair up in order, later losers take the second slot of an even losers round, in reverse
+				// order every other round so players do not meet again right away
+				for _, match := range matches {
+					if match.Bracket != model.WinnersBracket {
+						continue
+					}
+					count := size >> match.Round
+					want := "GF/2"
+					switch {
+					case size == 2:
+						// the loser of the only match gets a second chance in the grand final
+					case match.Round == 1:
+						want = fmt.Sprintf("L1-%d/%d", (match.Position+1)/2, 2-match.Position%2)
+					case match.Round%2 == 0:
+						want = fmt.Sprintf("L%d-%d/2", 2*(match.Round-1), count+1-match.Position)
+					default:
+						want = fmt.Sprintf("L%d-%d/2", 2*(match.Round-1), match.Position)
+					}
+					assert.Equal(t, want, fmt.Sprintf("%s/%d", match.LoserTo, match.LoserToSlot), match.Code)
+				}
+
+				// The favourite wins everything
+				champion, losersChampion := playDoubleElimination(t, matches, favourite)
+				assert.Equal(t, uint(1), champion)
+				assert.Equal(t, model.MatchSkipped, grandFinalReset(matches).Status)
+				assertDoubleEliminationRanking(t, makePlayers(players), matches, champion)
+
+				// The losers bracket champion wins the grand final: with a reset the final is replayed
+				// and the favourite takes it, without one the losers bracket champion is the champion
+				matches = service.GenerateDoubleElimination(3, makePlayers(players), reset)
+				champion, losersChampion = playDoubleElimination(t, matches, func(match model.Match, losersChampion uint) uint {
+					if match.Code == "GF" {
+						return losersChampion
+					}
+					return favourite(match, losersChampion)
+				})
+				if reset {
+					assert.Equal(t, uint(1), champion)
+					assert.Equal(t, model.MatchCompleted, grandFinalReset(matches).Status)
+				} else {
+					assert.Equal(t, losersChampion, champion)
+				}
+				assertDoubleEliminationRanking(t, makePlayers(players), matches, champion)
+
+				// The losers bracket champion wins both grand finals
+				if reset {
+					matches = service.GenerateDoubleElimination(3, makePlayers(players), reset)
+					champion, losersChampion = playDoubleElimination(t, matches, func(match model.Match, losersChampion uint) uint {
+						if match.Bracket == model.GrandFinalBracket {
+							return losersChampion
+						}
+						return favourite(match, losersChampion)
+					})
+					assert.NotEqual(t, uint(1), champion)
+					assert.Equal(t, losersChampion, champion)
+					assert.Equal(t, model.MatchCompleted, grandFinalReset(matches).Status)
+					assertDoubleEliminationRanking(t, makePlayers(players), matches, champion)
+				}
+			})
+		}
+	}
+}
+
+func TestDoubleEliminationDropIns(t *testing.T) {
+	tests := map[int]map[string]string{
+		8: {
+			"W1-1": "L1-1/1", "W1-2": "L1-1/2", "W1-3": "L1-2/1", "W1-4": "L1-2/2",
+			"W2-1": "L2-2/2", "W2-2": "L2-1/2",
+			"W3-1": "L4-1/2",
+		},
+		16: {
+			"W1-1": "L1-1/1", "W1-2": "L1-1/2", "W1-3": "L1-2/1", "W1-4": "L1-2/2",
+			"W1-5": "L1-3/1", "W1-6": "L1-3/2", "W1-7": "L1-4/1", "W1-8": "L1-4/2",
+			"W2-1": "L2-4/2", "W2-2": "L2-3/2", "W2-3": "L2-2/2", "W2-4": "L2-1/2",
+			"W3-1": "L4-1/2", "W3-2": "L4-2/2",
+			"W4-1": "L6-1/2",
+		},
+	}
+	for players, dropIns := range tests {
+		matches := service.GenerateDoubleElimination(1, makePlayers(players), false)
+		got := map[string]string{}
+		for _, match := range matches {
+			if match.LoserTo != "" {
+				got[match.Code] = fmt.Sprintf("%s/%d", match.LoserTo, match.LoserToSlot)
+			}
+		}
+		assert.Equal(t, dropIns, got, "%d players", players)
+	}
+}
+
+// favourite picks the player with the lower user ID as the winner of a match
+func favourite(match model.Match, losersChampion uint) uint {
+	return min(*match.Player1ID, *match.Player2ID)
+}
+
+// grandFinalReset returns the grand final reset of a bracket, or an empty match without one
+func grandFinalReset(matches []model.Match) model.Match {
+	for _, match := range matches {
+		if match.Code == "GF2" {
+			return match
+		}
+	}
+	return model.Match{Status: model.MatchSkipped}
+}
+
+// playDoubleElimination plays every ready match, winner picking who wins it, and returns the champion
+// together with the losers bracket champion who reached the grand final
+func playDoubleElimination(t *testing.T, matches []model.Match, winner func(match model.Match, losersChampion uint) uint) (uint, uint) {
+	losses := map[uint]int{}
+	var losersChampion uint
+	for played := true; played; {
+		played = false
+		for _, match := range matches {
+			if match.Status != model.MatchPending || match.Player1ID == nil || match.Player2ID == nil {
+				continue
+			}
+			if match.Code == "GF" {
+				losersChampion = *match.Player2ID
+			}
+			won := winner(match, losersChampion)
+			score1, score2 := 1, 0
+			if won == *match.Player2ID {
+				score1, score2 = 0, 1
+			}
+			_, err := service.ApplyBracketResult(matches, match.Code, score1, score2)
+			require.NoError(t, err)
+			loser := *match.Player1ID
+			if loser == won {
+				loser = *match.Player2ID
+			}
+			losses[loser]++
+			played = true
+		}
+	}
+
+	var champion uint
+	for _, match := range matches {
+		assert.NotEqual(t, model.MatchPending, match.Status, "%s was played", match.Code)
+		if match.Bracket == model.GrandFinalBracket && match.Status == model.MatchCompleted {
+			champion = *match.WinnerID
+		}
+	}
+	for player, count := range losses {
+		assert.LessOrEqual(t, count, 2, "player %d is out after two losses", player)
+	}
+	assert.Less(t, losses[champion], 2, "the champion lost at most once")
+	return champion, losersChampion
+}
+
+// assertDoubleEliminationRanking checks the ranking of a played double-elimination bracket: the champion first,
+// the other grand finalist second, then everyone by the losers round they were knocked out in, latest first
+func assertDoubleEliminationRanking(t *testing.T, players []model.User, matches []model.Match, champion uint) {
+	t.Helper()
+	var finalists []uint
+	knockedOut := map[uint]int{}
+	for _, match := range matches {
+		switch {
+		case match.Code == "GF":
+			finalists = []uint{*match.Player1ID, *match.Player2ID}
+		case match.Bracket == model.LosersBracket:
+			for _, playerID := range []*uint{match.Player1ID, match.Player2ID} {
+				if playerID != nil && match.Round > knockedOut[*playerID] {
+					knockedOut[*playerID] = match.Round
+				}
+			}
+		}
+	}
+	runnerUp := finalists[0]
+	if runnerUp == champion {
+		runnerUp = finalists[1]
+	}
+
+	ranked := rankedIDs(service.RankElimination(players, matches))
+	require.Len(t, ranked, len(players))
+	assert.Equal(t, []uint{champion, runnerUp}, ranked[:2])
+	for i := 3; i < len(ranked); i++ {
+		assert.GreaterOrEqual(t, knockedOut[ranked[i-1]], knockedOut[ranked[i]], "%d ranks above %d", ranked[i-1], ranked[i])
+	}
+}
+
+func TestRankDoubleEliminationReset(t *testing.T) {
+	// The winners bracket champion loses the first grand final and wins the reset: both finalists
+	// have three wins, the reset decides who ranks first
+	players := makePlayers(4)
+	matches := service.GenerateDoubleElimination(1, players, true)
+	for _, result := range []struct {
+		code           string
+		score1, score2 int
+	}{
+		{"W1-1", 1, 0}, {"W1-2", 1, 0}, {"W2-1", 1, 0}, // 1 beats 4 and 2, 2 beats 3
+		{"L1-1", 1, 0}, {"L2-1", 0, 1}, // 4 beats 3, then 2 beats 4
+		{"GF", 0, 1}, {"GF2", 1, 0},
+	} {
+		_, err := service.ApplyBracketResult(matches, result.code, result.score1, result.score2)
+		require.NoError(t, err, result.code)
+	}
+
+	ranking := service.RankElimination(players, matches)
+	assert.Equal(t, []uint{1, 2, 4, 3}, rankedIDs(ranking))
+	assert.Equal(t, ranking[0].Points, ranking[1].Points, "the finalists are level on wins")
+}
+
+func TestDoubleEliminationGrandFinalReset(t *testing.T) {
+	matches := service.GenerateDoubleElimination(1, makePlayers(2), true)
+	require.Len(t, matches, 3)
+
+	// The winners bracket champion loses the first grand final, so the reset is played
+	_, err := service.ApplyBracketResult(matches, "W1-1", 3, 1)
+	require.NoError(t, err)
+	_, err = service.ApplyBracketResult(matches, "GF", 0, 2)
+	require.NoError(t, err)
+	reset := matches[2]
+	assert.Equal(t, "GF2", reset.Code)
+	assert.Equal(t, model.MatchPending, reset.Status)
+	assert.True(t, reset.HasPlayer(1) && reset.HasPlayer(2))
+
+	_, err = service.ApplyBracketResult(matches, "GF2", 2, 2)
+	assert.ErrorIs(t, err, service.ErrDrawNotAllowed)
+
+	// Without a loss for the winners bracket champion the reset is skipped
+	matches = service.GenerateDoubleElimination(1, makePlayers(2), true)
+	_, err = service.ApplyBracketResult(matches, "W1-1", 3, 1)
+	require.NoError(t, err)
+	_, err = service.ApplyBracketResult(matches, "GF", 2, 0)
+	require.NoError(t, err)
+	assert.Equal(t, model.MatchSkipped, matches[2].Status)
+	_, err = service.ApplyBracketResult(matches, "GF2", 1, 0)
+	assert.ErrorIs(t, err, service.ErrMatchCompleted)
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"testing"
+
+	"tournament-app/internal/crud"
+	"tournament-app/model"
+	"tournament-app/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClanLeaderboard(t *testing.T) {
+	setupRedis(t)
+
+	for _, clanID := range []uint{1, 2} {
+		require.NoError(t, crud.AddClanToLeaderboard(clanID))
+	}
+	// Users 1 and 2 are in clan 1, user 3 in clan 2, user 4 in no clan
+	require.NoError(t, crud.AddTournamentResults(
+		map[uint]float64{1: 6, 2: 3, 3: 7, 4: 9},
+		map[uint]uint{1: 1, 2: 1, 3: 2},
+		0,
+	))
+
+	rankings, err := crud.GetClanLeaderboard(0, -1)
+	require.NoError(t, err)
+	require.Len(t, rankings, 2)
+	assert.Equal(t, model.ClanRanking{Rank: 1, ClanID: 1, Score: 9}, rankings[0])
+	assert.Equal(t, model.ClanRanking{Rank: 2, ClanID: 2, Score: 7}, rankings[1])
+
+	// A member moving to another clan takes their points along
+	require.NoError(t, crud.RemoveClanMemberResults(1, 2))
+	require.NoError(t, crud.AddClanMemberResults(2, 2))
+	// A user without results changes nothing
+	require.NoError(t, crud.AddClanMemberResults(2, 5))
+
+	rankings, err = crud.GetClanLeaderboard(0, -1)
+	require.NoError(t, err)
+	assert.Equal(t, uint(2), rankings[0].ClanID)
+	assert.Equal(t, float64(10), rankings[0].Score)
+	assert.Equal(t, float64(6), rankings[1].Score)
+
+	require.NoError(t, crud.RemoveClanFromLeaderboard(2))
+	rankings, err = crud.GetClanLeaderboard(0, -1)
+	require.NoError(t, err)
+	assert.Len(t, rankings, 1)
+}
+
+func TestClanMembership(t *testing.T) {
+	setupPostgres(t)
+	setupRedis(t)
+
+	var users []model.User
+	for _, name := range []string{"Leader", "Officer", "Member", "Outsider"} {
+		user := model.User{Name: name, Money: 100, Level: 1}
+		require.NoError(t, service.CreateUser(&user))
+		users = append(users, user)
+	}
+	leader, officer, member, outsider := users[0].ID, users[1].ID, users[2].ID, users[3].ID
+
+	clan := model.Clan{Name: "Wolves", Tag: "WLF"}
+	require.NoError(t, service.CreateClan(&clan, leader))
+	assert.ErrorIs(t, service.CreateClan(&model.Clan{Name: "Other"}, leader), service.ErrAlreadyInClan)
+	assert.ErrorIs(t, service.CreateClan(&model.Clan{Name: "Wolves"}, outsider), service.ErrClanNameTaken)
+
+	join := func(inviter, userID uint) {
+		invitation, err := service.InviteToClan(clan.ID, inviter, userID)
+		require.NoError(t, err)
+		_, err = service.InviteToClan(clan.ID, inviter, userID)
+		assert.ErrorIs(t, err, service.ErrAlreadyInvited)
+		_, err = service.AcceptClanInvitation(invitation.ID, outsider)
+		assert.ErrorIs(t, err, service.ErrClanPermission)
+		_, err = service.AcceptClanInvitation(invitation.ID, userID)
+		require.NoError(t, err)
+		_, err = service.AcceptClanInvitation(invitation.ID, userID)
+		assert.ErrorIs(t, err, service.ErrInvitationAnswered)
+	}
+	join(leader, officer)
+	_, err := service.SetClanRole(clan.ID, leader, officer, model.OfficerRole)
+	require.NoError(t, err)
+	join(officer, member)
+
+	// Members cannot invite, officers cannot remove officers, the leader cannot leave
+	_, err = service.InviteToClan(clan.ID, member, outsider)
+	assert.ErrorIs(t, err, service.ErrClanPermission)
+	assert.ErrorIs(t, service.KickClanMember(clan.ID, officer, officer), service.ErrClanPermission)
+	assert.ErrorIs(t, service.LeaveClan(clan.ID, leader), service.ErrLeaderLeaving)
+
+	// Deposits and payouts go through the members' ledgers
+	_, err = service.DepositToClan(clan.ID, member, 40)
+	require.NoError(t, err)
+	_, err = service.PayOutFromClan(clan.ID, officer, officer, 10)
+	assert.ErrorIs(t, err, service.ErrClanPermission)
+	_, err = service.PayOutFromClan(clan.ID, leader, officer, 50)
+	assert.ErrorIs(t, err, service.ErrInsufficientTreasury)
+	stored, err := service.PayOutFromClan(clan.ID, leader, officer, 30)
+	require.NoError(t, err)
+	assert.Equal(t, 10, stored.Treasury)
+	mismatches, err := service.ReconcileWallets()
+	require.NoError(t, err)
+	assert.Empty(t, mismatches)
+
+	// Handing over the leadership makes the old leader an officer
+	stored, err = service.SetClanRole(clan.ID, leader, officer, model.LeaderRole)
+	require.NoError(t, err)
+	roles := map[uint]model.ClanRole{}
+	for _, m := range stored.Members {
+		roles[m.UserID] = m.Role
+	}
+	assert.Equal(t, map[uint]model.ClanRole{leader: model.OfficerRole, officer: model.LeaderRole, member: model.MemberRole}, roles)
+
+	require.NoError(t, service.KickClanMember(clan.ID, leader, member))
+	assert.ErrorIs(t, service.DeleteClan(clan.ID, officer), service.ErrTreasuryNotEmpty)
+}
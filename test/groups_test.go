@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+
+	"tournament-app/model"
+	"tournament-app/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitGroups(t *testing.T) {
+	groups := service.SplitGroups(makePlayers(8), 4)
+	require.Len(t, groups, 2)
+
+	// Snake seeding: 1, 4, 5, 8 and 2, 3, 6, 7
+	ids := func(group []model.User) []uint {
+		var ids []uint
+		for _, user := range group {
+			ids = append(ids, user.ID)
+		}
+		return ids
+	}
+	assert.Equal(t, []uint{1, 4, 5, 8}, ids(groups[0]))
+	assert.Equal(t, []uint{2, 3, 6, 7}, ids(groups[1]))
+
+	tests := []struct {
+		players   int
+		groupSize int
+		sizes     []int
+	}{
+		{12, 4, []int{4, 4, 4}},
+		{10, 4, []int{3, 3, 4}},
+		{9, 3, []int{3, 3, 3}},
+		{3, 2, []int{3}},
+		{5, 2, []int{3, 2}},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%d players groups of %d", tt.players, tt.groupSize), func(t *testing.T) {
+			var sizes []int
+			for _, group := range service.SplitGroups(makePlayers(tt.players), tt.groupSize) {
+				sizes = append(sizes, len(group))
+			}
+			assert.Equal(t, tt.sizes, sizes)
+		})
+	}
+}
+
+func TestGroupStageToKnockout(t *testing.T) {
+	players := makePlayers(8)
+	matches := service.GenerateGroupStage(2, players, 4)
+
+	// Two groups of four play six matches each
+	perGroup := map[int]int{}
+	for _, match := range matches {
+		perGroup[match.Group]++
+	}
+	assert.Equal(t, map[int]int{1: 6, 2: 6}, perGroup)
+
+	// No knockout before the last group match
+	playSwissRound(matches[:len(matches)-1])
+	assert.Empty(t, service.GenerateGroupKnockout(2, players, matches, 2))
+	playSwissRound(matches)
+
+	groups := service.ComputeGroupStandings(players, matches)
+	require.Len(t, groups, 2)
+	assert.Equal(t, uint(1), groups[0].Standings[0].UserID)
+	assert.Equal(t, uint(4), groups[0].Standings[1].UserID)
+	assert.Equal(t, uint(2), groups[1].Standings[0].UserID)
+	assert.Equal(t, 2, groups[1].Standings[0].Group)
+	assert.Equal(t, 1, groups[0].Standings[0].Rank)
+
+	knockout := service.GenerateGroupKnockout(2, players, matches, 2)
+	require.Len(t, knockout, 3)
+	for _, match := range knockout {
+		assert.Equal(t, model.WinnersBracket, match.Bracket)
+		assert.Zero(t, match.Group)
+	}
+
+	// Group winners are the top seeds and meet the other group's runner-up
+	assert.Equal(t, uint(1), *knockout[0].Player1ID)
+	assert.Equal(t, uint(3), *knockout[0].Player2ID)
+	assert.Equal(t, uint(2), *knockout[1].Player1ID)
+	assert.Equal(t, uint(4), *knockout[1].Player2ID)
+
+	// The knockout is built once
+	matches = append(matches, knockout...)
+	assert.Empty(t, service.GenerateGroupKnockout(2, players, matches, 2))
+
+	// Upset: player 4 wins the knockout and ranks first despite fewer group points
+	_, err := service.ApplyBracketResult(matches, "W1-1", 2, 0)
+	require.NoError(t, err)
+	_, err = service.ApplyBracketResult(matches, "W1-2", 0, 1)
+	require.NoError(t, err)
+	_, err = service.ApplyBracketResult(matches, "W2-1", 0, 3)
+	require.NoError(t, err)
+
+	ranking := service.RankGroupStage(players, matches)
+	require.Len(t, ranking, 8)
+	var order []uint
+	for _, standing := range ranking {
+		order = append(order, standing.UserID)
+	}
+	assert.Equal(t, []uint{4, 1, 2, 3, 5, 6, 8, 7}, order)
+	assert.Equal(t, 1, ranking[0].Rank)
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+
+	"tournament-app/internal/db"
+	"tournament-app/model"
+	"tournament-app/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConcurrentJoin(t *testing.T) {
+	setupPostgres(t)
+	setupRedis(t)
+
+	tournament := model.Tournament{Name: "Concurrent", Prize: 1000, MaxPlayers: 5, MinPlayers: 2, EntryFee: fee(50)}
+	require.NoError(t, service.CreateTournament(&tournament))
+	require.NoError(t, service.OpenRegistration(tournament.ID))
+
+	var users []model.User
+	for i := 0; i < 20; i++ {
+		user := model.User{Name: fmt.Sprintf("Player%d", i), Money: 100, Level: 1}
+		require.NoError(t, service.CreateUser(&user))
+		users = append(users, user)
+	}
+
+	// Every user tries to join twice at the same time
+	var wg sync.WaitGroup
+	for _, user := range users {
+		for i := 0; i < 2; i++ {
+			wg.Add(1)
+			go func(userID uint) {
+				defer wg.Done()
+				_ = service.JoinTournament(tournament.ID, userID)
+			}(user.ID)
+		}
+	}
+	wg.Wait()
+
+	joined, err := service.GetTournamentByID(tournament.ID)
+	require.NoError(t, err)
+	assert.Len(t, joined.Users, 5)
+	assert.Equal(t, model.Ongoing, joined.Status)
+
+	members := map[uint]bool{}
+	for _, user := range joined.Users {
+		members[user.ID] = true
+	}
+	assert.Len(t, members, 5)
+
+	// The join that filled the tournament created its bracket once, together with the start
+	matches, err := service.GetMatchesByTournamentID(tournament.ID)
+	require.NoError(t, err)
+	assert.Len(t, matches, len(service.GenerateSingleElimination(tournament.ID, joined.Users)))
+
+	total := 0
+	for _, user := range users {
+		stored, err := service.GetUserByID(user.ID)
+		require.NoError(t, err)
+		if members[user.ID] {
+			assert.Equal(t, 50, stored.Money, "joined user %d pays the entry fee once", user.ID)
+		} else {
+			assert.Equal(t, 100, stored.Money, "user %d is not charged without a seat", user.ID)
+		}
+		total += stored.Money
+	}
+	assert.Equal(t, 20*100-5*50, total)
+
+	// Every entry fee is in the ledger
+	mismatches, err := service.ReconcileWallets()
+	require.NoError(t, err)
+	assert.Empty(t, mismatches)
+
+	leaderboard, err := service.GetTournamentLeaderboard(tournament.ID, 0, -1)
+	require.NoError(t, err)
+	assert.Len(t, leaderboard, 5)
+}
+
+func TestFreeTournament(t *testing.T) {
+	setupPostgres(t)
+	setupRedis(t)
+
+	// An entry fee of 0 is kept, a tournament without one charges the default
+	free := model.Tournament{Name: "Free", Prize: 100, MaxPlayers: 4, MinPlayers: 2, EntryFee: fee(0)}
+	require.NoError(t, service.CreateTournament(&free))
+	assert.Equal(t, 0, free.Fee())
+	paid := model.Tournament{Name: "Paid", Prize: 100, MaxPlayers: 4, MinPlayers: 2}
+	require.NoError(t, service.CreateTournament(&paid))
+	assert.Equal(t, model.DefaultEntryFee, paid.Fee())
+
+	// An update without an entry fee keeps the tournament free
+	update := model.Tournament{ID: free.ID, Name: "Still free", Prize: 100}
+	require.NoError(t, service.UpdateTournament(&update))
+	assert.Equal(t, 0, update.Fee())
+
+	require.NoError(t, service.OpenRegistration(free.ID))
+	user := model.User{Name: "Player", Money: 0, Level: 1}
+	require.NoError(t, service.CreateUser(&user))
+	require.NoError(t, service.JoinTournament(free.ID, user.ID))
+	stored, err := service.GetUserByID(user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 0, stored.Money)
+}
+
+func TestOpeningBalanceBackfill(t *testing.T) {
+	setupPostgres(t)
+	setupRedis(t)
+
+	// A wallet from before the ledger has money but no transactions
+	legacy := model.User{Name: "Legacy", Money: 700, Level: 1}
+	require.NoError(t, db.DB.Create(&legacy).Error)
+	opened := model.User{Name: "Opened", Money: 300, Level: 1}
+	require.NoError(t, service.CreateUser(&opened))
+	mismatches, err := service.ReconcileWallets()
+	require.NoError(t, err)
+	require.Len(t, mismatches, 1)
+	assert.Equal(t, legacy.ID, mismatches[0].UserID)
+
+	// Starting up backfills its opening balance once
+	for i := 0; i < 2; i++ {
+		require.NoError(t, db.InitPostgres(os.Getenv("POSTGRES_DSN")))
+	}
+	mismatches, err = service.ReconcileWallets()
+	require.NoError(t, err)
+	assert.Empty(t, mismatches)
+
+	for _, user := range []model.User{legacy, opened} {
+		transactions, err := service.GetUserTransactions(user.ID)
+		require.NoError(t, err)
+		require.Len(t, transactions, 1)
+		assert.Equal(t, model.AdminAdjustTransaction, transactions[0].Reason)
+		assert.Equal(t, user.Money, transactions[0].Amount)
+	}
+}
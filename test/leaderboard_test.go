@@ -0,0 +1,295 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"tournament-app/internal/crud"
+	"tournament-app/model"
+	"tournament-app/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLeaderboardWindows(t *testing.T) {
+	redisServer := setupRedis(t)
+
+	// A user new to the all-time board has not changed their score yet
+	require.NoError(t, crud.UpdateLeaderboard("1", 100))
+	require.NoError(t, crud.UpdateLeaderboard("2", 300))
+	for _, window := range []model.LeaderboardWindow{model.DailyWindow, model.WeeklyWindow} {
+		leaderboard, err := crud.GetLeaderboard(window, 0, -1)
+		require.NoError(t, err)
+		require.Len(t, leaderboard, 2, window)
+		assert.Zero(t, leaderboard[0].Score)
+		assert.Zero(t, leaderboard[1].Score)
+	}
+
+	// The all-time board holds the scores, the boards of the current day and week add up their changes
+	require.NoError(t, crud.UpdateLeaderboard("1", 250))
+	require.NoError(t, crud.UpdateLeaderboard("1", 400))
+	require.NoError(t, crud.UpdateLeaderboard("2", 280))
+	leaderboard, err := crud.GetLeaderboard(model.AllTimeWindow, 0, -1)
+	require.NoError(t, err)
+	assert.Equal(t, []uint{1, 2}, []uint{leaderboard[0].UserID, leaderboard[1].UserID})
+	assert.Equal(t, []float64{400, 280}, []float64{leaderboard[0].Score, leaderboard[1].Score})
+	for _, window := range []model.LeaderboardWindow{model.DailyWindow, model.WeeklyWindow} {
+		leaderboard, err := crud.GetLeaderboard(window, 0, -1)
+		require.NoError(t, err)
+		require.Len(t, leaderboard, 2, window)
+		assert.Equal(t, []uint{1, 2}, []uint{leaderboard[0].UserID, leaderboard[1].UserID}, window)
+		assert.Equal(t, []float64{300, -20}, []float64{leaderboard[0].Score, leaderboard[1].Score}, window)
+	}
+
+	// Only the window boards expire, the daily one within a day and the weekly one within a week
+	var daily, weekly string
+	for _, key := range redisServer.Keys() {
+		switch {
+		case strings.Contains(key, ":daily:"):
+			daily = key
+		case strings.Contains(key, ":weekly:"):
+			weekly = key
+		default:
+			assert.Zero(t, redisServer.TTL(key), key)
+		}
+	}
+	require.NotEmpty(t, daily)
+	require.NotEmpty(t, weekly)
+	assert.Greater(t, redisServer.TTL(daily), time.Duration(0))
+	assert.LessOrEqual(t, redisServer.TTL(daily), 24*time.Hour)
+	assert.GreaterOrEqual(t, redisServer.TTL(weekly), redisServer.TTL(daily)-time.Second)
+	assert.LessOrEqual(t, redisServer.TTL(weekly), 7*24*time.Hour)
+
+	redisServer.FastForward(redisServer.TTL(weekly) + time.Second)
+	for _, window := range []model.LeaderboardWindow{model.DailyWindow, model.WeeklyWindow} {
+		leaderboard, err := crud.GetLeaderboard(window, 0, -1)
+		require.NoError(t, err)
+		assert.Empty(t, leaderboard, window)
+	}
+	leaderboard, err = crud.GetLeaderboard(model.AllTimeWindow, 0, -1)
+	require.NoError(t, err)
+	assert.Len(t, leaderboard, 2)
+}
+
+func TestUnknownLeaderboardWindow(t *testing.T) {
+	_, err := service.GetActiveLeaderboard("monthly", 0, 10)
+	assert.ErrorIs(t, err, service.ErrInvalidWindow)
+}
+
+func TestLeaderboardAround(t *testing.T) {
+	setupRedis(t)
+
+	// Users 1 to 7 start at 0 and score 100 to 700, user 7 leads every board
+	for userID := 1; userID <= 7; userID++ {
+		require.NoError(t, crud.UpdateLeaderboard(fmt.Sprintf("%d", userID), 0))
+		require.NoError(t, crud.UpdateLeaderboard(fmt.Sprintf("%d", userID), float64(userID*100)))
+	}
+
+	entries, found, err := crud.GetLeaderboardAround(0, model.AllTimeWindow, 4, 0)
+	require.NoError(t, err)
+	require.True(t, found)
+	assert.Equal(t, []model.LeaderboardEntry{{Rank: 4, UserID: 4, Score: 400}}, entries)
+
+	entries, found, err = crud.GetLeaderboardAround(0, model.WeeklyWindow, 4, 2)
+	require.NoError(t, err)
+	require.True(t, found)
+	var ranks []int
+	var users []uint
+	for _, entry := range entries {
+		ranks = append(ranks, entry.Rank)
+		users = append(users, entry.UserID)
+	}
+	assert.Equal(t, []int{2, 3, 4, 5, 6}, ranks)
+	assert.Equal(t, []uint{6, 5, 4, 3, 2}, users)
+
+	// The range is cut off at the top and the bottom of the board
+	entries, _, err = crud.GetLeaderboardAround(0, model.DailyWindow, 7, 3)
+	require.NoError(t, err)
+	assert.Len(t, entries, 4)
+	assert.Equal(t, 1, entries[0].Rank)
+	entries, _, err = crud.GetLeaderboardAround(0, model.AllTimeWindow, 1, 3)
+	require.NoError(t, err)
+	assert.Len(t, entries, 4)
+	assert.Equal(t, 7, entries[3].Rank)
+
+	// Tournament boards are looked up by the tournament, users missing from a board are not found
+	require.NoError(t, crud.UpdateTournamentLeaderboard(5, "2", 3))
+	require.NoError(t, crud.UpdateTournamentLeaderboard(5, "9", 6))
+	entries, found, err = crud.GetLeaderboardAround(5, "", 2, 1)
+	require.NoError(t, err)
+	require.True(t, found)
+	assert.Equal(t, []model.LeaderboardEntry{{Rank: 1, UserID: 9, Score: 6}, {Rank: 2, UserID: 2, Score: 3}}, entries)
+	_, found, err = crud.GetLeaderboardAround(5, "", 4, 1)
+	require.NoError(t, err)
+	assert.False(t, found)
+}
+
+// seedLeaderboard creates users with a picture and puts them on the global leaderboard by their score
+func seedLeaderboard(t testing.TB, n int) []model.User {
+	t.Helper()
+	users := make([]model.User, 0, n)
+	for i := 0; i < n; i++ {
+		user := model.User{Name: fmt.Sprintf("Player%d", i), Money: 100 + i, Level: 1 + i%10, Avatar: fmt.Sprintf("https://example.com/avatars/%d.png", i)}
+		require.NoError(t, service.CreateUser(&user))
+		require.NoError(t, crud.UpdateLeaderboard(fmt.Sprintf("%d", user.ID), user.Score))
+		users = append(users, user)
+	}
+	return users
+}
+
+func TestLeaderboardProfiles(t *testing.T) {
+	setupPostgres(t)
+	setupRedis(t)
+	users := seedLeaderboard(t, 3)
+
+	leaderboard, err := service.GetLeaderboard(model.AllTimeWindow, 1, 2)
+	require.NoError(t, err)
+	require.Len(t, leaderboard, 2)
+	// Player1 has the second best score, it keeps its rank on a page that does not start at the top
+	assert.Equal(t, model.LeaderboardEntry{
+		Rank:   2,
+		UserID: users[1].ID,
+		Name:   users[1].Name,
+		Level:  users[1].Level,
+		Avatar: users[1].Avatar,
+		Score:  users[1].Score,
+	}, leaderboard[0])
+	assert.Equal(t, 3, leaderboard[1].Rank)
+	assert.Equal(t, users[0].Name, leaderboard[1].Name)
+
+	entry, err := service.GetLeaderboardRank(users[2].ID, 0, "")
+	require.NoError(t, err)
+	assert.Equal(t, 1, entry.Rank)
+	assert.Equal(t, users[2].Avatar, entry.Avatar)
+}
+
+// BenchmarkLeaderboardProfiles compares reading the profiles of a leaderboard page in a single query
+// with looking up every user on it one by one
+func BenchmarkLeaderboardProfiles(b *testing.B) {
+	setupPostgres(b)
+	setupRedis(b)
+	seedLeaderboard(b, 100)
+
+	b.Run("batched", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := service.GetLeaderboard(model.AllTimeWindow, 0, 99); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("n+1", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			leaderboard, err := crud.GetLeaderboard(model.AllTimeWindow, 0, 99)
+			if err != nil {
+				b.Fatal(err)
+			}
+			for _, entry := range leaderboard {
+				if _, err := crud.GetUserByID(entry.UserID); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+}
+
+func TestReplaceBoard(t *testing.T) {
+	setupRedis(t)
+
+	require.NoError(t, crud.UpdateTournamentLeaderboard(4, "1", 3))
+	require.NoError(t, crud.UpdateTournamentLeaderboard(4, "7", 9))
+
+	// Members missing from the new scores are dropped, the others are overwritten
+	require.NoError(t, crud.ReplaceBoard(model.TournamentBoard, 4, map[uint]float64{1: 6, 2: 0}))
+	scores, err := crud.GetBoardScores(model.TournamentBoard, 4)
+	require.NoError(t, err)
+	assert.Equal(t, map[uint]float64{1: 6, 2: 0}, scores)
+
+	// An empty board is removed
+	require.NoError(t, crud.ReplaceBoard(model.TournamentBoard, 4, nil))
+	scores, err = crud.GetBoardScores(model.TournamentBoard, 4)
+	require.NoError(t, err)
+	assert.Empty(t, scores)
+}
+
+func TestReconcileLeaderboards(t *testing.T) {
+	setupPostgres(t)
+	setupRedis(t)
+	users := seedLeaderboard(t, 3)
+
+	// Redis drifts away from Postgres: a score is wrong, a user is missing and a deleted user is left over
+	require.NoError(t, crud.UpdateLeaderboard(fmt.Sprintf("%d", users[0].ID), 1))
+	require.NoError(t, crud.RemoveFromRatingRanking(users[1].ID))
+	require.NoError(t, crud.UpdateLeaderboard("999", 50))
+
+	report, err := service.ReconcileLeaderboards(true)
+	require.NoError(t, err)
+	assert.True(t, report.DryRun)
+	assert.Zero(t, report.Rebuilt)
+	require.NotEmpty(t, report.Mismatches)
+
+	// A dry run leaves the boards as they were
+	scores, err := crud.GetBoardScores(model.GlobalBoard, 0)
+	require.NoError(t, err)
+	assert.Equal(t, float64(1), scores[users[0].ID])
+	assert.Contains(t, scores, uint(999))
+
+	report, err = service.ReconcileLeaderboards(false)
+	require.NoError(t, err)
+	assert.Equal(t, 2, report.Rebuilt)
+
+	scores, err = crud.GetBoardScores(model.GlobalBoard, 0)
+	require.NoError(t, err)
+	assert.Len(t, scores, 3)
+	assert.Equal(t, model.CalculateScore(&users[0]), scores[users[0].ID])
+	ratings, err := crud.GetBoardScores(model.RatingBoard, 0)
+	require.NoError(t, err)
+	assert.Equal(t, users[1].Rating, ratings[users[1].ID])
+
+	// Once rebuilt there is nothing left to report
+	report, err = service.ReconcileLeaderboards(true)
+	require.NoError(t, err)
+	assert.Empty(t, report.Mismatches)
+}
+
+func TestLeaderboardStatus(t *testing.T) {
+	setupRedis(t)
+
+	// A tournament's board becomes active with its first entry and stays active while points come in
+	require.NoError(t, crud.UpdateTournamentLeaderboard(3, "1", 0))
+	require.NoError(t, crud.IncrementTournamentLeaderboard(3, "1", 3))
+	require.NoError(t, crud.UpdateTournamentLeaderboard(8, "2", 0))
+	status, err := crud.GetLeaderboardStatus(3)
+	require.NoError(t, err)
+	assert.Equal(t, model.Active, status)
+
+	leaderboard, err := crud.GetTournamentLeaderboard(3, 0, -1)
+	require.NoError(t, err)
+	assert.Equal(t, []model.Leaderboard{{UserID: 1, TournamentID: 3, Score: 3, Status: model.Active}}, leaderboard)
+
+	// The global boards are always live
+	require.NoError(t, crud.UpdateLeaderboard("1", 100))
+	active, err := service.GetActiveLeaderboard("", 0, -1)
+	require.NoError(t, err)
+	require.Len(t, active, 1)
+	assert.Equal(t, model.Active, active[0].Status)
+
+	// Archiving drops the live ranking and keeps the board passive, late points do not revive it
+	require.NoError(t, crud.ArchiveTournamentLeaderboard(3))
+	require.NoError(t, crud.IncrementTournamentLeaderboard(3, "1", 1))
+	statuses, err := crud.GetLeaderboardStatuses()
+	require.NoError(t, err)
+	assert.Equal(t, map[uint]model.LeaderboardStatus{3: model.Passive, 8: model.Active}, statuses)
+	leaderboard, err = crud.GetTournamentLeaderboard(3, 0, -1)
+	require.NoError(t, err)
+	require.Len(t, leaderboard, 1)
+	assert.Equal(t, model.Passive, leaderboard[0].Status)
+
+	// A removed board has no status
+	require.NoError(t, crud.RemoveLeaderboardFromRedis(8))
+	status, err = crud.GetLeaderboardStatus(8)
+	require.NoError(t, err)
+	assert.Empty(t, status)
+}
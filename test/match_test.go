@@ -0,0 +1,120 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+
+	"tournament-app/model"
+	"tournament-app/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatchResultsRankTournament(t *testing.T) {
+	setupPostgres(t)
+	setupRedis(t)
+
+	tournament := model.Tournament{
+		Name: "Matches", Prize: 300, MaxPlayers: 3, MinPlayers: 3, EntryFee: fee(10), Format: model.RoundRobin,
+		PrizeStrategy: model.PrizeStrategyConfig{Type: model.FixedPrize, Amounts: []int{200, 100}},
+	}
+	require.NoError(t, service.CreateTournament(&tournament))
+	require.NoError(t, service.OpenRegistration(tournament.ID))
+
+	var users []model.User
+	for i := 0; i < 3; i++ {
+		// The richest player must not win by money alone
+		user := model.User{Name: fmt.Sprintf("Player%d", i), Money: 1000 * (3 - i), Level: 1}
+		require.NoError(t, service.CreateUser(&user))
+		require.NoError(t, service.JoinTournament(tournament.ID, user.ID))
+		users = append(users, user)
+	}
+
+	// The full tournament started with its round robin schedule, results are reported on those matches
+	matches, err := service.GetMatchesByTournamentID(tournament.ID)
+	require.NoError(t, err)
+	play := func(player1, player2 model.User, score1, score2 int) {
+		for _, match := range matches {
+			if !match.HasPlayer(player1.ID) || !match.HasPlayer(player2.ID) {
+				continue
+			}
+			if *match.Player1ID == player2.ID {
+				score1, score2 = score2, score1
+			}
+			_, err := service.ReportMatchResult(tournament.ID, match.ID, score1, score2)
+			require.NoError(t, err)
+			_, err = service.ReportMatchResult(tournament.ID, match.ID, score1, score2)
+			assert.ErrorIs(t, err, service.ErrMatchCompleted)
+			return
+		}
+		t.Fatalf("no match between users %d and %d", player1.ID, player2.ID)
+	}
+	play(users[2], users[0], 3, 1)
+	play(users[2], users[1], 2, 0)
+	play(users[1], users[0], 1, 1)
+
+	leaderboard, err := service.GetTournamentLeaderboard(tournament.ID, 0, -1)
+	require.NoError(t, err)
+	require.Len(t, leaderboard, 3)
+	assert.Equal(t, users[2].ID, leaderboard[0].UserID)
+	assert.Equal(t, float64(6), leaderboard[0].Score)
+
+	require.NoError(t, service.EndTournament(tournament.ID))
+	_, err = service.ReportMatchResult(tournament.ID, matches[0].ID, 1, 0)
+	assert.ErrorIs(t, err, service.ErrInvalidTransition, "a finished tournament takes no more results")
+	winner, err := service.GetUserByID(users[2].ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1000-10+200, winner.Money)
+
+	// Two wins raise the winner's rating, every reported match is in the rating history
+	assert.Greater(t, winner.Rating, model.DefaultSkillRating.Rating)
+	history, err := service.GetRatingHistory(winner.ID)
+	require.NoError(t, err)
+	assert.Len(t, history, 2)
+	rankings, err := service.GetRankings(0, 0)
+	require.NoError(t, err)
+	assert.Equal(t, winner.ID, rankings[0].UserID)
+}
+
+func TestByeChampionTakesFirstPrize(t *testing.T) {
+	setupPostgres(t)
+	setupRedis(t)
+
+	tournament := model.Tournament{
+		Name: "Bye", Prize: 300, MaxPlayers: 3, MinPlayers: 3, EntryFee: fee(0), Format: model.SingleElimination,
+		Seeding:       model.SeedByLevel,
+		PrizeStrategy: model.PrizeStrategyConfig{Type: model.FixedPrize, Amounts: []int{200, 100}},
+	}
+	require.NoError(t, service.CreateTournament(&tournament))
+	require.NoError(t, service.OpenRegistration(tournament.ID))
+
+	// The first user is the top seed and gets the bye, the full tournament starts on the third join
+	var users []model.User
+	for i := 0; i < 3; i++ {
+		user := model.User{Name: fmt.Sprintf("Seed%d", i+1), Money: 100, Level: 3 - i}
+		require.NoError(t, service.CreateUser(&user))
+		require.NoError(t, service.JoinTournament(tournament.ID, user.ID))
+		users = append(users, user)
+	}
+
+	matches, err := service.GetMatchesByTournamentID(tournament.ID)
+	require.NoError(t, err)
+	codes := map[string]model.Match{}
+	for _, match := range matches {
+		codes[match.Code] = match
+	}
+	_, err = service.ReportMatchResult(tournament.ID, codes["W1-2"].ID, 2, 1)
+	require.NoError(t, err)
+	final, err := service.ReportMatchResult(tournament.ID, codes["W2-1"].ID, 3, 0)
+	require.NoError(t, err)
+	require.Equal(t, users[0].ID, *final.WinnerID)
+
+	// The champion won a single match, as many as the finalist, and still takes the first prize
+	require.NoError(t, service.EndTournament(tournament.ID))
+	for i, want := range []int{300, 200, 100} {
+		stored, err := service.GetUserByID(users[i].ID)
+		require.NoError(t, err)
+		assert.Equal(t, want, stored.Money, "seed %d", i+1)
+	}
+}
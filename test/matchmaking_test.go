@@ -0,0 +1,241 @@
+package main
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"tournament-app/internal/crud"
+	"tournament-app/model"
+	"tournament-app/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClock is a clock that only moves when the test advances it
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+var testMatchmakingConfig = service.MatchmakingConfig{
+	BaseWindow:     100,
+	WidenPerSecond: 10,
+	MaxWindow:      300,
+	Interval:       time.Millisecond,
+}
+
+func TestRatingWindow(t *testing.T) {
+	assert.Equal(t, 100.0, testMatchmakingConfig.RatingWindow(0))
+	assert.Equal(t, 150.0, testMatchmakingConfig.RatingWindow(5*time.Second))
+	assert.Equal(t, 300.0, testMatchmakingConfig.RatingWindow(time.Hour))
+	assert.Equal(t, 100.0, testMatchmakingConfig.RatingWindow(-time.Second))
+}
+
+func TestPairQueue(t *testing.T) {
+	start := newFakeClock().Now()
+	queue := []model.QueueEntry{
+		{UserID: 1, Rating: 1500, JoinedAt: start},
+		{UserID: 2, Rating: 1650, JoinedAt: start.Add(time.Second)},
+		{UserID: 3, Rating: 1580, JoinedAt: start.Add(2 * time.Second)},
+		{UserID: 4, Rating: 2100, JoinedAt: start},
+	}
+
+	// Right away only players within 100 of each other are matched, the closest one first
+	pairs := service.PairQueue(queue, start.Add(2*time.Second), testMatchmakingConfig)
+	require.Len(t, pairs, 1)
+	assert.Equal(t, uint(1), pairs[0][0].UserID)
+	assert.Equal(t, uint(3), pairs[0][1].UserID)
+
+	// Without player 3, player 1 waits until their window reaches 150
+	queue = append(queue[:2], queue[3])
+	assert.Empty(t, service.PairQueue(queue, start.Add(4*time.Second), testMatchmakingConfig))
+	pairs = service.PairQueue(queue, start.Add(5*time.Second), testMatchmakingConfig)
+	require.Len(t, pairs, 1)
+	assert.Equal(t, [2]uint{1, 2}, [2]uint{pairs[0][0].UserID, pairs[0][1].UserID})
+
+	// The window stops growing at the maximum
+	assert.Len(t, service.PairQueue(queue, start.Add(time.Hour), testMatchmakingConfig), 1)
+}
+
+// newTestMatchmaker returns a matchmaker on a fake clock that keeps the matches it makes in memory
+func newTestMatchmaker(clock service.Clock) (*service.Matchmaker, *[]model.Match) {
+	var mu sync.Mutex
+	created := &[]model.Match{}
+	matchmaker := service.NewMatchmaker(clock, testMatchmakingConfig)
+	matchmaker.CreateMatch = func(match *model.Match) error {
+		mu.Lock()
+		defer mu.Unlock()
+		match.ID = uint(len(*created) + 1)
+		*created = append(*created, *match)
+		return nil
+	}
+	return matchmaker, created
+}
+
+func TestMatchmakerRunOnce(t *testing.T) {
+	setupRedis(t)
+	clock := newFakeClock()
+	matchmaker, created := newTestMatchmaker(clock)
+
+	for _, entry := range []model.QueueEntry{
+		{UserID: 1, Rating: 1500},
+		{UserID: 2, Rating: 1560},
+		{UserID: 3, Rating: 1800},
+		{UserID: 4, Rating: 1990},
+	} {
+		entry.JoinedAt = clock.Now()
+		added, err := crud.EnqueueMatchmaking(entry)
+		require.NoError(t, err)
+		require.True(t, added)
+	}
+	added, err := crud.EnqueueMatchmaking(model.QueueEntry{UserID: 1, Rating: 1500, JoinedAt: clock.Now()})
+	require.NoError(t, err)
+	assert.False(t, added, "a user is queued once")
+
+	matches, err := matchmaker.RunOnce()
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+	assert.Equal(t, uint(1), *matches[0].Player1ID)
+	assert.Equal(t, uint(2), *matches[0].Player2ID)
+	assert.Zero(t, matches[0].TournamentID)
+	assert.Equal(t, model.MatchPending, matches[0].Status)
+
+	// Players 3 and 4 are 190 apart, which both accept after 9 seconds
+	clock.Advance(8 * time.Second)
+	matches, err = matchmaker.RunOnce()
+	require.NoError(t, err)
+	assert.Empty(t, matches)
+
+	clock.Advance(time.Second)
+	matches, err = matchmaker.RunOnce()
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+	assert.Equal(t, uint(3), *matches[0].Player1ID)
+	assert.Len(t, *created, 2)
+
+	queue, err := crud.GetMatchmakingQueue(clock.Now())
+	require.NoError(t, err)
+	assert.Empty(t, queue)
+}
+
+func TestMatchmakerRequeuesOnFailure(t *testing.T) {
+	setupRedis(t)
+	clock := newFakeClock()
+	matchmaker := service.NewMatchmaker(clock, testMatchmakingConfig)
+	matchmaker.CreateMatch = func(*model.Match) error { return errors.New("database down") }
+
+	for _, id := range []uint{1, 2} {
+		_, err := crud.EnqueueMatchmaking(model.QueueEntry{UserID: id, Rating: 1500, JoinedAt: clock.Now()})
+		require.NoError(t, err)
+	}
+
+	_, err := matchmaker.RunOnce()
+	assert.Error(t, err)
+
+	// Both players keep their place and their waiting time
+	queue, err := crud.GetMatchmakingQueue(clock.Now().Add(time.Minute))
+	require.NoError(t, err)
+	require.Len(t, queue, 2)
+	assert.True(t, queue[0].JoinedAt.Equal(clock.Now()))
+}
+
+func TestMatchmakerDequeue(t *testing.T) {
+	setupRedis(t)
+	clock := newFakeClock()
+	matchmaker, created := newTestMatchmaker(clock)
+
+	for _, id := range []uint{1, 2} {
+		_, err := crud.EnqueueMatchmaking(model.QueueEntry{UserID: id, Rating: 1500, JoinedAt: clock.Now()})
+		require.NoError(t, err)
+	}
+	require.NoError(t, matchmaker.Dequeue(2))
+	assert.ErrorIs(t, matchmaker.Dequeue(2), service.ErrNotQueued)
+
+	matches, err := matchmaker.RunOnce()
+	require.NoError(t, err)
+	assert.Empty(t, matches)
+	assert.Empty(t, *created)
+}
+
+func TestMatchmakerStartStop(t *testing.T) {
+	setupRedis(t)
+	clock := newFakeClock()
+	matchmaker, _ := newTestMatchmaker(clock)
+
+	for _, id := range []uint{1, 2} {
+		_, err := crud.EnqueueMatchmaking(model.QueueEntry{UserID: id, Rating: 1500, JoinedAt: clock.Now()})
+		require.NoError(t, err)
+	}
+
+	matchmaker.Start()
+	matchmaker.Start() // starting twice keeps a single worker
+	assert.Eventually(t, func() bool {
+		queue, err := crud.GetMatchmakingQueue(clock.Now())
+		return err == nil && len(queue) == 0
+	}, time.Second, time.Millisecond)
+
+	// Stop waits for the worker, nothing is matched afterwards
+	matchmaker.Stop()
+	matchmaker.Stop()
+	for _, id := range []uint{3, 4} {
+		_, err := crud.EnqueueMatchmaking(model.QueueEntry{UserID: id, Rating: 1500, JoinedAt: clock.Now()})
+		require.NoError(t, err)
+	}
+	time.Sleep(10 * testMatchmakingConfig.Interval)
+	queue, err := crud.GetMatchmakingQueue(clock.Now())
+	require.NoError(t, err)
+	assert.Len(t, queue, 2)
+}
+
+func TestMatchmakingQueueUsers(t *testing.T) {
+	setupPostgres(t)
+	setupRedis(t)
+	matchmaker := service.NewMatchmaker(newFakeClock(), testMatchmakingConfig)
+
+	var users []model.User
+	for i := 0; i < 2; i++ {
+		user := model.User{Name: "Queued", Money: 100, Level: 1}
+		require.NoError(t, service.CreateUser(&user))
+		require.NoError(t, matchmaker.Enqueue(user.ID))
+		users = append(users, user)
+	}
+	assert.ErrorIs(t, matchmaker.Enqueue(users[0].ID), service.ErrAlreadyQueued)
+
+	matches, err := matchmaker.RunOnce()
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+
+	// Both players find the match to report
+	for _, user := range users {
+		found, err := service.GetMatchmakingMatches(user.ID)
+		require.NoError(t, err)
+		require.Len(t, found, 1)
+		assert.Equal(t, matches[0].ID, found[0].ID)
+	}
+
+	// Reporting the match rates both players
+	_, err = service.ReportMatchmakingResult(matches[0].ID, 2, 0)
+	require.NoError(t, err)
+	winner, err := service.GetUserByID(*matches[0].Player1ID)
+	require.NoError(t, err)
+	assert.Greater(t, winner.Rating, model.DefaultSkillRating.Rating)
+}
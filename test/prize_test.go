@@ -0,0 +1,52 @@
+package main
+
+import (
+	"testing"
+
+	"tournament-app/model"
+	"tournament-app/service"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrizeStrategies(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  model.PrizeStrategyConfig
+		pool    int
+		players int
+		want    []int
+	}{
+		{"default table", model.PrizeStrategyConfig{}, 1000, 10, []int{500, 250, 125, 62}},
+		{"default table few players", model.PrizeStrategyConfig{}, 1000, 2, []int{500, 250}},
+		{"percentage", model.PrizeStrategyConfig{Type: model.PercentagePrize, Percentages: []float64{60, 40}}, 1000, 5, []int{600, 400}},
+		{"percentage over pool", model.PrizeStrategyConfig{Type: model.PercentagePrize, Percentages: []float64{80, 80}}, 1000, 5, []int{800, 200}},
+		{"fixed", model.PrizeStrategyConfig{Type: model.FixedPrize, Amounts: []int{200, 100, 50}}, 1000, 10, []int{200, 100, 50}},
+		{"fixed over pool", model.PrizeStrategyConfig{Type: model.FixedPrize, Amounts: []int{200, 100, 50}}, 250, 10, []int{200, 50, 0}},
+		{"winner takes all", model.PrizeStrategyConfig{Type: model.WinnerTakesAllPrize}, 1000, 10, []int{1000}},
+		{"winner takes all without players", model.PrizeStrategyConfig{Type: model.WinnerTakesAllPrize}, 1000, 0, nil},
+		{"top n split", model.PrizeStrategyConfig{Type: model.TopNSplitPrize, TopN: 3}, 1000, 10, []int{333, 333, 333}},
+		{"top n split few players", model.PrizeStrategyConfig{Type: model.TopNSplitPrize, TopN: 3}, 1000, 2, []int{500, 500}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			strategy, err := service.NewPrizeStrategy(tt.config)
+			assert.NoError(t, err)
+
+			payouts := strategy.Payouts(tt.pool, tt.players)
+			assert.Equal(t, tt.want, payouts)
+
+			total := 0
+			for _, prize := range payouts {
+				total += prize
+			}
+			assert.LessOrEqual(t, total, tt.pool)
+		})
+	}
+}
+
+func TestUnknownPrizeStrategy(t *testing.T) {
+	_, err := service.NewPrizeStrategy(model.PrizeStrategyConfig{Type: "lottery"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"testing"
+
+	"tournament-app/internal/crud"
+	"tournament-app/model"
+	"tournament-app/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestGlicko2Period checks the worked example of Glickman's Glicko-2 paper
+func TestGlicko2Period(t *testing.T) {
+	player := model.SkillRating{Rating: 1500, RatingDeviation: 200, Volatility: 0.06}
+	opponents := []model.SkillRating{
+		{Rating: 1400, RatingDeviation: 30, Volatility: 0.06},
+		{Rating: 1550, RatingDeviation: 100, Volatility: 0.06},
+		{Rating: 1700, RatingDeviation: 300, Volatility: 0.06},
+	}
+
+	rated := service.Glicko2{Tau: 0.5}.Period(player, opponents, []float64{1, 0, 0})
+	assert.InDelta(t, 1464.06, rated.Rating, 0.01)
+	assert.InDelta(t, 151.52, rated.RatingDeviation, 0.01)
+	assert.InDelta(t, 0.05999, rated.Volatility, 0.00001)
+
+	// Without games only the deviation grows
+	idle := service.Glicko2{Tau: 0.5}.Period(player, nil, nil)
+	assert.Equal(t, player.Rating, idle.Rating)
+	assert.Greater(t, idle.RatingDeviation, player.RatingDeviation)
+}
+
+func TestRatingSystems(t *testing.T) {
+	systems := map[string]service.Rating{
+		"glicko2": service.Glicko2{Tau: 0.5},
+		"elo":     service.Elo{K: 32},
+	}
+
+	for name, system := range systems {
+		t.Run(name, func(t *testing.T) {
+			strong := model.SkillRating{Rating: 1700, RatingDeviation: 80, Volatility: 0.06}
+			weak := model.SkillRating{Rating: 1400, RatingDeviation: 80, Volatility: 0.06}
+
+			// The winner gains what the loser drops, an upset moves the ratings further
+			won, lost := system.Update(strong, weak, 1)
+			assert.Greater(t, won.Rating, strong.Rating)
+			assert.Less(t, lost.Rating, weak.Rating)
+
+			upsetLost, upsetWon := system.Update(strong, weak, 0)
+			assert.Greater(t, strong.Rating-upsetLost.Rating, won.Rating-strong.Rating)
+			assert.Greater(t, upsetWon.Rating, weak.Rating)
+
+			// A draw between equals leaves the ratings where they were
+			drew1, drew2 := system.Update(model.DefaultSkillRating, model.DefaultSkillRating, 0.5)
+			assert.InDelta(t, model.DefaultSkillRating.Rating, drew1.Rating, 0.0001)
+			assert.InDelta(t, model.DefaultSkillRating.Rating, drew2.Rating, 0.0001)
+		})
+	}
+
+	// Elo ratings are zero-sum
+	won, lost := service.Elo{K: 32}.Update(model.DefaultSkillRating, model.DefaultSkillRating, 1)
+	assert.Equal(t, 1516.0, won.Rating)
+	assert.Equal(t, 1484.0, lost.Rating)
+
+	// Glicko-2 gets more certain with every game
+	won, _ = service.Glicko2{Tau: 0.5}.Update(model.DefaultSkillRating, model.DefaultSkillRating, 1)
+	assert.Less(t, won.RatingDeviation, model.DefaultSkillRating.RatingDeviation)
+}
+
+func TestRatingRanking(t *testing.T) {
+	setupRedis(t)
+
+	require.NoError(t, crud.UpdateRatingRanking(1, 1500))
+	require.NoError(t, crud.UpdateRatingRanking(2, 1720.5))
+	require.NoError(t, crud.UpdateRatingRanking(3, 1610))
+
+	ranking, err := service.GetRankings(0, -1)
+	require.NoError(t, err)
+	assert.Equal(t, []model.Ranking{
+		{Rank: 1, UserID: 2, Rating: 1720.5},
+		{Rank: 2, UserID: 3, Rating: 1610},
+		{Rank: 3, UserID: 1, Rating: 1500},
+	}, ranking)
+
+	// A page keeps the overall ranks
+	page, err := service.GetRankings(1, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []model.Ranking{{Rank: 2, UserID: 3, Rating: 1610}}, page)
+
+	require.NoError(t, crud.RemoveFromRatingRanking(2))
+	ranking, err = service.GetRankings(0, -1)
+	require.NoError(t, err)
+	assert.Len(t, ranking, 2)
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+
+	"tournament-app/model"
+	"tournament-app/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLeaveAndCancelRefunds(t *testing.T) {
+	setupPostgres(t)
+	setupRedis(t)
+
+	tournament := model.Tournament{Name: "Refunds", Prize: 1000, MaxPlayers: 4, MinPlayers: 2, EntryFee: fee(50)}
+	require.NoError(t, service.CreateTournament(&tournament))
+	require.NoError(t, service.OpenRegistration(tournament.ID))
+
+	var users []model.User
+	for i := 0; i < 3; i++ {
+		user := model.User{Name: fmt.Sprintf("Player%d", i), Money: 100, Level: 1}
+		require.NoError(t, service.CreateUser(&user))
+		require.NoError(t, service.JoinTournament(tournament.ID, user.ID))
+		users = append(users, user)
+	}
+
+	// Leaving before the start refunds the whole entry fee
+	require.NoError(t, service.LeaveTournament(tournament.ID, users[0].ID))
+	assert.ErrorIs(t, service.LeaveTournament(tournament.ID, users[0].ID), service.ErrNotJoined)
+	left, err := service.GetUserByID(users[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, 100, left.Money)
+	assert.Equal(t, []string{"admin_adjust 100", "entry_fee -50", "refund 50"}, ledger(t, users[0].ID))
+
+	// Nobody leaves once the matches are drawn, cancelling an ongoing tournament refunds nothing under the default policy
+	require.NoError(t, service.StartTournament(tournament.ID))
+	assert.ErrorIs(t, service.LeaveTournament(tournament.ID, users[1].ID), service.ErrInvalidTransition)
+	require.NoError(t, service.CancelTournament(tournament.ID))
+	for _, user := range users[1:] {
+		stored, err := service.GetUserByID(user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 50, stored.Money)
+		assert.Equal(t, []string{"admin_adjust 100", "entry_fee -50"}, ledger(t, user.ID), "no refund is recorded")
+	}
+
+	cancelled, err := service.GetTournamentByID(tournament.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.Cancelled, cancelled.Status)
+	assert.Len(t, cancelled.Users, 2)
+
+	mismatches, err := service.ReconcileWallets()
+	require.NoError(t, err)
+	assert.Empty(t, mismatches)
+}
+
+func TestCancelWithFullRefund(t *testing.T) {
+	setupPostgres(t)
+	setupRedis(t)
+
+	tournament := model.Tournament{Name: "Full refund", Prize: 1000, MaxPlayers: 4, MinPlayers: 1, EntryFee: fee(30), RefundPolicy: model.FullRefund}
+	require.NoError(t, service.CreateTournament(&tournament))
+	require.NoError(t, service.OpenRegistration(tournament.ID))
+
+	user := model.User{Name: "Player", Money: 100, Level: 1}
+	require.NoError(t, service.CreateUser(&user))
+	require.NoError(t, service.JoinTournament(tournament.ID, user.ID))
+	require.NoError(t, service.StartTournament(tournament.ID))
+	require.NoError(t, service.CancelTournament(tournament.ID))
+
+	stored, err := service.GetUserByID(user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 100, stored.Money)
+
+	assert.Equal(t, []string{"admin_adjust 100", "entry_fee -30", "refund 30"}, ledger(t, user.ID))
+}
+
+// ledger returns the reason and amount of every transaction of the user, oldest first
+func ledger(t *testing.T, userID uint) []string {
+	t.Helper()
+	transactions, err := service.GetUserTransactions(userID)
+	require.NoError(t, err)
+	entries := make([]string, 0, len(transactions))
+	for _, transaction := range transactions {
+		entries = append(entries, fmt.Sprintf("%s %d", transaction.Reason, transaction.Amount))
+	}
+	return entries
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+
+	"tournament-app/internal/crud"
+	"tournament-app/model"
+	"tournament-app/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateRoundRobin(t *testing.T) {
+	for players := 2; players <= 11; players++ {
+		t.Run(fmt.Sprintf("%d players", players), func(t *testing.T) {
+			matches := service.GenerateRoundRobin(4, makePlayers(players))
+
+			rounds := players
+			if players%2 == 1 {
+				rounds = players + 1
+			}
+			rounds--
+
+			pairs := map[[2]uint]int{}
+			perRound := map[int]map[uint]int{}
+			byes := map[uint]int{}
+			for _, match := range matches {
+				assert.Equal(t, uint(4), match.TournamentID)
+				assert.GreaterOrEqual(t, match.Round, 1)
+				assert.LessOrEqual(t, match.Round, rounds)
+				if perRound[match.Round] == nil {
+					perRound[match.Round] = map[uint]int{}
+				}
+
+				if match.Player2ID == nil {
+					assert.Equal(t, model.MatchSkipped, match.Status)
+					byes[*match.Player1ID]++
+					perRound[match.Round][*match.Player1ID]++
+					continue
+				}
+				assert.Equal(t, model.MatchPending, match.Status)
+				a, b := *match.Player1ID, *match.Player2ID
+				perRound[match.Round][a]++
+				perRound[match.Round][b]++
+				pairs[[2]uint{min(a, b), max(a, b)}]++
+			}
+
+			// Every pair meets exactly once
+			assert.Len(t, pairs, players*(players-1)/2)
+			for pair, count := range pairs {
+				assert.Equal(t, 1, count, "%v meet once", pair)
+			}
+
+			// Everyone is scheduled once per round
+			assert.Len(t, perRound, rounds)
+			for round, seen := range perRound {
+				assert.Len(t, seen, players, "round %d", round)
+				for player, count := range seen {
+					assert.Equal(t, 1, count, "player %d in round %d", player, round)
+				}
+			}
+
+			// With an odd field everyone sits out exactly once
+			if players%2 == 1 {
+				assert.Len(t, byes, players)
+				for player, count := range byes {
+					assert.Equal(t, 1, count, "player %d sits out once", player)
+				}
+			} else {
+				assert.Empty(t, byes)
+			}
+		})
+	}
+}
+
+// playedMatch returns a completed match between two users
+func playedMatch(player1, player2 uint, score1, score2 int) model.Match {
+	match := model.Match{
+		Player1ID:    &player1,
+		Player2ID:    &player2,
+		Player1Score: score1,
+		Player2Score: score2,
+		Status:       model.MatchCompleted,
+	}
+	switch {
+	case score1 > score2:
+		match.WinnerID = match.Player1ID
+	case score2 > score1:
+		match.WinnerID = match.Player2ID
+	}
+	return match
+}
+
+func TestComputeStandings(t *testing.T) {
+	players := makePlayers(4)
+	matches := []model.Match{
+		playedMatch(1, 2, 1, 0),
+		playedMatch(3, 4, 2, 2),
+		playedMatch(1, 3, 0, 1),
+		playedMatch(2, 4, 5, 0),
+		playedMatch(1, 4, 3, 0),
+		playedMatch(2, 3, 1, 0),
+		// a pending match does not count
+		{Player1ID: &players[0].ID, Player2ID: &players[1].ID, Status: model.MatchPending},
+	}
+
+	standings := service.ComputeStandings(players, matches)
+	require.Len(t, standings, 4)
+
+	// Players 1 and 2 have 6 points each, 1 beat 2 head-to-head despite 2's better score difference
+	assert.Equal(t, []uint{1, 2, 3, 4}, []uint{standings[0].UserID, standings[1].UserID, standings[2].UserID, standings[3].UserID})
+	for i, standing := range standings {
+		assert.Equal(t, i+1, standing.Rank)
+	}
+
+	first := standings[0]
+	assert.Equal(t, 3, first.Played)
+	assert.Equal(t, 2, first.Wins)
+	assert.Equal(t, 0, first.Draws)
+	assert.Equal(t, 1, first.Losses)
+	assert.Equal(t, 6, first.Points)
+	assert.Equal(t, 3, first.ScoreDifference())
+
+	third := standings[2]
+	assert.Equal(t, 1, third.Wins)
+	assert.Equal(t, 1, third.Draws)
+	assert.Equal(t, 4, third.Points)
+	assert.Equal(t, 1, standings[3].Points)
+}
+
+func TestComputeStandingsScoreDifference(t *testing.T) {
+	// A three-way tie where everyone beat someone, the score difference decides
+	matches := []model.Match{
+		playedMatch(1, 2, 1, 0),
+		playedMatch(2, 3, 4, 0),
+		playedMatch(3, 1, 2, 1),
+	}
+
+	standings := service.ComputeStandings(makePlayers(3), matches)
+	assert.Equal(t, []uint{2, 1, 3}, []uint{standings[0].UserID, standings[1].UserID, standings[2].UserID})
+}
+
+func TestTournamentStandingsInRedis(t *testing.T) {
+	setupRedis(t)
+
+	standings := service.ComputeStandings(makePlayers(3), []model.Match{playedMatch(3, 1, 2, 0)})
+	require.NoError(t, crud.SaveTournamentStandings(9, standings))
+
+	saved, err := crud.GetTournamentStandings(9)
+	require.NoError(t, err)
+	assert.Equal(t, standings, saved)
+
+	// The leaderboard holds each player's points
+	leaderboard, err := crud.GetTournamentLeaderboard(9, 0, -1)
+	require.NoError(t, err)
+	require.Len(t, leaderboard, 3)
+	assert.Equal(t, uint(3), leaderboard[0].UserID)
+	assert.Equal(t, float64(3), leaderboard[0].Score)
+
+	require.NoError(t, crud.RemoveLeaderboardFromRedis(9))
+	saved, err = crud.GetTournamentStandings(9)
+	require.NoError(t, err)
+	assert.Empty(t, saved)
+}
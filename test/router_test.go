@@ -6,10 +6,12 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"tournament-app/internal/crud"
 	"tournament-app/internal/router"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func setupRouter() *gin.Engine {
@@ -17,6 +19,13 @@ func setupRouter() *gin.Engine {
 
 	router.UserRoutes(r)
 	router.TournamentRoutes(r)
+	router.MatchRoutes(r)
+	router.RankingRoutes(r)
+	router.MatchmakingRoutes(r)
+	router.TeamRoutes(r)
+	router.ClanRoutes(r)
+	router.SeasonRoutes(r)
+	router.TemplateRoutes(r)
 
 	return r
 }
@@ -24,6 +33,10 @@ func setupRouter() *gin.Engine {
 func TestRoutes(t *testing.T) {
 	router := setupRouter()
 
+	// The rank lookups answer 404 for users missing from the leaderboard
+	setupRedis(t)
+	require.NoError(t, crud.UpdateLeaderboard("1", 1000))
+
 	tests := []struct {
 		method   string
 		endpoint string
@@ -35,6 +48,8 @@ func TestRoutes(t *testing.T) {
 		{"POST", "/users", `{"name": "User3", "money": 3000, "level": 3}`},
 		{"POST", "/users", `{"name": "User4", "money": 4000, "level": 4}`},
 		{"POST", "/users", `{"name": "User5", "money": 5000, "level": 5}`},
+		{"GET", "/users/1/transactions", ""},
+		{"GET", "/users/reconcile", ""},
 
 		// Tournament routes
 		{"POST", "/tournaments", `{"name": "Tournament1", "prize": 1000}`},
@@ -44,16 +59,73 @@ func TestRoutes(t *testing.T) {
 		{"PUT", "/tournaments/2", `{"name": "Updated Tournament2", "prize": 2500}`},
 		{"GET", "/tournaments/2", ""},
 		{"GET", "/tournaments/ongoing", ""},
+		{"POST", "/tournaments/2/open", ""},
 		{"POST", "/tournaments/join", `{"tournament_id": 2, "user_id": 1}`},
+		{"POST", "/tournaments/2/leave", `{"user_id": 1}`},
+		{"POST", "/tournaments/2/start", ""},
+		{"POST", "/tournaments/2/matches", `{"player1_id": 1, "player2_id": 2, "round": 1}`},
+		{"GET", "/tournaments/2/matches", ""},
+		{"GET", "/tournaments/2/bracket", ""},
+		{"GET", "/tournaments/2/standings", ""},
+		{"POST", "/tournaments/2/rounds/next", ""},
+		{"GET", "/tournaments/2/groups", ""},
+		{"GET", "/rankings", ""},
+		{"GET", "/users/1/ratings", ""},
+		{"POST", "/matchmaking/queue", `{"user_id": 1}`},
+		{"DELETE", "/matchmaking/queue", `{"user_id": 1}`},
+		{"POST", "/matchmaking/matches/1/result", `{"player1_score": 2, "player2_score": 1}`},
+		{"GET", "/users/1/matchmaking/matches", ""},
+		{"POST", "/teams", `{"name": "Team1", "captain_id": 1, "roster_size": 2}`},
+		{"GET", "/teams", ""},
+		{"GET", "/teams/1", ""},
+		{"POST", "/teams/1/members", `{"user_id": 2}`},
+		{"DELETE", "/teams/1/members/2", ""},
+		{"POST", "/tournaments/2/teams/join", `{"team_id": 1}`},
+		{"POST", "/tournaments/2/teams/leave", `{"team_id": 1}`},
+		{"DELETE", "/teams/1", ""},
+		{"POST", "/clans", `{"name": "Clan1", "tag": "C1", "leader_id": 1}`},
+		{"GET", "/clans", ""},
+		{"GET", "/clans/rankings", ""},
+		{"GET", "/clans/1", ""},
+		{"POST", "/clans/1/invitations", `{"inviter_id": 1, "user_id": 2}`},
+		{"GET", "/users/2/clan-invitations", ""},
+		{"POST", "/clans/invitations/1/accept", `{"user_id": 2}`},
+		{"POST", "/clans/invitations/1/decline", `{"user_id": 2}`},
+		{"PUT", "/clans/1/members/2", `{"actor_id": 1, "role": "officer"}`},
+		{"POST", "/clans/1/treasury/deposit", `{"user_id": 2, "amount": 10}`},
+		{"POST", "/clans/1/treasury/payout", `{"actor_id": 1, "user_id": 2, "amount": 10}`},
+		{"POST", "/clans/1/leave", `{"user_id": 2}`},
+		{"DELETE", "/clans/1/members/2", `{"actor_id": 1}`},
+		{"DELETE", "/clans/1", `{"actor_id": 1}`},
+		{"POST", "/seasons", `{"name": "Season1", "starts_at": "2024-01-01T00:00:00Z", "ends_at": "2024-02-01T00:00:00Z", "rating_soft_reset": 0.5}`},
+		{"GET", "/seasons", ""},
+		{"GET", "/seasons/current", ""},
+		{"POST", "/seasons/rollover", ""},
+		{"GET", "/seasons/1", ""},
+		{"GET", "/seasons/1/leaderboard", ""},
+		{"POST", "/tournament-templates", `{"name_pattern": "Daily Cup {date}", "prize": 100, "recurrence": "@daily"}`},
+		{"GET", "/tournament-templates", ""},
+		{"GET", "/tournament-templates/1", ""},
+		{"PUT", "/tournament-templates/1", `{"recurrence": "0 18 * * *"}`},
+		{"GET", "/tournament-templates/1/tournaments", ""},
+		{"DELETE", "/tournament-templates/1", ""},
+		{"POST", "/tournaments/2/matches/1/result", `{"player1_score": 2, "player2_score": 1}`},
 		{"POST", "/tournaments/2/end", ""},
+		{"GET", "/tournaments/2/payouts", ""},
+		{"POST", "/tournaments/3/cancel", ""},
 		{"GET", "/tournaments", ""},
 
 		// Leaderboard routes
 		{"GET", "/leaderboard", ""},
+		{"GET", "/leaderboard?window=weekly", ""},
 		{"GET", "/leaderboard/tournament/2", ""},
 		{"GET", "/leaderboard/user/1", ""},
+		{"GET", "/leaderboard/user/1/rank", ""},
+		{"GET", "/leaderboard/user/1/around?radius=2&window=daily", ""},
 		{"GET", "/leaderboard/tournament/2/finished", ""},
+		{"POST", "/leaderboard/reconcile?dry_run=true", ""},
 		{"GET", "/leaderboard/active", ""},
+		{"GET", "/leaderboard/active?window=daily", ""},
 		{"GET", "/leaderboard/user/1/active", ""},
 		{"GET", "/leaderboard/tournament/2/active", ""},
 	}
@@ -71,7 +143,31 @@ func TestRoutes(t *testing.T) {
 			w := httptest.NewRecorder()
 			router.ServeHTTP(w, req)
 
-			assert.NotEqual(t, http.StatusNotFound, w.Code)
+			// Handlers answer 404 for missing records, only gin's own 404 means the route is not registered
+			if w.Code == http.StatusNotFound {
+				assert.NotEqual(t, "404 page not found", w.Body.String())
+			}
 		})
 	}
 }
+
+func TestTournamentErrorStatus(t *testing.T) {
+	router := setupRouter()
+
+	// Settings that fail validation are the client's fault
+	for _, body := range []string{`{"name": "", "prize": 100}`, `{"name": "Negative fee", "prize": 100, "entry_fee": -1}`} {
+		req := httptest.NewRequest("POST", "/tournaments", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code, body)
+	}
+
+	// A missing tournament is not found
+	setupPostgres(t)
+	setupRedis(t)
+	req := httptest.NewRequest("POST", "/tournaments/99/open", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
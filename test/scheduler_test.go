@@ -0,0 +1,300 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"tournament-app/internal/db"
+	"tournament-app/model"
+	"tournament-app/service"
+	"tournament-app/validation"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateSchedule(t *testing.T) {
+	at := func(hours int) *time.Time {
+		moment := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).Add(time.Duration(hours) * time.Hour)
+		return &moment
+	}
+	valid := func() model.Tournament {
+		return model.Tournament{
+			Name: "Scheduled", Prize: 100, MaxPlayers: 4, MinPlayers: 2,
+			RefundPolicy: model.RefundBeforeStart, Format: model.SingleElimination, Seeding: model.SeedByLevel, Status: model.Planned,
+		}
+	}
+
+	tournament := valid()
+	tournament.RegistrationOpensAt, tournament.RegistrationClosesAt, tournament.StartsAt, tournament.EndsAt = at(0), at(1), at(1), at(3)
+	assert.NoError(t, validation.ValidateTournament(&tournament))
+
+	// Any step may be left out, the others still have to follow each other
+	tournament = valid()
+	tournament.RegistrationOpensAt, tournament.EndsAt = at(2), at(1)
+	assert.Error(t, validation.ValidateTournament(&tournament))
+
+	tournament = valid()
+	tournament.RegistrationClosesAt, tournament.StartsAt = at(2), at(1)
+	assert.Error(t, validation.ValidateTournament(&tournament))
+
+	tournament = valid()
+	tournament.StartsAt, tournament.EndsAt = at(1), at(1)
+	assert.Error(t, validation.ValidateTournament(&tournament))
+}
+
+func TestScheduledDecisions(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	later, earlier := now.Add(time.Hour), now.Add(-time.Hour)
+	tournament := func(status model.TournamentStatus, players int, change func(*model.Tournament)) *model.Tournament {
+		tournament := &model.Tournament{Status: status, MinPlayers: 2, Format: model.SingleElimination, Users: makePlayers(players), EndsAt: &later}
+		if change != nil {
+			change(tournament)
+		}
+		return tournament
+	}
+
+	starts := []struct {
+		name       string
+		tournament *model.Tournament
+		want       model.TournamentStatus
+	}{
+		{"enough entrants", tournament(model.RegistrationOpen, 2, nil), model.Ongoing},
+		{"no end time", tournament(model.RegistrationOpen, 3, func(t *model.Tournament) { t.EndsAt = nil }), model.Ongoing},
+		{"never opened", tournament(model.Planned, 2, nil), model.Cancelled},
+		{"too few entrants", tournament(model.RegistrationOpen, 1, nil), model.Cancelled},
+		{"end time passed", tournament(model.RegistrationOpen, 2, func(t *model.Tournament) { t.EndsAt = &earlier }), model.Cancelled},
+		{"ends right now", tournament(model.RegistrationOpen, 2, func(t *model.Tournament) { t.EndsAt = &now }), model.Cancelled},
+		{"swiss rounds filled", tournament(model.RegistrationOpen, 4, func(t *model.Tournament) { t.Format, t.Rounds = model.Swiss, 3 }), model.Ongoing},
+		{"swiss rounds unfilled", tournament(model.RegistrationOpen, 3, func(t *model.Tournament) { t.Format, t.Rounds = model.Swiss, 3 }), model.Cancelled},
+		{"teams", tournament(model.RegistrationOpen, 4, func(t *model.Tournament) {
+			t.TeamEntry, t.Teams = true, []model.Team{{ID: 1}}
+		}), model.Cancelled},
+	}
+	for _, tt := range starts {
+		assert.Equal(t, tt.want, service.ScheduledStart(tt.tournament, now), tt.name)
+	}
+
+	assert.Equal(t, model.Finished, service.ScheduledEnd(tournament(model.Ongoing, 2, nil)))
+	assert.Equal(t, model.Cancelled, service.ScheduledEnd(tournament(model.Ongoing, 1, nil)), "left with too few entrants")
+}
+
+// scheduledTournament creates a tournament that opens after an hour, stops taking entries after two,
+// starts after three and ends after five
+func scheduledTournament(t *testing.T, now time.Time, name string) model.Tournament {
+	t.Helper()
+	at := func(hours int) *time.Time {
+		moment := now.Add(time.Duration(hours) * time.Hour)
+		return &moment
+	}
+	tournament := model.Tournament{
+		Name: name, Prize: 100, MaxPlayers: 4, MinPlayers: 2, EntryFee: fee(10),
+		RegistrationOpensAt: at(1), RegistrationClosesAt: at(2), StartsAt: at(3), EndsAt: at(5),
+	}
+	require.NoError(t, service.CreateTournament(&tournament))
+	return tournament
+}
+
+func TestTournamentScheduler(t *testing.T) {
+	setupPostgres(t)
+	setupRedis(t)
+
+	// Entries are checked against the real time, so the fake clock starts from it
+	clock := &fakeClock{now: time.Now()}
+	scheduler := service.NewTournamentScheduler(clock, time.Millisecond)
+	full := scheduledTournament(t, clock.Now(), "Full")
+	empty := scheduledTournament(t, clock.Now(), "Empty")
+
+	transitions, err := scheduler.RunOnce()
+	require.NoError(t, err)
+	assert.Empty(t, transitions)
+
+	clock.Advance(time.Hour)
+	transitions, err = scheduler.RunOnce()
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []service.ScheduledTransition{
+		{TournamentID: full.ID, From: model.Planned, To: model.RegistrationOpen},
+		{TournamentID: empty.ID, From: model.Planned, To: model.RegistrationOpen},
+	}, transitions)
+
+	for i := 0; i < 3; i++ {
+		user := model.User{Name: fmt.Sprintf("Player%d", i), Money: 100, Level: 1 + i}
+		require.NoError(t, service.CreateUser(&user))
+		require.NoError(t, service.JoinTournament(full.ID, user.ID))
+	}
+
+	// At starts_at the tournament with enough players starts, the other one is cancelled
+	clock.Advance(2 * time.Hour)
+	transitions, err = scheduler.RunOnce()
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []service.ScheduledTransition{
+		{TournamentID: full.ID, From: model.RegistrationOpen, To: model.Ongoing},
+		{TournamentID: empty.ID, From: model.RegistrationOpen, To: model.Cancelled},
+	}, transitions)
+	matches, err := service.GetMatchesByTournamentID(full.ID)
+	require.NoError(t, err)
+	assert.NotEmpty(t, matches)
+
+	clock.Advance(2 * time.Hour)
+	transitions, err = scheduler.RunOnce()
+	require.NoError(t, err)
+	assert.Equal(t, []service.ScheduledTransition{{TournamentID: full.ID, From: model.Ongoing, To: model.Finished}}, transitions)
+
+	// Nothing is left to do once every tournament is done
+	transitions, err = scheduler.RunOnce()
+	require.NoError(t, err)
+	assert.Empty(t, transitions)
+}
+
+func TestTournamentSchedulerCatchesUp(t *testing.T) {
+	setupPostgres(t)
+	setupRedis(t)
+
+	clock := &fakeClock{now: time.Now()}
+	missed := scheduledTournament(t, clock.Now(), "Missed")
+	startsAt := clock.Now().Add(3 * time.Hour)
+	unopened := model.Tournament{Name: "Unopened", Prize: 100, MaxPlayers: 4, MinPlayers: 2, EntryFee: fee(10), StartsAt: &startsAt}
+	require.NoError(t, service.CreateTournament(&unopened))
+
+	// A scheduler started after every step of the tournament passed opens it and then cancels it,
+	// its state comes from Postgres only
+	clock.Advance(6 * time.Hour)
+	transitions, err := service.NewTournamentScheduler(clock, time.Millisecond).RunOnce()
+	require.NoError(t, err)
+	assert.Equal(t, []service.ScheduledTransition{
+		{TournamentID: missed.ID, From: model.Planned, To: model.RegistrationOpen},
+		{TournamentID: missed.ID, From: model.RegistrationOpen, To: model.Cancelled},
+		// Without a registration time the tournament stays planned until its start and nobody could join
+		{TournamentID: unopened.ID, From: model.Planned, To: model.Cancelled},
+	}, transitions)
+
+	for _, id := range []uint{missed.ID, unopened.ID} {
+		stored, err := service.GetTournamentByID(id)
+		require.NoError(t, err)
+		assert.Equal(t, model.Cancelled, stored.Status)
+	}
+}
+
+// startedTournament runs a scheduled tournament with three players up to its start and returns it with the players
+func startedTournament(t *testing.T, scheduler *service.TournamentScheduler, clock *fakeClock) (model.Tournament, []model.User) {
+	t.Helper()
+	tournament := scheduledTournament(t, clock.Now(), "Paid")
+	clock.Advance(time.Hour)
+	_, err := scheduler.RunOnce()
+	require.NoError(t, err)
+
+	var users []model.User
+	for i := 0; i < 3; i++ {
+		user := model.User{Name: fmt.Sprintf("Player%d", i), Money: 100, Level: 1 + i}
+		require.NoError(t, service.CreateUser(&user))
+		require.NoError(t, service.JoinTournament(tournament.ID, user.ID))
+		users = append(users, user)
+	}
+
+	clock.Advance(2 * time.Hour)
+	_, err = scheduler.RunOnce()
+	require.NoError(t, err)
+	return tournament, users
+}
+
+// totalMoney returns the money the users hold together
+func totalMoney(t *testing.T, users []model.User) int {
+	t.Helper()
+	total := 0
+	for _, user := range users {
+		stored, err := service.GetUserByID(user.ID)
+		require.NoError(t, err)
+		total += stored.Money
+	}
+	return total
+}
+
+// prizePool returns what the default prize strategy pays out of the pool to the players
+func prizePool(pool, players int) int {
+	strategy, _ := service.NewPrizeStrategy(model.PrizeStrategyConfig{})
+	total := 0
+	for _, payout := range strategy.Payouts(pool, players) {
+		total += payout
+	}
+	return total
+}
+
+func TestTournamentSchedulerRetriesFinalize(t *testing.T) {
+	setupPostgres(t)
+	setupRedis(t)
+
+	clock := &fakeClock{now: time.Now()}
+	scheduler := service.NewTournamentScheduler(clock, time.Millisecond)
+	tournament, users := startedTournament(t, scheduler, clock)
+	require.Equal(t, 270, totalMoney(t, users))
+
+	// Saving the final leaderboard fails after the prizes were paid
+	require.NoError(t, db.DB.Exec(`CREATE OR REPLACE FUNCTION fail_leaderboard() RETURNS trigger AS $$
+		BEGIN RAISE EXCEPTION 'leaderboard is unavailable'; END $$ LANGUAGE plpgsql`).Error)
+	require.NoError(t, db.DB.Exec("CREATE TRIGGER fail_leaderboard BEFORE INSERT ON leaderboards FOR EACH ROW EXECUTE FUNCTION fail_leaderboard()").Error)
+	t.Cleanup(func() { db.DB.Exec("DROP TRIGGER IF EXISTS fail_leaderboard ON leaderboards") })
+
+	// Every failed pass leaves the wallets as they were and the tournament ongoing
+	clock.Advance(2 * time.Hour)
+	for i := 0; i < 2; i++ {
+		_, err := scheduler.RunOnce()
+		require.Error(t, err)
+		assert.Equal(t, 270, totalMoney(t, users))
+		stored, err := service.GetTournamentByID(tournament.ID)
+		require.NoError(t, err)
+		assert.Equal(t, model.Ongoing, stored.Status)
+	}
+
+	// Once the failure is gone the prizes are paid a single time
+	require.NoError(t, db.DB.Exec("DROP TRIGGER fail_leaderboard ON leaderboards").Error)
+	transitions, err := scheduler.RunOnce()
+	require.NoError(t, err)
+	assert.Equal(t, []service.ScheduledTransition{{TournamentID: tournament.ID, From: model.Ongoing, To: model.Finished}}, transitions)
+	assert.Equal(t, 270+prizePool(100, 3), totalMoney(t, users))
+
+	_, err = scheduler.RunOnce()
+	require.NoError(t, err)
+	assert.Equal(t, 270+prizePool(100, 3), totalMoney(t, users))
+	mismatches, err := service.ReconcileWallets()
+	require.NoError(t, err)
+	assert.Empty(t, mismatches)
+}
+
+func TestTournamentSchedulerRacesManualEnd(t *testing.T) {
+	setupPostgres(t)
+	setupRedis(t)
+
+	clock := &fakeClock{now: time.Now()}
+	scheduler := service.NewTournamentScheduler(clock, time.Millisecond)
+	tournament, users := startedTournament(t, scheduler, clock)
+
+	// The scheduler and a manual end finalize the tournament at the same time, only one of them pays
+	clock.Advance(2 * time.Hour)
+	var wg sync.WaitGroup
+	var schedulerErr, endErr error
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		_, schedulerErr = scheduler.RunOnce()
+	}()
+	go func() {
+		defer wg.Done()
+		endErr = service.EndTournament(tournament.ID)
+	}()
+	wg.Wait()
+
+	assert.False(t, schedulerErr != nil && endErr != nil, "one of the finalizations succeeds")
+	for _, err := range []error{schedulerErr, endErr} {
+		if err != nil {
+			assert.True(t, errors.Is(err, service.ErrInvalidTransition), err.Error())
+		}
+	}
+	assert.Equal(t, 270+prizePool(100, 3), totalMoney(t, users))
+
+	stored, err := service.GetTournamentByID(tournament.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.Finished, stored.Status)
+}
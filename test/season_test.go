@@ -0,0 +1,176 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"tournament-app/internal/crud"
+	"tournament-app/model"
+	"tournament-app/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNextSeason(t *testing.T) {
+	january := model.Season{
+		Name:            "January 2024",
+		StartsAt:        time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		EndsAt:          time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+		RatingSoftReset: 0.25,
+	}
+
+	// Rolled over on time the next season follows directly
+	next := service.NextSeason(january, time.Date(2024, 2, 1, 0, 5, 0, 0, time.UTC))
+	assert.Equal(t, "February 2024", next.Name)
+	assert.Equal(t, january.EndsAt, next.StartsAt)
+	assert.Equal(t, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), next.EndsAt)
+	assert.Equal(t, model.SeasonActive, next.Status)
+	assert.Equal(t, 0.25, next.RatingSoftReset)
+
+	// Months without a running season are skipped
+	late := service.NextSeason(january, time.Date(2024, 4, 10, 0, 0, 0, 0, time.UTC))
+	assert.Equal(t, "April 2024", late.Name)
+	assert.Equal(t, time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), late.EndsAt)
+}
+
+func TestSoftReset(t *testing.T) {
+	strong := model.SkillRating{Rating: 1900, RatingDeviation: 50, Volatility: 0.05}
+
+	assert.Equal(t, strong, strong.SoftReset(0))
+	assert.Equal(t, model.SkillRating{Rating: 1500, RatingDeviation: 350, Volatility: 0.05}, strong.SoftReset(1))
+
+	half := strong.SoftReset(0.5)
+	assert.InDelta(t, 1700, half.Rating, 0.0001)
+	assert.InDelta(t, 200, half.RatingDeviation, 0.0001)
+
+	weak := model.SkillRating{Rating: 1100, RatingDeviation: 350, Volatility: 0.06}
+	assert.InDelta(t, 1400, weak.SoftReset(0.75).Rating, 0.0001)
+}
+
+func TestSeasonLeaderboardInRedis(t *testing.T) {
+	setupRedis(t)
+
+	require.NoError(t, crud.AddTournamentResults(map[uint]float64{1: 4, 2: 9}, nil, 1))
+	require.NoError(t, crud.AddTournamentResults(map[uint]float64{1: 6, 3: 2}, nil, 1))
+	require.NoError(t, crud.AddTournamentResults(map[uint]float64{3: 5}, nil, 2))
+
+	standings, err := crud.GetSeasonLeaderboard(1, 0, -1)
+	require.NoError(t, err)
+	assert.Equal(t, []model.SeasonLeaderboard{
+		{SeasonID: 1, Rank: 1, UserID: 1, Score: 10},
+		{SeasonID: 1, Rank: 2, UserID: 2, Score: 9},
+		{SeasonID: 1, Rank: 3, UserID: 3, Score: 2},
+	}, standings)
+
+	// Seasons keep their own keys, removing one leaves the other
+	require.NoError(t, crud.RemoveSeasonLeaderboard(1))
+	standings, err = crud.GetSeasonLeaderboard(1, 0, -1)
+	require.NoError(t, err)
+	assert.Empty(t, standings)
+	standings, err = crud.GetSeasonLeaderboard(2, 0, -1)
+	require.NoError(t, err)
+	assert.Equal(t, []model.SeasonLeaderboard{{SeasonID: 2, Rank: 1, UserID: 3, Score: 5}}, standings)
+}
+
+func TestSeasonRollover(t *testing.T) {
+	setupPostgres(t)
+	setupRedis(t)
+
+	now := time.Now()
+	season := model.Season{Name: "Current", StartsAt: now.Add(-time.Hour), EndsAt: now.Add(time.Hour), RatingSoftReset: 0.5}
+	require.NoError(t, service.CreateSeason(&season))
+	overlapping := model.Season{Name: "Overlapping", StartsAt: now, EndsAt: now.Add(2 * time.Hour)}
+	assert.ErrorIs(t, service.CreateSeason(&overlapping), service.ErrSeasonOverlap)
+
+	user := model.User{Name: "Player", Money: 100, Level: 1, SkillRating: model.SkillRating{Rating: 1900, RatingDeviation: 50, Volatility: 0.06}}
+	require.NoError(t, service.CreateUser(&user))
+	require.NoError(t, crud.AddTournamentResults(map[uint]float64{user.ID: 7}, nil, season.ID))
+
+	// Nothing ends before the season's end
+	archived, err := service.RolloverSeasons(now)
+	require.NoError(t, err)
+	assert.Empty(t, archived)
+
+	// Reading the current season does not roll it over, the scheduler does
+	current, err := service.CurrentSeason(season.EndsAt)
+	require.NoError(t, err)
+	assert.Nil(t, current)
+	stored, err := service.GetSeasonByID(season.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.SeasonActive, stored.Status)
+
+	archived, err = service.RolloverSeasons(season.EndsAt)
+	require.NoError(t, err)
+	require.Len(t, archived, 1)
+	assert.Equal(t, model.SeasonArchived, archived[0].Status)
+
+	// The final standings are read from Postgres after the season
+	standings, err := service.GetSeasonLeaderboard(season.ID, 0, -1)
+	require.NoError(t, err)
+	require.Len(t, standings, 1)
+	assert.Equal(t, user.ID, standings[0].UserID)
+	assert.Equal(t, float64(7), standings[0].Score)
+
+	rated, err := crud.GetUserByID(user.ID)
+	require.NoError(t, err)
+	assert.InDelta(t, 1700, rated.Rating, 0.0001)
+
+	// The next season started where the last one ended
+	current, err = service.CurrentSeason(season.EndsAt)
+	require.NoError(t, err)
+	require.NotNil(t, current)
+	assert.True(t, current.StartsAt.Equal(season.EndsAt))
+}
+
+func TestTournamentFinishedAfterRollover(t *testing.T) {
+	setupPostgres(t)
+	setupRedis(t)
+
+	now := time.Now()
+	season := model.Season{Name: "Current", StartsAt: now.Add(-time.Hour), EndsAt: now.Add(time.Hour)}
+	require.NoError(t, service.CreateSeason(&season))
+
+	earlier := model.User{Name: "Earlier", Money: 100, Level: 1}
+	require.NoError(t, service.CreateUser(&earlier))
+	require.NoError(t, crud.AddTournamentResults(map[uint]float64{earlier.ID: 2}, nil, season.ID))
+
+	// The tournament is created during the season and finishes after the season was rolled over
+	tournament := model.Tournament{Name: "Late", Prize: 10, MaxPlayers: 2, MinPlayers: 2, EntryFee: fee(0), Format: model.SingleElimination}
+	require.NoError(t, service.CreateTournament(&tournament))
+	require.NotNil(t, tournament.SeasonID)
+	assert.Equal(t, season.ID, *tournament.SeasonID)
+	require.NoError(t, service.OpenRegistration(tournament.ID))
+	var users []model.User
+	for i := 0; i < 2; i++ {
+		user := model.User{Name: "Late", Money: 100, Level: 2 - i}
+		require.NoError(t, service.CreateUser(&user))
+		require.NoError(t, service.JoinTournament(tournament.ID, user.ID))
+		users = append(users, user)
+	}
+	matches, err := service.GetMatchesByTournamentID(tournament.ID)
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+	final, err := service.ReportMatchResult(tournament.ID, matches[0].ID, 2, 0)
+	require.NoError(t, err)
+
+	archived, err := service.RolloverSeasons(season.EndsAt)
+	require.NoError(t, err)
+	require.Len(t, archived, 1)
+	require.NoError(t, service.EndTournament(tournament.ID))
+
+	// The points are added to the archived standings, which are ranked again
+	standings, err := service.GetSeasonLeaderboard(season.ID, 0, -1)
+	require.NoError(t, err)
+	require.Len(t, standings, 3)
+	assert.Equal(t, *final.WinnerID, standings[0].UserID)
+	assert.Equal(t, float64(3), standings[0].Score)
+	assert.Equal(t, []int{1, 2, 3}, []int{standings[0].Rank, standings[1].Rank, standings[2].Rank})
+	assert.Equal(t, earlier.ID, standings[1].UserID)
+	assert.Equal(t, float64(2), standings[1].Score)
+
+	// The archived season's Redis leaderboard is not brought back
+	live, err := crud.GetSeasonLeaderboard(season.ID, 0, -1)
+	require.NoError(t, err)
+	assert.Empty(t, live)
+}
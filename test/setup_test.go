@@ -0,0 +1,58 @@
+package main
+
+import (
+	"os"
+	"sync"
+	"testing"
+
+	"tournament-app/internal/db"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+var (
+	redisServer *miniredis.Miniredis
+	redisOnce   sync.Once
+)
+
+// setupRedis points the app at an in-process Redis stand-in, flushed for every test
+func setupRedis(t testing.TB) *miniredis.Miniredis {
+	t.Helper()
+	redisOnce.Do(func() {
+		redisServer = miniredis.NewMiniRedis()
+		if err := redisServer.Start(); err != nil {
+			t.Fatalf("failed to start miniredis: %v", err)
+		}
+		os.Setenv("REDIS_HOST", redisServer.Host())
+		os.Setenv("REDIS_PORT", redisServer.Port())
+		db.InitRedis(0)
+	})
+	redisServer.FlushAll()
+	return redisServer
+}
+
+// setupPostgres connects to the database in POSTGRES_DSN and clears it. The test is skipped without one,
+// unless REQUIRE_POSTGRES is set as it is by the tests service of docker compose.
+func setupPostgres(t testing.TB) {
+	t.Helper()
+	dsn := os.Getenv("POSTGRES_DSN")
+	if dsn == "" && os.Getenv("REQUIRE_POSTGRES") != "" {
+		t.Fatal("POSTGRES_DSN is not set")
+	}
+	if dsn == "" {
+		t.Skip("POSTGRES_DSN is not set")
+	}
+	if db.DB == nil {
+		if err := db.InitPostgres(dsn); err != nil {
+			t.Fatalf("failed to connect to Postgres: %v", err)
+		}
+	}
+	if err := db.DB.Exec("TRUNCATE TABLE users, tournaments, tournament_users, leaderboards, transactions, matches, rating_histories, teams, clans, clan_members, clan_invitations, seasons, season_leaderboards, tournament_templates RESTART IDENTITY CASCADE").Error; err != nil {
+		t.Fatalf("failed to clear database: %v", err)
+	}
+}
+
+// fee returns an entry fee to set on a tournament
+func fee(amount int) *int {
+	return &amount
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+
+	"tournament-app/model"
+	"tournament-app/service"
+	"tournament-app/validation"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// playSwissRound reports every pending match of the round, the lower user ID winning
+func playSwissRound(matches []model.Match) {
+	for i := range matches {
+		match := &matches[i]
+		if match.Status != model.MatchPending {
+			continue
+		}
+		match.Status = model.MatchCompleted
+		if *match.Player1ID < *match.Player2ID {
+			match.Player1Score, match.WinnerID = 1, match.Player1ID
+		} else {
+			match.Player2Score, match.WinnerID = 1, match.Player2ID
+		}
+	}
+}
+
+func TestPairSwissRounds(t *testing.T) {
+	tests := []struct {
+		players int
+		rounds  int
+	}{
+		{4, 3}, {5, 4}, {8, 3}, {9, 4}, {15, 4}, {16, 4}, {33, 6},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%d players %d rounds", tt.players, tt.rounds), func(t *testing.T) {
+			players := makePlayers(tt.players)
+			var matches []model.Match
+			pairs := map[[2]uint]int{}
+			byes := map[uint]int{}
+
+			for round := 1; round <= tt.rounds; round++ {
+				pairings, err := service.PairSwissRound(5, round, players, matches)
+				require.NoError(t, err, "round %d", round)
+
+				points := map[uint]int{}
+				for _, standing := range service.ComputeSwissStandings(players, matches) {
+					points[standing.UserID] = standing.Points
+				}
+
+				seen := map[uint]bool{}
+				for _, match := range pairings {
+					assert.Equal(t, round, match.Round)
+					assert.False(t, seen[*match.Player1ID])
+					seen[*match.Player1ID] = true
+					if match.Player2ID == nil {
+						assert.Equal(t, model.MatchCompleted, match.Status)
+						byes[*match.Player1ID]++
+						continue
+					}
+					assert.False(t, seen[*match.Player2ID])
+					seen[*match.Player2ID] = true
+					a, b := *match.Player1ID, *match.Player2ID
+					pairs[[2]uint{min(a, b), max(a, b)}]++
+				}
+				assert.Len(t, seen, tt.players, "everyone is paired in round %d", round)
+
+				// An even group of unbeaten players only meets each other
+				top, unbeaten := 3*(round-1), 0
+				for _, p := range points {
+					if p == top {
+						unbeaten++
+					}
+				}
+				for _, match := range pairings {
+					if match.Player2ID != nil && points[*match.Player1ID] == top && unbeaten%2 == 0 {
+						assert.Equal(t, top, points[*match.Player2ID], "round %d: %d plays on equal points", round, *match.Player1ID)
+					}
+				}
+
+				matches = append(matches, pairings...)
+				playSwissRound(matches)
+			}
+
+			for pair, count := range pairs {
+				assert.Equal(t, 1, count, "%v meet once", pair)
+			}
+			for player, count := range byes {
+				assert.Equal(t, 1, count, "player %d gets one bye", player)
+			}
+			if tt.players%2 == 1 {
+				assert.Len(t, byes, tt.rounds)
+			} else {
+				assert.Empty(t, byes)
+			}
+
+			// The top seed wins every match
+			standings := service.ComputeSwissStandings(players, matches)
+			assert.Equal(t, uint(1), standings[0].UserID)
+			assert.Equal(t, 3*tt.rounds, standings[0].Points)
+		})
+	}
+}
+
+func TestPairSwissFirstRound(t *testing.T) {
+	matches, err := service.PairSwissRound(1, 1, makePlayers(8), nil)
+	require.NoError(t, err)
+
+	// The top half plays the bottom half: 1-5, 2-6, 3-7, 4-8
+	for i, match := range matches {
+		assert.Equal(t, uint(i+1), *match.Player1ID)
+		assert.Equal(t, uint(i+5), *match.Player2ID)
+	}
+}
+
+func TestPairSwissWithoutRematch(t *testing.T) {
+	players := makePlayers(2)
+	matches := []model.Match{playedMatch(1, 2, 1, 0)}
+	_, err := service.PairSwissRound(1, 2, players, matches)
+	assert.ErrorIs(t, err, service.ErrNoPairing)
+}
+
+func TestPairSwissGivesUp(t *testing.T) {
+	// The last ranked player already met everyone, every ordering of the others fails on them
+	players := makePlayers(24)
+	var matches []model.Match
+	for id := uint(1); id < 24; id++ {
+		matches = append(matches, playedMatch(id, 24, 1, 0))
+	}
+	_, err := service.PairSwissRound(1, 24, players, matches)
+	assert.ErrorIs(t, err, service.ErrNoPairing)
+}
+
+func TestValidateSwissRounds(t *testing.T) {
+	tournament := model.Tournament{
+		Name: "Swiss", Prize: 100, MaxPlayers: 4, MinPlayers: 2, Rounds: 3,
+		RefundPolicy: model.RefundBeforeStart, Format: model.Swiss, Seeding: model.SeedByLevel, Status: model.Planned,
+	}
+	assert.NoError(t, validation.ValidateTournament(&tournament))
+
+	// Four players run out of new opponents after three rounds
+	tournament.Rounds = 4
+	assert.Error(t, validation.ValidateTournament(&tournament))
+}
+
+func TestComputeSwissStandings(t *testing.T) {
+	bye := uint(4)
+	matches := []model.Match{
+		playedMatch(1, 2, 1, 0),
+		playedMatch(3, 4, 1, 1),
+		playedMatch(1, 3, 0, 1),
+		playedMatch(2, 4, 2, 0),
+		{Player1ID: &bye, WinnerID: &bye, ByeSlot: 2, Status: model.MatchCompleted},
+	}
+
+	standings := service.ComputeSwissStandings(makePlayers(4), matches)
+	require.Len(t, standings, 4)
+
+	rows := map[uint]model.Standing{}
+	for _, standing := range standings {
+		rows[standing.UserID] = standing
+	}
+
+	// Player 3: draw with 4 (4 points), win over 1 (3 points)
+	assert.Equal(t, 4, rows[3].Points)
+	assert.Equal(t, 7, rows[3].Buchholz)
+	assert.Equal(t, 5.0, rows[3].SonnebornBerger)
+
+	// The bye counts as a win, but adds nothing to the tiebreakers
+	assert.Equal(t, 4, rows[4].Points)
+	assert.Equal(t, 1, rows[4].Wins)
+	assert.Equal(t, 7, rows[4].Buchholz)
+
+	// Players 3 and 4 are level on points and Buchholz, Sonneborn-Berger decides
+	assert.Equal(t, uint(3), standings[0].UserID)
+	assert.Equal(t, uint(4), standings[1].UserID)
+	// Players 1 and 2 are level too, 2 beat a stronger opponent than 1 did
+	assert.Equal(t, uint(2), standings[2].UserID)
+	assert.Equal(t, uint(1), standings[3].UserID)
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+
+	"tournament-app/model"
+	"tournament-app/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createTeam creates a team of the given size with new users of 100 money, the first one is the captain
+func createTeam(t *testing.T, name string, size int) (model.Team, []model.User) {
+	t.Helper()
+	var users []model.User
+	for i := 0; i < size; i++ {
+		user := model.User{Name: fmt.Sprintf("%s%d", name, i), Money: 100, Level: 1}
+		require.NoError(t, service.CreateUser(&user))
+		users = append(users, user)
+	}
+
+	team := model.Team{Name: name, CaptainID: users[0].ID, RosterSize: size}
+	require.NoError(t, service.CreateTeam(&team))
+	for _, user := range users[1:] {
+		_, err := service.AddTeamMember(team.ID, user.ID)
+		require.NoError(t, err)
+	}
+	return team, users
+}
+
+func TestTeamRoster(t *testing.T) {
+	setupPostgres(t)
+	setupRedis(t)
+
+	team, users := createTeam(t, "Roster", 2)
+	stored, err := service.GetTeamByID(team.ID)
+	require.NoError(t, err)
+	assert.Len(t, stored.Members, 2)
+
+	extra := model.User{Name: "Extra", Money: 100, Level: 1}
+	require.NoError(t, service.CreateUser(&extra))
+	_, err = service.AddTeamMember(team.ID, extra.ID)
+	assert.ErrorIs(t, err, service.ErrTeamFull)
+	_, err = service.AddTeamMember(team.ID, users[1].ID)
+	assert.ErrorIs(t, err, service.ErrAlreadyMember)
+
+	_, err = service.RemoveTeamMember(team.ID, users[0].ID)
+	assert.ErrorIs(t, err, service.ErrCaptainLeaving)
+	_, err = service.RemoveTeamMember(team.ID, extra.ID)
+	assert.ErrorIs(t, err, service.ErrNotMember)
+	stored, err = service.RemoveTeamMember(team.ID, users[1].ID)
+	require.NoError(t, err)
+	assert.Len(t, stored.Members, 1)
+}
+
+func TestTeamTournament(t *testing.T) {
+	setupPostgres(t)
+	setupRedis(t)
+
+	tournament := model.Tournament{
+		Name: "Teams", Prize: 201, MaxPlayers: 2, MinPlayers: 2, EntryFee: fee(51), TeamEntry: true,
+		PrizeStrategy: model.PrizeStrategyConfig{Type: model.WinnerTakesAllPrize},
+	}
+	require.NoError(t, service.CreateTournament(&tournament))
+	assert.Equal(t, model.SplitTeamFee, tournament.TeamFee)
+	require.NoError(t, service.OpenRegistration(tournament.ID))
+
+	blue, blueUsers := createTeam(t, "Blue", 2)
+	red, redUsers := createTeam(t, "Red", 2)
+	assert.ErrorIs(t, service.JoinTournament(tournament.ID, blueUsers[0].ID), service.ErrTeamTournament)
+
+	// A team without a full roster cannot enter
+	_, err := service.RemoveTeamMember(red.ID, redUsers[1].ID)
+	require.NoError(t, err)
+	assert.ErrorIs(t, service.JoinTournamentAsTeam(tournament.ID, red.ID), service.ErrRosterIncomplete)
+	_, err = service.AddTeamMember(red.ID, redUsers[1].ID)
+	require.NoError(t, err)
+
+	// The captain pays the part of the fee that does not split evenly
+	require.NoError(t, service.JoinTournamentAsTeam(tournament.ID, blue.ID))
+	assert.ErrorIs(t, service.JoinTournamentAsTeam(tournament.ID, blue.ID), service.ErrAlreadyJoined)
+	for i, want := range []int{74, 75} {
+		stored, err := service.GetUserByID(blueUsers[i].ID)
+		require.NoError(t, err)
+		assert.Equal(t, want, stored.Money)
+	}
+	_, err = service.RemoveTeamMember(blue.ID, blueUsers[1].ID)
+	assert.ErrorIs(t, err, service.ErrTeamEntered)
+
+	// The second team fills the tournament, which starts with the two teams in the final
+	require.NoError(t, service.JoinTournamentAsTeam(tournament.ID, red.ID))
+	started, err := service.GetTournamentByID(tournament.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.Ongoing, started.Status)
+	assert.Len(t, started.Users, 4)
+
+	matches, err := service.GetMatchesByTournamentID(tournament.ID)
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+	assert.ElementsMatch(t, []uint{blue.ID, red.ID}, []uint{*matches[0].Player1ID, *matches[0].Player2ID})
+	blueScore, redScore := 2, 0
+	if *matches[0].Player1ID == red.ID {
+		blueScore, redScore = redScore, blueScore
+	}
+	_, err = service.ReportMatchResult(tournament.ID, matches[0].ID, blueScore, redScore)
+	require.NoError(t, err)
+
+	leaderboard, err := service.GetTournamentLeaderboard(tournament.ID, 0, -1)
+	require.NoError(t, err)
+	require.Len(t, leaderboard, 2)
+	assert.Equal(t, blue.ID, leaderboard[0].TeamID)
+	assert.Equal(t, blue.CaptainID, leaderboard[0].UserID)
+
+	// Until the tournament ends its board is active, members see the entry of their team
+	active, err := service.GetActiveLeaderboardByTournamentID(tournament.ID)
+	require.NoError(t, err)
+	require.Len(t, active, 2)
+	assert.Equal(t, blue.CaptainID, active[0].UserID)
+	entries, err := service.GetActiveLeaderboardByUserID(blueUsers[1].ID)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, blue.ID, entries[0].TeamID)
+	assert.Equal(t, leaderboard[0].Score, entries[0].Score)
+
+	// The prize is split across the winning team, teams are not rated
+	require.NoError(t, service.EndTournament(tournament.ID))
+	for i, want := range []int{74 + 101, 75 + 100} {
+		stored, err := service.GetUserByID(blueUsers[i].ID)
+		require.NoError(t, err)
+		assert.Equal(t, want, stored.Money)
+		assert.Equal(t, model.DefaultSkillRating.Rating, stored.Rating)
+	}
+	mismatches, err := service.ReconcileWallets()
+	require.NoError(t, err)
+	assert.Empty(t, mismatches)
+
+	finished, err := service.GetFinishedLeaderboardByTournamentID(tournament.ID)
+	require.NoError(t, err)
+	require.Len(t, finished, 2)
+	active, err = service.GetActiveLeaderboardByTournamentID(tournament.ID)
+	require.NoError(t, err)
+	assert.Empty(t, active)
+}
+
+func TestTeamEntryFeePaidByCaptain(t *testing.T) {
+	setupPostgres(t)
+	setupRedis(t)
+
+	tournament := model.Tournament{
+		Name: "Captains", Prize: 100, MaxPlayers: 4, MinPlayers: 2, EntryFee: fee(60),
+		TeamEntry: true, TeamFee: model.CaptainTeamFee, RefundPolicy: model.FullRefund,
+	}
+	require.NoError(t, service.CreateTournament(&tournament))
+	require.NoError(t, service.OpenRegistration(tournament.ID))
+
+	team, users := createTeam(t, "Green", 3)
+	require.NoError(t, service.JoinTournamentAsTeam(tournament.ID, team.ID))
+	for i, want := range []int{40, 100, 100} {
+		stored, err := service.GetUserByID(users[i].ID)
+		require.NoError(t, err)
+		assert.Equal(t, want, stored.Money)
+	}
+
+	// Leaving gives the captain their fee back and takes the team off the leaderboard
+	require.NoError(t, service.LeaveTournamentAsTeam(tournament.ID, team.ID))
+	assert.ErrorIs(t, service.LeaveTournamentAsTeam(tournament.ID, team.ID), service.ErrNotJoined)
+	captain, err := service.GetUserByID(users[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, 100, captain.Money)
+
+	leaderboard, err := service.GetTournamentLeaderboard(tournament.ID, 0, -1)
+	require.NoError(t, err)
+	assert.Empty(t, leaderboard)
+	require.NoError(t, service.DeleteTeam(team.ID))
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"tournament-app/model"
+	"tournament-app/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecurrence(t *testing.T) {
+	// Monday, 1 January 2024
+	monday := time.Date(2024, 1, 1, 12, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		expression string
+		after      time.Time
+		next       time.Time
+	}{
+		{"@daily", monday, time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)},
+		{"@weekly", monday, time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC)},
+		{"0 18 * * *", monday, time.Date(2024, 1, 1, 18, 0, 0, 0, time.UTC)},
+		{"*/15 9-17 * * 1-5", monday, time.Date(2024, 1, 1, 12, 45, 0, 0, time.UTC)},
+		{"*/15 9-17 * * 1-5", time.Date(2024, 1, 5, 17, 45, 0, 0, time.UTC), time.Date(2024, 1, 8, 9, 0, 0, 0, time.UTC)},
+		{"0 20 * * 0,6", monday, time.Date(2024, 1, 6, 20, 0, 0, 0, time.UTC)},
+		{"0 20 * * 7", monday, time.Date(2024, 1, 7, 20, 0, 0, 0, time.UTC)},
+		{"0 0 1 */3 *", monday, time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)},
+		// Both day fields restricted: the 15th or any Friday
+		{"0 0 15 * 5", monday, time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC)},
+		// The time itself does not count, the next one is strictly after it
+		{"30 12 * * *", monday, time.Date(2024, 1, 2, 12, 30, 0, 0, time.UTC)},
+		{"0 0 30 2 *", monday, time.Time{}},
+	}
+	for _, tt := range tests {
+		recurrence, err := model.ParseRecurrence(tt.expression)
+		require.NoError(t, err, tt.expression)
+		assert.Equal(t, tt.next, recurrence.Next(tt.after), tt.expression)
+	}
+
+	for _, expression := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "*/0 * * * *", "5-1 * * * *", "a * * * *", "@yearly"} {
+		_, err := model.ParseRecurrence(expression)
+		assert.Error(t, err, expression)
+	}
+}
+
+func TestTemplateTournament(t *testing.T) {
+	template := model.TournamentTemplate{
+		ID: 4, NamePattern: "Evening Cup {date} {time}", Prize: 500, EntryFee: fee(20), Format: model.Swiss,
+		RegistrationMinutes: 60, DurationMinutes: 90,
+	}
+	startsAt := time.Date(2024, 3, 9, 19, 0, 0, 0, time.UTC)
+
+	tournament := template.Tournament(startsAt)
+	assert.Equal(t, "Evening Cup 2024-03-09 19:00", tournament.Name)
+	assert.Equal(t, 500, tournament.Prize)
+	assert.Equal(t, model.Swiss, tournament.Format)
+	require.NotNil(t, tournament.TemplateID)
+	assert.Equal(t, uint(4), *tournament.TemplateID)
+	assert.Equal(t, startsAt.Add(-time.Hour), *tournament.RegistrationOpensAt)
+	assert.Equal(t, startsAt, *tournament.RegistrationClosesAt)
+	assert.Equal(t, startsAt, *tournament.StartsAt)
+	assert.Equal(t, startsAt.Add(90*time.Minute), *tournament.EndsAt)
+}
+
+func TestTemplateUpdate(t *testing.T) {
+	template := model.TournamentTemplate{
+		NamePattern: "Weekly", Prize: 300, EntryFee: fee(10), Format: model.DoubleElimination, GrandFinalReset: true,
+		Recurrence: "@weekly", Paused: true,
+	}
+
+	// Only the fields sent change, also when they are sent empty
+	var update model.TournamentTemplateUpdate
+	require.NoError(t, json.Unmarshal([]byte(`{"prize": 0, "grand_final_reset": false, "recurrence": "@daily"}`), &update))
+	update.Apply(&template)
+	assert.Equal(t, 0, template.Prize)
+	assert.False(t, template.GrandFinalReset)
+	assert.Equal(t, "@daily", template.Recurrence)
+	assert.Equal(t, "Weekly", template.NamePattern)
+	assert.Equal(t, fee(10), template.EntryFee)
+	assert.Equal(t, model.DoubleElimination, template.Format)
+	assert.True(t, template.Paused)
+}
+
+func TestTemplateTournaments(t *testing.T) {
+	setupPostgres(t)
+	setupRedis(t)
+
+	template := model.TournamentTemplate{NamePattern: "Hourly {time}", Prize: 100, Recurrence: "0 * * * *", CreateAheadMinutes: 180}
+	require.NoError(t, service.CreateTournamentTemplate(&template))
+	assert.Equal(t, model.DefaultDurationMinutes, template.DurationMinutes)
+
+	invalid := model.TournamentTemplate{NamePattern: "Broken", Prize: 100, Recurrence: "every day"}
+	assert.ErrorIs(t, service.CreateTournamentTemplate(&invalid), service.ErrInvalidTemplate)
+
+	// The tournaments starting within the create-ahead time are created once
+	now := time.Date(2030, 1, 1, 12, 10, 0, 0, time.UTC)
+	created, err := service.CreateTemplateTournaments(now)
+	require.NoError(t, err)
+	require.Len(t, created, 3)
+	assert.Equal(t, "Hourly 13:00", created[0].Name)
+	assert.Equal(t, model.Planned, created[0].Status)
+	assert.Equal(t, model.DefaultMaxPlayers, created[0].MaxPlayers)
+	created, err = service.CreateTemplateTournaments(now)
+	require.NoError(t, err)
+	assert.Empty(t, created)
+
+	// An hour later the next one comes up
+	created, err = service.CreateTemplateTournaments(now.Add(time.Hour))
+	require.NoError(t, err)
+	require.Len(t, created, 1)
+	assert.Equal(t, time.Date(2030, 1, 1, 16, 0, 0, 0, time.UTC), created[0].StartsAt.UTC())
+
+	// A paused template creates nothing, the settings left out of the update are kept
+	paused := true
+	updated, err := service.UpdateTournamentTemplate(template.ID, &model.TournamentTemplateUpdate{Paused: &paused})
+	require.NoError(t, err)
+	assert.True(t, updated.Paused)
+	assert.Equal(t, 100, updated.Prize)
+	assert.Equal(t, "0 * * * *", updated.Recurrence)
+	require.NotNil(t, updated.LastStartsAt)
+	assert.Equal(t, time.Date(2030, 1, 1, 16, 0, 0, 0, time.UTC), updated.LastStartsAt.UTC())
+	created, err = service.CreateTemplateTournaments(now.Add(5 * time.Hour))
+	require.NoError(t, err)
+	assert.Empty(t, created)
+
+	// Its tournaments stay linked after it is deleted
+	require.NoError(t, service.DeleteTournamentTemplate(template.ID))
+	tournaments, err := service.GetTemplateTournaments(template.ID)
+	require.NoError(t, err)
+	require.Len(t, tournaments, 4)
+	assert.Equal(t, "Hourly 16:00", tournaments[0].Name)
+}
@@ -0,0 +1,31 @@
+package validation
+
+import (
+	"errors"
+
+	"tournament-app/model"
+)
+
+func ValidateClan(clan *model.Clan) error {
+	if clan.Name == "" {
+		return errors.New("clan name cannot be empty")
+	}
+	if len(clan.Tag) > 5 {
+		return errors.New("clan tag cannot be longer than 5 characters")
+	}
+	if clan.Treasury < 0 {
+		return errors.New("clan treasury cannot be negative")
+	}
+
+	return nil
+}
+
+func ValidateClanRole(role model.ClanRole) error {
+	switch role {
+	case model.LeaderRole, model.OfficerRole, model.MemberRole:
+	default:
+		return errors.New("clan role must be one of 'leader', 'officer' or 'member'")
+	}
+
+	return nil
+}
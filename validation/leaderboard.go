@@ -22,3 +22,13 @@ func ValidateLeaderboard(leaderboard *model.Leaderboard) error {
 
 	return nil
 }
+
+func ValidateLeaderboardWindow(window model.LeaderboardWindow) error {
+	switch window {
+	case model.AllTimeWindow, model.DailyWindow, model.WeeklyWindow:
+	default:
+		return errors.New("leaderboard window must be one of 'all_time', 'daily' or 'weekly'")
+	}
+
+	return nil
+}
@@ -0,0 +1,29 @@
+package validation
+
+import (
+	"errors"
+
+	"tournament-app/model"
+)
+
+func ValidateMatch(match *model.Match) error {
+	if match.Round < 1 {
+		return errors.New("match round must be at least 1")
+	}
+	if match.TournamentID == 0 && (match.Player1ID == nil || match.Player2ID == nil) {
+		return errors.New("a matchmaking match needs both players")
+	}
+	if match.Player1ID != nil && match.Player2ID != nil && *match.Player1ID == *match.Player2ID {
+		return errors.New("match players must be different users")
+	}
+	if match.Player1Score < 0 || match.Player2Score < 0 {
+		return errors.New("match scores cannot be negative")
+	}
+	switch match.Status {
+	case model.MatchPending, model.MatchCompleted, model.MatchSkipped:
+	default:
+		return errors.New("match status must be one of 'pending', 'completed' or 'skipped'")
+	}
+
+	return nil
+}
@@ -0,0 +1,24 @@
+package validation
+
+import (
+	"errors"
+
+	"tournament-app/model"
+)
+
+func ValidateSeason(season *model.Season) error {
+	if season.Name == "" {
+		return errors.New("season name cannot be empty")
+	}
+	if season.StartsAt.IsZero() || season.EndsAt.IsZero() {
+		return errors.New("season starts_at and ends_at are required")
+	}
+	if !season.EndsAt.After(season.StartsAt) {
+		return errors.New("season must end after it starts")
+	}
+	if season.RatingSoftReset < 0 || season.RatingSoftReset > 1 {
+		return errors.New("season rating_soft_reset must be between 0 and 1")
+	}
+
+	return nil
+}
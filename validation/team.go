@@ -0,0 +1,24 @@
+package validation
+
+import (
+	"errors"
+
+	"tournament-app/model"
+)
+
+func ValidateTeam(team *model.Team) error {
+	if team.Name == "" {
+		return errors.New("team name cannot be empty")
+	}
+	if team.CaptainID == 0 {
+		return errors.New("team captain_id cannot be empty")
+	}
+	if team.RosterSize < 1 {
+		return errors.New("team roster_size must be at least 1")
+	}
+	if len(team.Members) > team.RosterSize {
+		return errors.New("team cannot have more members than its roster_size")
+	}
+
+	return nil
+}
@@ -0,0 +1,29 @@
+package validation
+
+import (
+	"errors"
+
+	"tournament-app/model"
+)
+
+// ValidateTournamentTemplate checks the template's own fields, the tournament settings it copies are checked
+// on a tournament made from it
+func ValidateTournamentTemplate(template *model.TournamentTemplate) error {
+	if template.NamePattern == "" {
+		return errors.New("tournament template name_pattern cannot be empty")
+	}
+	if _, err := model.ParseRecurrence(template.Recurrence); err != nil {
+		return err
+	}
+	if template.RegistrationMinutes < 0 {
+		return errors.New("tournament template registration_minutes cannot be negative")
+	}
+	if template.DurationMinutes < 1 {
+		return errors.New("tournament template duration_minutes must be at least 1")
+	}
+	if template.CreateAheadMinutes < 1 {
+		return errors.New("tournament template create_ahead_minutes must be at least 1")
+	}
+
+	return nil
+}
@@ -2,6 +2,8 @@ package validation
 
 import (
 	"errors"
+	"fmt"
+	"time"
 
 	"tournament-app/model"
 )
@@ -13,9 +15,122 @@ func ValidateTournament(tournament *model.Tournament) error {
 	if tournament.Prize < 0 {
 		return errors.New("tournament prize cannot be negative")
 	}
-	if tournament.Status != model.Planned && tournament.Status != model.Ongoing && tournament.Status != model.Finished {
-		return errors.New("tournament status must be either 'planned', 'ongoing', or 'finished'")
+	if tournament.MinPlayers < 1 {
+		return errors.New("tournament min_players must be at least 1")
+	}
+	if tournament.MaxPlayers < tournament.MinPlayers {
+		return errors.New("tournament max_players cannot be less than min_players")
+	}
+	if tournament.EntryFee != nil && *tournament.EntryFee < 0 {
+		return errors.New("tournament entry_fee cannot be negative")
+	}
+	switch tournament.RefundPolicy {
+	case model.RefundBeforeStart, model.FullRefund, model.NoRefund:
+	default:
+		return errors.New("tournament refund_policy must be one of 'before_start', 'full' or 'none'")
+	}
+	switch tournament.Format {
+	case model.SingleElimination, model.DoubleElimination, model.RoundRobin, model.Swiss, model.GroupStage:
+	default:
+		return errors.New("tournament format must be one of 'single_elimination', 'double_elimination', 'round_robin', 'swiss' or 'group_stage'")
+	}
+	if tournament.Rounds < 0 {
+		return errors.New("tournament rounds cannot be negative")
+	}
+	if tournament.Format == model.Swiss && tournament.Rounds > tournament.MaxPlayers-1 {
+		return errors.New("tournament rounds cannot be more than max_players - 1, the players would run out of new opponents")
+	}
+	if tournament.Format == model.GroupStage {
+		if tournament.GroupSize < 2 {
+			return errors.New("tournament group_size must be at least 2")
+		}
+		if tournament.AdvancePerGroup < 1 || tournament.AdvancePerGroup > tournament.GroupSize {
+			return errors.New("tournament advance_per_group must be between 1 and group_size")
+		}
+	}
+	if tournament.TeamEntry && tournament.TeamFee != model.SplitTeamFee && tournament.TeamFee != model.CaptainTeamFee {
+		return errors.New("tournament team_fee must be either 'split' or 'captain'")
+	}
+	if tournament.Seeding != model.SeedByLevel && tournament.Seeding != model.SeedByScore {
+		return errors.New("tournament seeding must be either 'level' or 'score'")
+	}
+	if err := validatePrizeStrategy(tournament); err != nil {
+		return err
+	}
+	if err := validateSchedule(tournament); err != nil {
+		return err
+	}
+	switch tournament.Status {
+	case model.Planned, model.RegistrationOpen, model.Ongoing, model.Finished, model.Cancelled:
+	default:
+		return errors.New("tournament status must be one of 'planned', 'registration_open', 'ongoing', 'finished' or 'cancelled'")
 	}
 
 	return nil
 }
+
+func validatePrizeStrategy(tournament *model.Tournament) error {
+	strategy := tournament.PrizeStrategy
+	switch strategy.Type {
+	case "", model.WinnerTakesAllPrize:
+	case model.PercentagePrize:
+		total := 0.0
+		for _, percentage := range strategy.Percentages {
+			if percentage < 0 {
+				return errors.New("prize strategy percentages cannot be negative")
+			}
+			total += percentage
+		}
+		if len(strategy.Percentages) == 0 || total > 100 {
+			return errors.New("prize strategy percentages must add up to at most 100")
+		}
+	case model.FixedPrize:
+		total := 0
+		for _, amount := range strategy.Amounts {
+			if amount < 0 {
+				return errors.New("prize strategy amounts cannot be negative")
+			}
+			total += amount
+		}
+		if len(strategy.Amounts) == 0 || total > tournament.Prize {
+			return errors.New("prize strategy amounts must add up to at most the tournament prize")
+		}
+	case model.TopNSplitPrize:
+		if strategy.TopN < 1 {
+			return errors.New("prize strategy top_n must be at least 1")
+		}
+	default:
+		return errors.New("prize strategy type must be one of 'percentage', 'fixed', 'winner_takes_all' or 'top_n_split'")
+	}
+
+	return nil
+}
+
+// validateSchedule checks that the scheduled steps of a tournament follow each other, any of them may be unset
+func validateSchedule(tournament *model.Tournament) error {
+	steps := []struct {
+		name string
+		at   *time.Time
+	}{
+		{"registration_opens_at", tournament.RegistrationOpensAt},
+		{"registration_closes_at", tournament.RegistrationClosesAt},
+		{"starts_at", tournament.StartsAt},
+		{"ends_at", tournament.EndsAt},
+	}
+
+	var previous *time.Time
+	var previousName string
+	for _, step := range steps {
+		if step.at == nil {
+			continue
+		}
+		if previous != nil && step.at.Before(*previous) {
+			return fmt.Errorf("tournament %s cannot be before %s", step.name, previousName)
+		}
+		previous, previousName = step.at, step.name
+	}
+	if tournament.StartsAt != nil && tournament.EndsAt != nil && !tournament.EndsAt.After(*tournament.StartsAt) {
+		return errors.New("tournament ends_at must be after starts_at")
+	}
+	return nil
+}
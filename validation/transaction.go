@@ -0,0 +1,21 @@
+package validation
+
+import (
+	"errors"
+
+	"tournament-app/model"
+)
+
+func ValidateTransaction(transaction *model.Transaction) error {
+	if transaction.UserID == 0 {
+		return errors.New("transaction user_id cannot be empty")
+	}
+	switch transaction.Reason {
+	case model.EntryFeeTransaction, model.PrizeTransaction, model.LevelUpTransaction, model.RefundTransaction, model.AdminAdjustTransaction,
+		model.ClanDepositTransaction, model.ClanPayoutTransaction:
+	default:
+		return errors.New("transaction reason must be one of 'entry_fee', 'prize', 'level_up', 'refund', 'admin_adjust', 'clan_deposit' or 'clan_payout'")
+	}
+
+	return nil
+}